@@ -28,9 +28,10 @@ import (
 )
 
 type launchTemplateInfo struct {
-	data    *ec2types.ResponseLaunchTemplateData
-	name    *string
-	version int
+	data               *ec2types.ResponseLaunchTemplateData
+	name               *string
+	version            int
+	versionDescription *string
 }
 
 // DescribeLaunchTemplates mocks the describing the launch templates
@@ -101,6 +102,7 @@ func (m *MockEC2) DescribeLaunchTemplateVersions(ctx context.Context, request *e
 			LaunchTemplateId:   aws.String(id),
 			LaunchTemplateData: ltInfo.data,
 			LaunchTemplateName: request.LaunchTemplateName,
+			VersionDescription: ltInfo.versionDescription,
 		})
 	}
 	return o, nil
@@ -124,9 +126,10 @@ func (m *MockEC2) CreateLaunchTemplate(ctx context.Context, request *ec2.CreateL
 		return nil, fmt.Errorf("duplicate LaunchTemplateId %s", id)
 	}
 	m.LaunchTemplates[id] = &launchTemplateInfo{
-		data:    responseLaunchTemplateData(request.LaunchTemplateData),
-		name:    request.LaunchTemplateName,
-		version: 1,
+		data:               responseLaunchTemplateData(request.LaunchTemplateData),
+		name:               request.LaunchTemplateName,
+		version:            1,
+		versionDescription: request.VersionDescription,
 	}
 	m.addTags(id, tagSpecificationsToTags(request.TagSpecifications, ec2types.ResourceTypeLaunchTemplate)...)
 
@@ -152,6 +155,7 @@ func (m *MockEC2) CreateLaunchTemplateVersion(ctx context.Context, request *ec2.
 			found = true
 			ltInfo.data = responseLaunchTemplateData(request.LaunchTemplateData)
 			ltInfo.version++
+			ltInfo.versionDescription = request.VersionDescription
 			ltVersion = ltInfo.version
 			ltID = id
 		}
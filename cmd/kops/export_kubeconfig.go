@@ -29,7 +29,6 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kubeconfig"
-	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
 )
@@ -130,55 +129,21 @@ func RunExportKubeconfig(ctx context.Context, f *util.Factory, out io.Writer, op
 	}
 
 	for _, cluster := range clusterList {
-		keyStore, err := clientset.KeyStore(cluster)
+		err := f.WriteKubeconfig(ctx, cluster, util.KubeconfigOptions{
+			Path:                        options.KubeConfigPath,
+			Admin:                       options.admin,
+			User:                        options.user,
+			Internal:                    options.internal,
+			UseKopsAuthenticationPlugin: options.UseKopsAuthenticationPlugin,
+		})
 		if err != nil {
 			return err
 		}
-
-		secretStore, err := clientset.SecretStore(cluster)
-		if err != nil {
-			return err
-		}
-
-		cloud, err := cloudup.BuildCloud(cluster)
-		if err != nil {
-			return err
-		}
-		conf, err := kubeconfig.BuildKubecfg(
-			ctx,
-			cluster,
-			keyStore,
-			secretStore,
-			cloud,
-			options.admin,
-			options.user,
-			options.internal,
-			f.KopsStateStore(),
-			options.UseKopsAuthenticationPlugin)
-		if err != nil {
-			return err
-		}
-
-		if err := conf.WriteKubecfg(buildPathOptions(options)); err != nil {
-			return err
-		}
 	}
 
 	return nil
 }
 
-func buildPathOptions(options *ExportKubeconfigOptions) *clientcmd.PathOptions {
-	pathOptions := clientcmd.NewDefaultPathOptions()
-
-	if len(options.KubeConfigPath) > 0 {
-		pathOptions.GlobalFile = options.KubeConfigPath
-		pathOptions.EnvVar = ""
-		pathOptions.GlobalFileSubpath = ""
-	}
-
-	return pathOptions
-}
-
 func completeKubecfgUser(cmd *cobra.Command, args []string, complete string) ([]string, cobra.ShellCompDirective) {
 	pathOptions := clientcmd.NewDefaultPathOptions()
 
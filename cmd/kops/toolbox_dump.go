@@ -22,16 +22,19 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
@@ -70,14 +73,51 @@ type ToolboxDumpOptions struct {
 	SSHUser      string
 	MaxNodes     int
 	K8sResources bool
+	Journal      string
+
+	JournaldFormat string
+
+	ClusterEvents  bool
+	PreservePaths  bool
+	FollowSymlinks bool
+	RedactConfigs  bool
+	Checksums      bool
+
+	ControllerPprofAddress string
+
+	KnownHosts            string
+	AllowUnknownHosts     bool
+	InsecureIgnoreHostKey bool
+
+	JumpHosts  []string
+	SOCKSProxy string
+
+	NodeSelector string
+	NodeTaints   []string
+
+	Inventory string
+
+	ProgressFile string
+
+	DialTimeout        time.Duration
+	BastionDialTimeout time.Duration
+	CommandTimeout     time.Duration
+
+	CaptureConcurrency int
 }
 
 func (o *ToolboxDumpOptions) InitDefaults() {
 	o.Output = OutputYaml
 	o.PrivateKey = "~/.ssh/id_rsa"
+	o.KnownHosts = "~/.ssh/known_hosts"
 	o.SSHUser = "ubuntu"
 	o.MaxNodes = 500
 	o.K8sResources = k8sResources != ""
+	o.Journal = string(dump.JournalCaptureAll)
+	o.RedactConfigs = true
+	o.DialTimeout = dump.DefaultDialTimeout
+	o.BastionDialTimeout = dump.DefaultBastionDialTimeout
+	o.CaptureConcurrency = dump.DefaultCaptureConcurrency
 }
 
 func NewCmdToolboxDump(f commandutils.Factory, out io.Writer) *cobra.Command {
@@ -106,8 +146,42 @@ func NewCmdToolboxDump(f commandutils.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVar(&options.K8sResources, "k8s-resources", options.K8sResources, "Include k8s resources in the dump")
 	cmd.Flags().IntVar(&options.MaxNodes, "max-nodes", options.MaxNodes, "The maximum number of nodes from which to dump logs")
 	cmd.Flags().StringVar(&options.PrivateKey, "private-key", options.PrivateKey, "File containing private key to use for SSH access to instances")
+	cmd.Flags().StringVar(&options.KnownHosts, "known-hosts", options.KnownHosts, "File of known SSH host keys used to verify instances")
+	cmd.MarkFlagFilename("known-hosts")
+	cmd.Flags().BoolVar(&options.AllowUnknownHosts, "allow-unknown-hosts", options.AllowUnknownHosts, "Accept instances missing from the known hosts, while still rejecting changed host keys")
+	cmd.Flags().BoolVar(&options.InsecureIgnoreHostKey, "insecure-ignore-host-key", options.InsecureIgnoreHostKey, "Accept any SSH host key from instances instead of verifying them against the known hosts; insecure")
+	cmd.Flags().StringSliceVar(&options.JumpHosts, "jump-host", options.JumpHosts, "SSH jump hosts through which the bastion is reached, in order; without a bastion, private instances are reached through the last one")
+	cmd.Flags().StringVar(&options.SOCKSProxy, "socks-proxy", options.SOCKSProxy, "SOCKS5 proxy (socks5://[user:password@]host:port) through which instances, or the jump hosts or bastion, are connected to")
 	cmd.Flags().StringVar(&options.SSHUser, "ssh-user", options.SSHUser, "The remote user for SSH access to instances")
 	cmd.RegisterFlagCompletionFunc("ssh-user", cobra.NoFileCompletions)
+	cmd.Flags().StringVar(&options.Journal, "journal", options.Journal, "Which systemd journals to collect from instances. One of all, full or services")
+	cmd.RegisterFlagCompletionFunc("journal", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var journals []string
+		for _, journal := range dump.JournalCaptures {
+			journals = append(journals, string(journal))
+		}
+		return journals, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.JournaldFormat, "journald-format", options.JournaldFormat, "Output format of journalctl for all journals collected from instances, e.g. json; by default short-precise for the full journal and cat for services")
+	cmd.RegisterFlagCompletionFunc("journald-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return dump.JournaldFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().BoolVar(&options.ClusterEvents, "cluster-events", options.ClusterEvents, "Capture the events of the whole cluster from a control-plane node")
+	cmd.Flags().BoolVar(&options.PreservePaths, "preserve-paths", options.PreservePaths, "Keep the directory structure of the log files captured from instances, instead of flattening their paths")
+	cmd.Flags().BoolVar(&options.FollowSymlinks, "follow-symlinks", options.FollowSymlinks, "Follow symlinks when searching for the log files of instances")
+	cmd.Flags().BoolVar(&options.RedactConfigs, "redact-configs", options.RedactConfigs, "Redact secrets such as tokens and passwords from the kubelet and containerd config files captured from instances")
+	cmd.Flags().BoolVar(&options.Checksums, "checksums", options.Checksums, "Write a .sha256 checksum file next to each file captured from instances")
+	cmd.Flags().StringVar(&options.ControllerPprofAddress, "kops-controller-pprof-address", options.ControllerPprofAddress, "Address (host:port) on control-plane instances of the kops-controller pprof endpoint, from which heap and goroutine profiles are captured if reachable")
+	cmd.Flags().StringVar(&options.NodeSelector, "node-selector", options.NodeSelector, "Only dump registered nodes matching this label selector")
+	cmd.Flags().StringSliceVar(&options.NodeTaints, "node-taint", options.NodeTaints, "Only dump registered nodes with a taint with one of these keys")
+	cmd.Flags().StringVar(&options.Inventory, "inventory", options.Inventory, "File listing the IPs of the instances to dump, one per line, instead of the nodes registered in Kubernetes; instances are reached through the bastion if there is one")
+	cmd.MarkFlagFilename("inventory")
+	cmd.Flags().StringVar(&options.ProgressFile, "progress-file", options.ProgressFile, "File to which progress events are written as JSON Lines while dumping nodes")
+	cmd.MarkFlagFilename("progress-file")
+	cmd.Flags().DurationVar(&options.DialTimeout, "dial-timeout", options.DialTimeout, "Timeout for connecting to instances over SSH")
+	cmd.Flags().DurationVar(&options.BastionDialTimeout, "bastion-dial-timeout", options.BastionDialTimeout, "Timeout for connecting to instances over SSH through the bastion")
+	cmd.Flags().DurationVar(&options.CommandTimeout, "command-timeout", options.CommandTimeout, "Timeout for each command run on instances, after which the capture is skipped; 0 for no timeout")
+	cmd.Flags().IntVar(&options.CaptureConcurrency, "capture-concurrency", options.CaptureConcurrency, "Number of commands run concurrently on each instance while capturing its logs")
 
 	return cmd
 }
@@ -142,6 +216,29 @@ func RunToolboxDump(ctx context.Context, f commandutils.Factory, out io.Writer,
 	}
 
 	if options.Dir != "" {
+		if !slices.Contains(dump.JournalCaptures, dump.JournalCapture(options.Journal)) {
+			return fmt.Errorf("unsupported journal capture: %q", options.Journal)
+		}
+		if options.CaptureConcurrency < 1 {
+			return fmt.Errorf("capture concurrency must be at least 1, got %d", options.CaptureConcurrency)
+		}
+
+		var nodeSelector labels.Selector
+		if options.NodeSelector != "" {
+			nodeSelector, err = labels.Parse(options.NodeSelector)
+			if err != nil {
+				return fmt.Errorf("invalid node selector %q: %w", options.NodeSelector, err)
+			}
+		}
+
+		var inventory []string
+		if options.Inventory != "" {
+			inventory, err = readInventory(options.Inventory)
+			if err != nil {
+				return err
+			}
+		}
+
 		privateKeyPath := options.PrivateKey
 		if strings.HasPrefix(privateKeyPath, "~/") {
 			privateKeyPath = filepath.Join(os.Getenv("HOME"), privateKeyPath[2:])
@@ -174,8 +271,7 @@ func RunToolboxDump(ctx context.Context, f commandutils.Factory, out io.Writer,
 			k8sClient, err := kubernetes.NewForConfig(kubeConfig)
 			if err != nil {
 				klog.Warningf("cannot build kube client for %q: %v", contextName, err)
-			} else {
-
+			} else if options.Inventory == "" {
 				nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 				if err != nil {
 					klog.Warningf("error listing nodes in cluster: %v", err)
@@ -196,7 +292,6 @@ func RunToolboxDump(ctx context.Context, f commandutils.Factory, out io.Writer,
 			Auth: []ssh.AuthMethod{
 				ssh.PublicKeys(signer),
 			},
-			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
 		}
 
 		keyRing := agent.NewKeyring()
@@ -225,23 +320,85 @@ func RunToolboxDump(ctx context.Context, f commandutils.Factory, out io.Writer,
 				}
 			}
 		}
-		dumper := dump.NewLogDumper(bastionAddress, sshConfig, keyRing, options.Dir)
-
-		var additionalIPs []string
-		var additionalPrivateIPs []string
-		for _, instance := range d.Instances {
-			if len(instance.PublicAddresses) != 0 {
-				additionalIPs = append(additionalIPs, instance.PublicAddresses[0])
-			} else if len(instance.PrivateAddresses) != 0 {
-				additionalPrivateIPs = append(additionalPrivateIPs, instance.PrivateAddresses[0])
-			} else {
-				klog.Warningf("no IP for instance %q", instance.Name)
+		dumper := dump.NewLogDumper(bastionAddress, sshConfig, keyRing, options.Dir).
+			WithJournalCapture(dump.JournalCapture(options.Journal)).
+			WithDialTimeout(options.DialTimeout, options.BastionDialTimeout).
+			WithCommandTimeout(options.CommandTimeout).
+			WithCaptureConcurrency(options.CaptureConcurrency).
+			WithJumpHosts(options.JumpHosts).
+			WithClusterEvents(options.ClusterEvents).
+			WithPreservePaths(options.PreservePaths).
+			WithFollowSymlinks(options.FollowSymlinks).
+			WithRedactedConfigs(options.RedactConfigs).
+			WithChecksums(options.Checksums).
+			WithControllerProfiles(options.ControllerPprofAddress).
+			WithNodeSelector(nodeSelector, options.NodeTaints)
+
+		dumper, err = dumper.WithJournaldFormat(options.JournaldFormat)
+		if err != nil {
+			return err
+		}
+
+		if options.InsecureIgnoreHostKey {
+			dumper, err = dumper.WithInsecureIgnoreHostKey()
+			if err != nil {
+				return err
+			}
+		} else {
+			knownHostsPath := options.KnownHosts
+			if strings.HasPrefix(knownHostsPath, "~/") {
+				knownHostsPath = filepath.Join(os.Getenv("HOME"), knownHostsPath[2:])
+			}
+			dumper, err = dumper.WithKnownHosts(knownHostsPath, options.AllowUnknownHosts)
+			if err != nil {
+				return fmt.Errorf("%w; use --insecure-ignore-host-key to skip verifying host keys", err)
+			}
+		}
+
+		if options.SOCKSProxy != "" {
+			dumper, err = dumper.WithSOCKSProxy(options.SOCKSProxy)
+			if err != nil {
+				return err
 			}
 		}
 
-		if err := dumper.DumpAllNodes(ctx, nodes, options.MaxNodes, additionalIPs, additionalPrivateIPs); err != nil {
+		if options.ProgressFile != "" {
+			progressFile, err := os.Create(options.ProgressFile)
+			if err != nil {
+				return fmt.Errorf("error creating progress file: %w", err)
+			}
+			defer progressFile.Close()
+			dumper = dumper.WithProgress(progressFile)
+		}
+
+		var results []dump.NodeDumpResult
+		if options.Inventory != "" {
+			results, err = dumper.DumpByIPs(ctx, inventory, bastionAddress != "")
+		} else {
+			var additionalIPs []string
+			var additionalPrivateIPs []string
+			for _, instance := range d.Instances {
+				if len(instance.PublicAddresses) != 0 {
+					additionalIPs = append(additionalIPs, instance.PublicAddresses[0])
+				} else if len(instance.PrivateAddresses) != 0 {
+					additionalPrivateIPs = append(additionalPrivateIPs, instance.PrivateAddresses[0])
+				} else {
+					klog.Warningf("no IP for instance %q", instance.Name)
+				}
+			}
+
+			results, err = dumper.DumpAllNodes(ctx, nodes, options.MaxNodes, additionalIPs, additionalPrivateIPs)
+		}
+		if err != nil {
 			klog.Warningf("error dumping nodes: %v", err)
 		}
+		for _, result := range results {
+			if !result.Connected {
+				klog.Warningf("could not connect to node %s: %v", result.Name, result.Err)
+			} else if result.CollectionErrors != 0 {
+				klog.Infof("dumped node %s with %d collection errors", result.Name, result.CollectionErrors)
+			}
+		}
 
 		if kubeConfig != nil && options.K8sResources {
 			dumper, err := dump.NewResourceDumper(kubeConfig, options.Output, options.Dir)
@@ -290,6 +447,31 @@ func RunToolboxDump(ctx context.Context, f commandutils.Factory, out io.Writer,
 	}
 }
 
+// readInventory reads the IPs listed in an inventory file, one per line.
+// Empty lines and lines starting with # are ignored.
+func readInventory(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading inventory %q: %w", path, err)
+	}
+
+	var ips []string
+	for i, line := range strings.Split(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if net.ParseIP(line) == nil {
+			return nil, fmt.Errorf("invalid IP %q on line %d of inventory %q", line, i+1, path)
+		}
+		ips = append(ips, line)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("inventory %q does not list any IPs", path)
+	}
+	return ips, nil
+}
+
 func truncateNodeList(nodes *corev1.NodeList, max int) error {
 	if max < 0 {
 		return errors.New("--max-nodes must be greater than zero")
@@ -17,6 +17,8 @@ limitations under the License.
 package main
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -90,6 +92,51 @@ func TestTruncateNodeList(t *testing.T) {
 	}
 }
 
+func TestReadInventory(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "ips",
+			input:    "# control plane\n10.0.0.1\n\n  10.0.0.2  \n2001:db8::1\n",
+			expected: []string{"10.0.0.1", "10.0.0.2", "2001:db8::1"},
+		},
+		{
+			name:  "invalid ip",
+			input: "10.0.0.1\nnode-1.example.com\n",
+			err:   true,
+		},
+		{
+			name:  "empty",
+			input: "# nothing\n\n",
+			err:   true,
+		},
+		{
+			name:     "crlf line endings",
+			input:    "10.0.0.1\r\n# control plane\r\n10.0.0.2\r\n",
+			expected: []string{"10.0.0.1", "10.0.0.2"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "inventory")
+			if err := os.WriteFile(path, []byte(tc.input), 0o644); err != nil {
+				t.Fatalf("error writing inventory: %v", err)
+			}
+			ips, err := readInventory(path)
+			if tc.err {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, ips)
+			}
+		})
+	}
+}
+
 func makeControlPlaneNode() corev1.Node {
 	return corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
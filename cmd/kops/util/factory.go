@@ -17,9 +17,13 @@ limitations under the License.
 package util
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	certmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -29,24 +33,45 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/transport"
 	"k8s.io/klog/v2"
 	channelscmd "k8s.io/kops/channels/pkg/cmd"
 	gceacls "k8s.io/kops/pkg/acls/gce"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/validation"
 	kopsclient "k8s.io/kops/pkg/client/clientset_generated/clientset"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/client/simple/api"
 	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
 type FactoryOptions struct {
 	RegistryPath string
+
+	// AdditionalRegistryPaths are further state stores searched, in order, for clusters not found in RegistryPath.
+	// Clusters in these state stores are read-only; new clusters are always created in RegistryPath.
+	AdditionalRegistryPaths []string
+
+	// WrapTransport, if set, wraps the http.RoundTripper of every client built by the Factory.
+	// This can be used to inject a proxy with custom authentication or to debug requests.
+	WrapTransport transport.WrapperFunc
+
+	// ReadOnly, if set, makes the clientset returned by KopsClient refuse any operation
+	// that would create, update or delete objects in the state store.
+	ReadOnly bool
+
+	// CloudCredentials, if set, selects the credentials used by Cloud, such as an AWS profile or role to assume,
+	// instead of the ones from the environment.
+	CloudCredentials cloudup.CloudCredentials
 }
 
 type Factory struct {
 	ConfigFlags genericclioptions.ConfigFlags
 	options     *FactoryOptions
-	clientset   simple.Clientset
 
 	kubernetesClient  kubernetes.Interface
 	certManagerClient certmanager.Interface
@@ -55,14 +80,31 @@ type Factory struct {
 	cachedRESTConfig *rest.Config
 	dynamicClient    dynamic.Interface
 	restMapper       *restmapper.DeferredDiscoveryRESTMapper
+
+	// mutex protects the clientset, clouds and the Kubernetes clients, along with the REST config they are built from
+	mutex     sync.Mutex
+	clientset simple.Clientset
+	clouds    map[string]fi.Cloud
 }
 
 func NewFactory(options *FactoryOptions) *Factory {
 	gceacls.Register()
 
-	return &Factory{
+	f := &Factory{
 		options: options,
 	}
+	// Wrap the config of the Kubernetes clients, including the discovery client of the RESTMapper
+	f.ConfigFlags.WrapConfigFn = f.wrapConfig
+	return f
+}
+
+// wrapConfig applies the WrapTransport option to the config of a Kubernetes client.
+// The options are only read when the config is built, as they are set from the command line flags.
+func (f *Factory) wrapConfig(config *rest.Config) *rest.Config {
+	if f.options != nil && f.options.WrapTransport != nil {
+		config.Wrap(f.options.WrapTransport)
+	}
+	return config
 }
 
 const (
@@ -77,65 +119,171 @@ Trailing slash will be trimmed.`
 )
 
 func (f *Factory) KopsClient() (simple.Clientset, error) {
-	if f.clientset == nil {
-		registryPath := f.options.RegistryPath
-		klog.V(2).Infof("state store %s", registryPath)
-		if registryPath == "" {
-			return nil, field.Required(field.NewPath("State Store"), STATE_ERROR)
-		}
-
-		// We recognize a `k8s` scheme; this might change in future so we won't document it yet
-		// In practice nobody is going to hit this accidentally, so I don't think we need a feature flag.
-		if strings.HasPrefix(registryPath, "k8s://") {
-			loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	return f.KopsClientWithContext(context.Background())
+}
 
-			configOverrides := &clientcmd.ConfigOverrides{}
+// KopsClientWithContext is like KopsClient, but stops building the clientset once ctx is done.
+// The clientset is cached, so ctx only applies to the first call that succeeds.
+func (f *Factory) KopsClientWithContext(ctx context.Context) (simple.Clientset, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 
-			if registryPath == "k8s://" {
-			} else {
-				u, err := url.Parse(registryPath)
+	if f.clientset == nil {
+		clientset, err := f.buildClientset(ctx, f.options.RegistryPath)
+		if err != nil {
+			return nil, err
+		}
+		if len(f.options.AdditionalRegistryPaths) > 0 {
+			var additional []simple.Clientset
+			for _, registryPath := range f.options.AdditionalRegistryPaths {
+				c, err := f.buildClientset(ctx, registryPath)
 				if err != nil {
-					return nil, fmt.Errorf("invalid kops server url: %q", registryPath)
+					return nil, err
 				}
-				configOverrides.CurrentContext = u.Host
+				additional = append(additional, c)
 			}
+			clientset = simple.NewCompositeClientset(clientset, additional...)
+		}
+		if f.options.ReadOnly {
+			clientset = simple.NewReadOnlyClientset(clientset)
+		}
+		f.clientset = clientset
+	}
 
-			kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
-			config, err := kubeConfig.ClientConfig()
-			if err != nil {
-				return nil, fmt.Errorf("error loading kubeconfig for %q", registryPath)
-			}
+	return f.clientset, nil
+}
 
-			kopsClient, err := kopsclient.NewForConfig(config)
-			if err != nil {
-				return nil, fmt.Errorf("error building kops API client: %v", err)
-			}
+// buildClientset builds the clientset for a single state store.
+func (f *Factory) buildClientset(ctx context.Context, registryPath string) (simple.Clientset, error) {
+	klog.V(2).Infof("state store %s", registryPath)
+	if registryPath == "" {
+		return nil, field.Required(field.NewPath("State Store"), STATE_ERROR)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-			f.clientset = api.NewRESTClientset(
-				f.VFSContext(),
-				&url.URL{
-					Scheme: "k8s",
-				},
-				kopsClient.Kops(),
-			)
+	var clientset simple.Clientset
+	// We recognize a `k8s` scheme; this might change in future so we won't document it yet
+	// In practice nobody is going to hit this accidentally, so I don't think we need a feature flag.
+	if strings.HasPrefix(registryPath, "k8s://") {
+		loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+
+		configOverrides := &clientcmd.ConfigOverrides{}
+
+		if registryPath == "k8s://" {
 		} else {
-			basePath, err := f.VFSContext().BuildVfsPath(registryPath)
+			u, err := url.Parse(registryPath)
 			if err != nil {
-				return nil, fmt.Errorf("error building path for %q: %v", registryPath, err)
+				return nil, fmt.Errorf("invalid kops server url: %q", registryPath)
 			}
+			configOverrides.CurrentContext = u.Host
+		}
 
-			if !vfs.IsClusterReadable(basePath) {
-				return nil, field.Invalid(field.NewPath("State Store"), registryPath, INVALID_STATE_ERROR)
-			}
+		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, configOverrides)
+		config, err := kubeConfig.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("error loading kubeconfig for %q", registryPath)
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if f.options.WrapTransport != nil {
+			config.Wrap(f.options.WrapTransport)
+		}
 
-			f.clientset = vfsclientset.NewVFSClientset(f.VFSContext(), basePath)
+		kopsClient, err := kopsclient.NewForConfig(config)
+		if err != nil {
+			return nil, fmt.Errorf("error building kops API client: %v", err)
+		}
+
+		clientset = api.NewRESTClientset(
+			f.VFSContext(),
+			&url.URL{
+				Scheme: "k8s",
+			},
+			kopsClient.Kops(),
+		)
+	} else {
+		basePath, err := f.VFSContext().BuildVfsPath(registryPath)
+		if err != nil {
+			return nil, fmt.Errorf("error building path for %q: %v", registryPath, err)
 		}
-		if strings.HasPrefix(registryPath, "file://") {
-			klog.Warning("The local filesystem state store is not functional for running clusters")
+		if err := ctx.Err(); err != nil {
+			return nil, err
 		}
+
+		if !vfs.IsClusterReadable(basePath) {
+			return nil, field.Invalid(field.NewPath("State Store"), registryPath, INVALID_STATE_ERROR)
+		}
+
+		clientset = vfsclientset.NewVFSClientset(f.VFSContext(), basePath)
+	}
+	if strings.HasPrefix(registryPath, "file://") {
+		klog.Warning("The local filesystem state store is not functional for running clusters")
 	}
+	return clientset, nil
+}
 
-	return f.clientset, nil
+// KubeconfigOptions configures the kubeconfig written by WriteKubeconfig
+type KubeconfigOptions struct {
+	// Path is the kubeconfig file to write; if empty, the default kubeconfig ($KUBECONFIG or ~/.kube/config) is used
+	Path string
+	// Admin, if non-zero, adds a cluster admin user credential with this lifetime
+	Admin time.Duration
+	// User is an existing user in the kubeconfig to use for the cluster context
+	User string
+	// Internal uses the internal DNS name of the cluster, bypassing the API load balancer
+	Internal bool
+	// UseKopsAuthenticationPlugin uses the kOps authentication plugin instead of a static credential
+	UseKopsAuthenticationPlugin bool
+}
+
+// WriteKubeconfig builds the kubeconfig for the cluster and merges it into the kubeconfig file.
+func (f *Factory) WriteKubeconfig(ctx context.Context, cluster *kops.Cluster, options KubeconfigOptions) error {
+	if options.Admin != 0 && options.User != "" {
+		return fmt.Errorf("cannot use both an admin user and an existing user")
+	}
+
+	clientset, err := f.KopsClientWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+	cloud, err := f.Cloud(cluster)
+	if err != nil {
+		return err
+	}
+
+	conf, err := kubeconfig.BuildKubecfg(
+		ctx,
+		cluster,
+		keyStore,
+		secretStore,
+		cloud,
+		options.Admin,
+		options.User,
+		options.Internal,
+		f.KopsStateStore(),
+		options.UseKopsAuthenticationPlugin)
+	if err != nil {
+		return err
+	}
+
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	if options.Path != "" {
+		pathOptions.GlobalFile = options.Path
+		pathOptions.EnvVar = ""
+		pathOptions.GlobalFileSubpath = ""
+	}
+	return conf.WriteKubecfg(pathOptions)
 }
 
 // KopsStateStore returns the configured KOPS_STATE_STORE in use
@@ -145,6 +293,7 @@ func (f *Factory) KopsStateStore() string {
 
 var _ channelscmd.Factory = &Factory{}
 
+// restConfig returns the config the Kubernetes clients are built from; the caller must hold the mutex.
 func (f *Factory) restConfig() (*rest.Config, error) {
 	if f.cachedRESTConfig == nil {
 		restConfig, err := f.ConfigFlags.ToRESTConfig()
@@ -160,6 +309,9 @@ func (f *Factory) restConfig() (*rest.Config, error) {
 }
 
 func (f *Factory) KubernetesClient() (kubernetes.Interface, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	if f.kubernetesClient == nil {
 		restConfig, err := f.restConfig()
 		if err != nil {
@@ -176,6 +328,9 @@ func (f *Factory) KubernetesClient() (kubernetes.Interface, error) {
 }
 
 func (f *Factory) DynamicClient() (dynamic.Interface, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	if f.dynamicClient == nil {
 		restConfig, err := f.restConfig()
 		if err != nil {
@@ -192,6 +347,9 @@ func (f *Factory) DynamicClient() (dynamic.Interface, error) {
 }
 
 func (f *Factory) CertManagerClient() (certmanager.Interface, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	if f.certManagerClient == nil {
 		restConfig, err := f.restConfig()
 		if err != nil {
@@ -208,6 +366,9 @@ func (f *Factory) CertManagerClient() (certmanager.Interface, error) {
 }
 
 func (f *Factory) RESTMapper() (*restmapper.DeferredDiscoveryRESTMapper, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
 	if f.restMapper == nil {
 		discoveryClient, err := f.ConfigFlags.ToDiscoveryClient()
 		if err != nil {
@@ -229,3 +390,103 @@ func (f *Factory) VFSContext() *vfs.VFSContext {
 	}
 	return f.vfsContext
 }
+
+// GetCluster reads the cluster from the state store and validates its spec.
+// It only needs access to the state store, and neither builds a cloud nor connects to the cluster.
+func (f *Factory) GetCluster(ctx context.Context, name string) (*kops.Cluster, error) {
+	if name == "" {
+		return nil, field.Required(field.NewPath("clusterName"), "Cluster name is required")
+	}
+
+	clientset, err := f.KopsClientWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cluster configuration: %w", err)
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster %q not found", name)
+	}
+	if cluster.ObjectMeta.Name != name {
+		return nil, fmt.Errorf("cluster name did not match expected name: %v vs %v", name, cluster.ObjectMeta.Name)
+	}
+
+	if errs := validation.ValidateCluster(cluster, false, f.VFSContext()); len(errs) != 0 {
+		return nil, fmt.Errorf("cluster %q is not valid: %w", name, errs.ToAggregate())
+	}
+	return cluster, nil
+}
+
+// Cloud returns the cloud for the cluster.
+// Clouds are cached by cluster name and cloud provider configuration, so that callers
+// sharing the Factory do not need to build the cloud again.
+func (f *Factory) Cloud(cluster *kops.Cluster) (fi.Cloud, error) {
+	key, err := cloudCacheKey(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if cloud, found := f.clouds[key]; found {
+		return cloud, nil
+	}
+
+	cloud, err := cloudup.BuildCloudWithCredentials(cluster, f.options.CloudCredentials)
+	if err != nil {
+		return nil, err
+	}
+	if f.clouds == nil {
+		f.clouds = make(map[string]fi.Cloud)
+	}
+	f.clouds[key] = cloud
+	return cloud, nil
+}
+
+// InvalidateCluster drops the clouds cached for the cluster, so that the next call builds them again,
+// for instance after the cloud provider configuration of the cluster changed.
+// The cloud provider packages keep their own caches, such as the AWS clouds shared per region and credentials,
+// so this does not pick up changed credentials.
+func (f *Factory) InvalidateCluster(name string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for key := range f.clouds {
+		if strings.HasPrefix(key, name+"|") {
+			delete(f.clouds, key)
+		}
+	}
+}
+
+// InvalidateAll drops all clouds and Kubernetes clients cached by the Factory, so that the next call builds them again.
+// The Kubernetes clients are built from the kubeconfig rather than for a cluster, so they are only dropped here,
+// for instance after the certificates of the cluster were rotated and the kubeconfig was exported again.
+func (f *Factory) InvalidateAll() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.clouds = nil
+	f.cachedRESTConfig = nil
+	f.kubernetesClient = nil
+	f.dynamicClient = nil
+	f.certManagerClient = nil
+	f.restMapper = nil
+}
+
+// cloudCacheKey returns the key identifying the cloud built for the cluster.
+// It covers the fields cloudup.BuildCloud uses, including the subnets, from which the region is derived.
+func cloudCacheKey(cluster *kops.Cluster) (string, error) {
+	var zones []string
+	for _, subnet := range cluster.Spec.Networking.Subnets {
+		zones = append(zones, subnet.Region+"/"+subnet.Zone)
+	}
+	providerConfig, err := json.Marshal(cluster.Spec.CloudProvider)
+	if err != nil {
+		return "", fmt.Errorf("error serializing cloud provider config: %w", err)
+	}
+	return cluster.ObjectMeta.Name + "|" + strings.Join(zones, ",") + "|" + string(providerConfig), nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/testutils"
+)
+
+func TestGetCluster(t *testing.T) {
+	t.Setenv("SKIP_REGION_CHECK", "1")
+	testutils.NewIntegrationTestHarness(t).SetupMockAWS()
+
+	ctx := context.Background()
+
+	factory := NewFactory(&FactoryOptions{RegistryPath: "memfs://tests"})
+	clientset, err := factory.KopsClient()
+	if err != nil {
+		t.Fatalf("could not create clientset: %v", err)
+	}
+	if _, err := clientset.CreateCluster(ctx, testutils.BuildMinimalCluster("test.k8s.io")); err != nil {
+		t.Fatalf("could not create cluster: %v", err)
+	}
+
+	cluster, err := factory.GetCluster(ctx, "test.k8s.io")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cluster.ObjectMeta.Name != "test.k8s.io" {
+		t.Errorf("expected cluster test.k8s.io, got %q", cluster.ObjectMeta.Name)
+	}
+
+	if _, err := factory.GetCluster(ctx, "missing.k8s.io"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected not found error, got %v", err)
+	}
+	if _, err := factory.GetCluster(ctx, ""); err == nil {
+		t.Errorf("expected error for empty cluster name")
+	}
+}
+
+func TestKopsClientConcurrent(t *testing.T) {
+	factory := NewFactory(&FactoryOptions{RegistryPath: "memfs://tests"})
+
+	var wg sync.WaitGroup
+	clientsets := make([]simple.Clientset, 10)
+	for i := range clientsets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			clientset, err := factory.KopsClient()
+			if err != nil {
+				t.Errorf("could not create clientset: %v", err)
+				return
+			}
+			clientsets[i] = clientset
+		}(i)
+	}
+	wg.Wait()
+
+	for i, clientset := range clientsets {
+		if clientset != clientsets[0] {
+			t.Errorf("expected the same clientset to be shared, got a different one at %d", i)
+		}
+	}
+}
+
+func TestInvalidateCluster(t *testing.T) {
+	t.Setenv("SKIP_REGION_CHECK", "1")
+	testutils.NewIntegrationTestHarness(t).SetupMockAWS()
+
+	factory := NewFactory(&FactoryOptions{RegistryPath: "memfs://tests"})
+	clusters := []*kops.Cluster{
+		testutils.BuildMinimalCluster("a.k8s.io"),
+		testutils.BuildMinimalCluster("b.k8s.io"),
+	}
+	for _, cluster := range clusters {
+		if _, err := factory.Cloud(cluster); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(factory.clouds) != 2 {
+		t.Fatalf("expected 2 cached clouds, got %d", len(factory.clouds))
+	}
+
+	factory.InvalidateCluster("a.k8s.io")
+	if len(factory.clouds) != 1 {
+		t.Fatalf("expected 1 cached cloud, got %d", len(factory.clouds))
+	}
+	for key := range factory.clouds {
+		if !strings.HasPrefix(key, "b.k8s.io|") {
+			t.Errorf("expected the cloud of b.k8s.io to be cached, got %q", key)
+		}
+	}
+
+	if _, err := factory.Cloud(clusters[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(factory.clouds) != 2 {
+		t.Errorf("expected the cloud of a.k8s.io to be built again, got %d cached clouds", len(factory.clouds))
+	}
+
+	factory.InvalidateAll()
+	if len(factory.clouds) != 0 {
+		t.Errorf("expected no cached clouds, got %d", len(factory.clouds))
+	}
+}
+
+func TestWrapTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(kubeconfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: %s
+contexts:
+- name: test
+  context:
+    cluster: test
+current-context: test
+`, server.URL)), 0o600); err != nil {
+		t.Fatalf("error writing kubeconfig: %v", err)
+	}
+
+	var mutex sync.Mutex
+	var paths []string
+	factory := NewFactory(&FactoryOptions{
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				mutex.Lock()
+				paths = append(paths, req.URL.Path)
+				mutex.Unlock()
+				return rt.RoundTrip(req)
+			})
+		},
+	})
+	cacheDir := t.TempDir()
+	factory.ConfigFlags.KubeConfig = &kubeconfig
+	factory.ConfigFlags.CacheDir = &cacheDir
+
+	ctx := context.Background()
+	sawRequest := func(path string) bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, p := range paths {
+			if p == path {
+				return true
+			}
+		}
+		return false
+	}
+
+	kubernetesClient, err := factory.KubernetesClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = kubernetesClient.CoreV1().Namespaces().Get(ctx, "rest", metav1.GetOptions{})
+	if !sawRequest("/api/v1/namespaces/rest") {
+		t.Errorf("expected the request of the kubernetes client to be wrapped, got %v", paths)
+	}
+
+	dynamicClient, err := factory.DynamicClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = dynamicClient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}).Get(ctx, "dynamic", metav1.GetOptions{})
+	if !sawRequest("/api/v1/namespaces/dynamic") {
+		t.Errorf("expected the request of the dynamic client to be wrapped, got %v", paths)
+	}
+
+	restMapper, err := factory.RESTMapper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = restMapper.KindFor(schema.GroupVersionResource{Resource: "namespaces"})
+	if !sawRequest("/api") {
+		t.Errorf("expected the requests of the discovery client to be wrapped, got %v", paths)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
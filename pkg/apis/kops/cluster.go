@@ -227,6 +227,9 @@ type AWSSpec struct {
 	// Manager to assign to each ELB provisioned for a Service, instead of creating
 	// one per ELB.
 	ElbSecurityGroup *string `json:"elbSecurityGroup,omitempty"`
+	// VolumeEncryptionKey is the KMS key used by default to encrypt the EBS volumes of the instances.
+	// Volumes setting their own encryption key, or not being encrypted, are not affected.
+	VolumeEncryptionKey *string `json:"volumeEncryptionKey,omitempty"`
 
 	// Spotinst cloud-config specs
 	SpotinstProduct     *string `json:"spotinstProduct,omitempty"`
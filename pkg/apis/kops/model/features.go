@@ -20,6 +20,7 @@ import (
 	"github.com/blang/semver/v4"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/k8sversion"
 )
 
 // UseChallengeCallback is true if we should use a callback challenge during node provisioning with kops-controller.
@@ -54,6 +55,60 @@ func UseKopsControllerForNodeConfig(cluster *kops.Cluster) bool {
 	return true
 }
 
+// UsesKubeProxyReplacement is true if the CNI replaces kube-proxy, so that kube-proxy should not be deployed.
+func UsesKubeProxyReplacement(cluster *kops.Cluster) bool {
+	networking := cluster.Spec.Networking
+	switch {
+	case networking.Cilium != nil:
+		// Cilium replaces kube-proxy with its BPF implementation of services
+		return networking.Cilium.EnableNodePort
+	case networking.KubeRouter != nil:
+		// kube-router always implements services itself
+		return true
+	default:
+		return false
+	}
+}
+
+// UseExternalCloudProvider is true if the cloud-specific control loops run in an external (out-of-tree)
+// cloud controller manager, and kubernetes components should be configured with --cloud-provider=external.
+func UseExternalCloudProvider(cluster *kops.Cluster) bool {
+	if cluster.Spec.ExternalCloudControllerManager != nil {
+		return true
+	}
+
+	switch cluster.GetCloudProvider() {
+	case kops.CloudProviderAWS:
+		// The in-tree AWS cloud provider was removed in kubernetes 1.27
+		return cluster.IsKubernetesGTE("1.27")
+	case kops.CloudProviderAzure:
+		// The in-tree Azure cloud provider was removed in kubernetes 1.30
+		return cluster.IsKubernetesGTE("1.30")
+	case kops.CloudProviderGCE:
+		// The in-tree GCE cloud provider was removed in kubernetes 1.31
+		return cluster.IsKubernetesGTE("1.31")
+	case kops.CloudProviderOpenstack:
+		// The in-tree OpenStack cloud provider was removed in kubernetes 1.26
+		return cluster.IsKubernetesGTE("1.26")
+	case kops.CloudProviderDO, kops.CloudProviderHetzner, kops.CloudProviderScaleway, kops.CloudProviderMetal:
+		// These clouds never had an in-tree cloud provider
+		return true
+	default:
+		return false
+	}
+}
+
+// UseAWSLoadBalancerController is true if kOps should deploy the AWS Load Balancer Controller,
+// which then provisions load balancers for Services and Ingresses instead of the cloud controller manager.
+func UseAWSLoadBalancerController(cluster *kops.Cluster) bool {
+	if cluster.GetCloudProvider() != kops.CloudProviderAWS {
+		return false
+	}
+
+	lbc := cluster.Spec.CloudProvider.AWS.LoadBalancerController
+	return lbc != nil && lbc.Enabled != nil && *lbc.Enabled
+}
+
 // UseCiliumEtcd is true if we are using the Cilium etcd cluster.
 func UseCiliumEtcd(cluster *kops.Cluster) bool {
 	if cluster.Spec.Networking.Cilium == nil {
@@ -80,3 +135,48 @@ func UseExternalKubeletCredentialProvider(k8sVersion semver.Version, cloudProvid
 		return false
 	}
 }
+
+// UsePodSecurityAdmission is true if pod security is enforced by the PodSecurity admission plugin,
+// which is enabled by default from Kubernetes 1.23 and replaces PodSecurityPolicy, removed in Kubernetes 1.25.
+func UsePodSecurityAdmission(k8sVersion *k8sversion.KubernetesVersion) bool {
+	return k8sVersion.IsGTE("1.23")
+}
+
+// DefaultPodSecurityAdmissionLevel returns the pod security level enforced by the PodSecurity admission plugin
+// in namespaces without a level of their own, or an empty string if the plugin is not used.
+func DefaultPodSecurityAdmissionLevel(k8sVersion *k8sversion.KubernetesVersion) string {
+	if !UsePodSecurityAdmission(k8sVersion) {
+		return ""
+	}
+	// Kubernetes enforces no restrictions unless configured otherwise
+	return "privileged"
+}
+
+// IsDualStack is true if pods get IPv6 addresses while the nodes also have IPv4 addresses.
+func IsDualStack(cluster *kops.Cluster) bool {
+	if !cluster.Spec.IsIPv6Only() {
+		return false
+	}
+	for _, subnet := range cluster.Spec.Networking.Subnets {
+		if subnet.CIDR != "" && subnet.IPv6CIDR != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// UseIPv6Masquerade is true if the CNI should masquerade IPv6 traffic leaving the node behind the node address.
+// This is needed in dual-stack clusters where the pod IPv6 addresses are not routed by the cloud.
+func UseIPv6Masquerade(cluster *kops.Cluster) bool {
+	if !IsDualStack(cluster) {
+		return false
+	}
+
+	switch cluster.GetCloudProvider() {
+	case kops.CloudProviderAWS:
+		// Pods get their IPv6 addresses from the prefix assigned to their node, which the VPC routes
+		return false
+	default:
+		return true
+	}
+}
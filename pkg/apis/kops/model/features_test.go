@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/k8sversion"
 )
 
 func TestUseCiliumEtcd(t *testing.T) {
@@ -80,3 +81,291 @@ func TestUseCiliumEtcd(t *testing.T) {
 		}
 	}
 }
+
+func TestUseIPv6Masquerade(t *testing.T) {
+	dualStackSubnets := []kops.ClusterSubnetSpec{
+		{
+			Name:     "us-test-1a",
+			Type:     kops.SubnetTypePrivate,
+			IPv6CIDR: "2001:db8:0:111::/64",
+		},
+		{
+			Name:     "dualstack-us-test-1a",
+			Type:     kops.SubnetTypeDualStack,
+			CIDR:     "172.20.32.0/19",
+			IPv6CIDR: "2001:db8:0:113::/64",
+		},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		cluster  *kops.Cluster
+		expected bool
+	}{
+		{
+			name: "dual-stack cilium on aws",
+			cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						AWS: &kops.AWSSpec{},
+					},
+					Networking: kops.NetworkingSpec{
+						NonMasqueradeCIDR: "::/0",
+						Subnets:           dualStackSubnets,
+						Cilium:            &kops.CiliumNetworkingSpec{},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "dual-stack cilium on gce",
+			cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						GCE: &kops.GCESpec{},
+					},
+					Networking: kops.NetworkingSpec{
+						NonMasqueradeCIDR: "::/0",
+						Subnets:           dualStackSubnets,
+						Cilium:            &kops.CiliumNetworkingSpec{},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "ipv4 cilium on aws",
+			cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						AWS: &kops.AWSSpec{},
+					},
+					Networking: kops.NetworkingSpec{
+						NonMasqueradeCIDR: "100.64.0.0/10",
+						Subnets: []kops.ClusterSubnetSpec{
+							{
+								Name: "us-test-1a",
+								Type: kops.SubnetTypePublic,
+								CIDR: "172.20.32.0/19",
+							},
+						},
+						Cilium: &kops.CiliumNetworkingSpec{},
+					},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "ipv6 cilium without ipv4 subnets on aws",
+			cluster: &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: kops.CloudProviderSpec{
+						AWS: &kops.AWSSpec{},
+					},
+					Networking: kops.NetworkingSpec{
+						NonMasqueradeCIDR: "::/0",
+						Subnets:           dualStackSubnets[:1],
+						Cilium:            &kops.CiliumNetworkingSpec{},
+					},
+				},
+			},
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := UseIPv6Masquerade(tc.cluster)
+			if actual != tc.expected {
+				t.Errorf("expected %v, but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUseExternalCloudProvider(t *testing.T) {
+	aws := kops.CloudProviderSpec{AWS: &kops.AWSSpec{}}
+	azure := kops.CloudProviderSpec{Azure: &kops.AzureSpec{}}
+	gce := kops.CloudProviderSpec{GCE: &kops.GCESpec{}}
+	openstack := kops.CloudProviderSpec{Openstack: &kops.OpenstackSpec{}}
+	hetzner := kops.CloudProviderSpec{Hetzner: &kops.HetznerSpec{}}
+
+	for _, tc := range []struct {
+		name                           string
+		clusterName                    string
+		cloudProvider                  kops.CloudProviderSpec
+		kubernetesVersion              string
+		externalCloudControllerManager *kops.CloudControllerManagerConfig
+		expected                       bool
+	}{
+		{name: "aws before removal", cloudProvider: aws, kubernetesVersion: "1.26.9", expected: false},
+		{name: "aws at removal", cloudProvider: aws, kubernetesVersion: "1.27.0", expected: true},
+		{name: "azure before removal", cloudProvider: azure, kubernetesVersion: "1.29.5", expected: false},
+		{name: "azure at removal", cloudProvider: azure, kubernetesVersion: "1.30.0", expected: true},
+		{name: "gce before removal", cloudProvider: gce, kubernetesVersion: "1.30.2", expected: false},
+		{name: "gce at removal", cloudProvider: gce, kubernetesVersion: "1.31.0", expected: true},
+		{name: "openstack before removal", cloudProvider: openstack, kubernetesVersion: "1.25.0", expected: false},
+		{name: "openstack at removal", cloudProvider: openstack, kubernetesVersion: "1.26.0", expected: true},
+		{name: "hetzner has no in-tree provider", cloudProvider: hetzner, kubernetesVersion: "1.25.0", expected: true},
+		{
+			name:                           "aws with external ccm before removal",
+			cloudProvider:                  aws,
+			kubernetesVersion:              "1.26.0",
+			externalCloudControllerManager: &kops.CloudControllerManagerConfig{},
+			expected:                       true,
+		},
+		{
+			name:                           "gce with external ccm before removal",
+			cloudProvider:                  gce,
+			kubernetesVersion:              "1.29.0",
+			externalCloudControllerManager: &kops.CloudControllerManagerConfig{},
+			expected:                       true,
+		},
+		{
+			name:              "gce with legacy gossip before removal",
+			clusterName:       "test.k8s.local",
+			cloudProvider:     gce,
+			kubernetesVersion: "1.30.0",
+			expected:          false,
+		},
+		{
+			name:              "gce with legacy gossip at removal",
+			clusterName:       "test.k8s.local",
+			cloudProvider:     gce,
+			kubernetesVersion: "1.31.0",
+			expected:          true,
+		},
+		{
+			name:              "aws with legacy gossip at removal",
+			clusterName:       "test.k8s.local",
+			cloudProvider:     aws,
+			kubernetesVersion: "1.27.0",
+			expected:          true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider:                  tc.cloudProvider,
+					ExternalCloudControllerManager: tc.externalCloudControllerManager,
+					KubernetesVersion:              tc.kubernetesVersion,
+				},
+			}
+			cluster.Name = tc.clusterName
+			if cluster.Name == "" {
+				cluster.Name = "test.example.com"
+			}
+			if tc.clusterName != "" && !cluster.UsesLegacyGossip() {
+				t.Fatalf("expected cluster %q to use legacy gossip", cluster.Name)
+			}
+
+			actual := UseExternalCloudProvider(cluster)
+			if actual != tc.expected {
+				t.Errorf("expected %v, but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUseAWSLoadBalancerController(t *testing.T) {
+	enabled, disabled := true, false
+
+	for _, tc := range []struct {
+		name          string
+		cloudProvider kops.CloudProviderSpec
+		expected      bool
+	}{
+		{
+			name:          "aws without controller",
+			cloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			expected:      false,
+		},
+		{
+			name: "aws with controller unset",
+			cloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{
+				LoadBalancerController: &kops.LoadBalancerControllerSpec{},
+			}},
+			expected: false,
+		},
+		{
+			name: "aws with controller disabled",
+			cloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{
+				LoadBalancerController: &kops.LoadBalancerControllerSpec{Enabled: &disabled},
+			}},
+			expected: false,
+		},
+		{
+			name: "aws with controller enabled",
+			cloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{
+				LoadBalancerController: &kops.LoadBalancerControllerSpec{Enabled: &enabled},
+			}},
+			expected: true,
+		},
+		{
+			name:          "gce",
+			cloudProvider: kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			expected:      false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kops.Cluster{
+				Spec: kops.ClusterSpec{
+					CloudProvider: tc.cloudProvider,
+				},
+			}
+
+			actual := UseAWSLoadBalancerController(cluster)
+			if actual != tc.expected {
+				t.Errorf("expected %v, but got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUsesKubeProxyReplacement(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		networking kops.NetworkingSpec
+		expected   bool
+	}{
+		{name: "cilium with kube-proxy replacement", networking: kops.NetworkingSpec{Cilium: &kops.CiliumNetworkingSpec{EnableNodePort: true}}, expected: true},
+		{name: "cilium without kube-proxy replacement", networking: kops.NetworkingSpec{Cilium: &kops.CiliumNetworkingSpec{}}, expected: false},
+		{name: "kube-router", networking: kops.NetworkingSpec{KubeRouter: &kops.KuberouterNetworkingSpec{}}, expected: true},
+		{name: "calico", networking: kops.NetworkingSpec{Calico: &kops.CalicoNetworkingSpec{}}, expected: false},
+		{name: "kubenet", networking: kops.NetworkingSpec{Kubenet: &kops.KubenetNetworkingSpec{}}, expected: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kops.Cluster{Spec: kops.ClusterSpec{Networking: tc.networking}}
+			if actual := UsesKubeProxyReplacement(cluster); actual != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUsePodSecurityAdmission(t *testing.T) {
+	for _, tc := range []struct {
+		version       string
+		expected      bool
+		expectedLevel string
+	}{
+		{version: "1.21.14", expected: false, expectedLevel: ""},
+		{version: "1.22.0", expected: false, expectedLevel: ""},
+		{version: "1.23.0", expected: true, expectedLevel: "privileged"},
+		{version: "1.24.17", expected: true, expectedLevel: "privileged"},
+		{version: "1.25.0", expected: true, expectedLevel: "privileged"},
+		{version: "v1.31.1", expected: true, expectedLevel: "privileged"},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			k8sVersion, err := k8sversion.Parse(tc.version)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual := UsePodSecurityAdmission(k8sVersion); actual != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+			if actual := DefaultPodSecurityAdmissionLevel(k8sVersion); actual != tc.expectedLevel {
+				t.Errorf("expected level %q, got %q", tc.expectedLevel, actual)
+			}
+		})
+	}
+}
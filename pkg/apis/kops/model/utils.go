@@ -48,3 +48,21 @@ func FindZonesForInstanceGroup(c *kops.Cluster, ig *kops.InstanceGroup) ([]strin
 	}
 	return zones.List(), nil
 }
+
+// InstanceGroupSize returns the minimum and maximum size of an instance group.
+// If they are not set, node instance groups default to 2 instances and other instance groups to 1 instance.
+func InstanceGroupSize(ig *kops.InstanceGroup) (minSize int32, maxSize int32) {
+	defaultSize := int32(1)
+	if ig.Spec.Role == kops.InstanceGroupRoleNode {
+		defaultSize = 2
+	}
+	minSize = defaultSize
+	if ig.Spec.MinSize != nil {
+		minSize = *ig.Spec.MinSize
+	}
+	maxSize = defaultSize
+	if ig.Spec.MaxSize != nil {
+		maxSize = *ig.Spec.MaxSize
+	}
+	return minSize, maxSize
+}
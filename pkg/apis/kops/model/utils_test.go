@@ -173,3 +173,52 @@ func Test_FindZonesForInstanceGroup(t *testing.T) {
 		}
 	}
 }
+
+func Test_InstanceGroupSize(t *testing.T) {
+	zero, three, ten := int32(0), int32(3), int32(10)
+
+	grid := []struct {
+		role        kops.InstanceGroupRole
+		minSize     *int32
+		maxSize     *int32
+		expectedMin int32
+		expectedMax int32
+	}{
+		{
+			role:        kops.InstanceGroupRoleNode,
+			expectedMin: 2,
+			expectedMax: 2,
+		},
+		{
+			role:        kops.InstanceGroupRoleControlPlane,
+			expectedMin: 1,
+			expectedMax: 1,
+		},
+		{
+			role:        kops.InstanceGroupRoleNode,
+			minSize:     &zero,
+			maxSize:     &ten,
+			expectedMin: 0,
+			expectedMax: 10,
+		},
+		{
+			role:        kops.InstanceGroupRoleNode,
+			minSize:     &three,
+			expectedMin: 3,
+			expectedMax: 2,
+		},
+	}
+	for i, g := range grid {
+		ig := &kops.InstanceGroup{
+			Spec: kops.InstanceGroupSpec{
+				Role:    g.role,
+				MinSize: g.minSize,
+				MaxSize: g.maxSize,
+			},
+		}
+		minSize, maxSize := InstanceGroupSize(ig)
+		if minSize != g.expectedMin || maxSize != g.expectedMax {
+			t.Errorf("case %d: expected size %d:%d, got %d:%d", i, g.expectedMin, g.expectedMax, minSize, maxSize)
+		}
+	}
+}
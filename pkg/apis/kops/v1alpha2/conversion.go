@@ -219,6 +219,13 @@ func Convert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *kops
 			val := *in.CloudConfig.ElbSecurityGroup
 			out.CloudProvider.AWS.ElbSecurityGroup = &val
 		}
+		if in.CloudConfig.VolumeEncryptionKey != nil {
+			if out.CloudProvider.AWS == nil {
+				return field.Forbidden(field.NewPath("spec").Child("cloudConfig", "volumeEncryptionKey"), "volumeEncryptionKey supports only AWS")
+			}
+			val := *in.CloudConfig.VolumeEncryptionKey
+			out.CloudProvider.AWS.VolumeEncryptionKey = &val
+		}
 		if in.CloudConfig.GCPPDCSIDriver != nil {
 			if out.CloudProvider.GCE == nil {
 				return field.Forbidden(field.NewPath("spec").Child("cloudConfig", "gcpPDCSIDriver"), "PD CSI driver supports only GCE")
@@ -466,6 +473,13 @@ func Convert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec, out
 			val := *aws.ElbSecurityGroup
 			out.CloudConfig.ElbSecurityGroup = &val
 		}
+		if aws.VolumeEncryptionKey != nil {
+			if out.CloudConfig == nil {
+				out.CloudConfig = &CloudConfiguration{}
+			}
+			val := *aws.VolumeEncryptionKey
+			out.CloudConfig.VolumeEncryptionKey = &val
+		}
 		if aws.NodeTerminationHandler != nil {
 			out.NodeTerminationHandler = &NodeTerminationHandlerSpec{}
 			if err := autoConvert_kops_NodeTerminationHandlerSpec_To_v1alpha2_NodeTerminationHandlerSpec(aws.NodeTerminationHandler, out.NodeTerminationHandler, s); err != nil {
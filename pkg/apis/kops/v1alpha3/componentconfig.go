@@ -1080,15 +1080,41 @@ type ClusterAutoscalerConfig struct {
 	// BalanceSimilarNodeGroups makes the cluster autoscaler treat similar node groups as one.
 	// Default: false
 	BalanceSimilarNodeGroups *bool `json:"balanceSimilarNodeGroups,omitempty"`
+	// BalancingIgnoreLabels are the labels that the cluster autoscaler should ignore when considering node group similarity.
+	// Default: none
+	BalancingIgnoreLabels []string `json:"balancingIgnoreLabels,omitempty"`
+	// IgnoreTaints are the keys of the taints, such as startup taints removed once a node is bootstrapped, that the cluster autoscaler
+	// ignores when simulating new nodes of a node group. Nodes that still carry one of these taints are considered not ready yet.
+	// Default: none
+	IgnoreTaints []string `json:"ignoreTaints,omitempty"`
 	// EmitPerNodegroupMetrics If true, publishes the node groups min and max metrics count set on the cluster autoscaler.
 	// Default: false
 	EmitPerNodegroupMetrics *bool `json:"emitPerNodegroupMetrics,omitempty"`
 	// AWSUseStaticInstanceList makes the cluster autoscaler to use statically defined set of AWS EC2 Instance List.
 	// Default: false
 	AWSUseStaticInstanceList *bool `json:"awsUseStaticInstanceList,omitempty"`
+	// GCERegional makes the cluster autoscaler treat the cluster as regional, balancing node groups across zones.
+	// Only supported on GCE.
+	// Default: false
+	GCERegional *bool `json:"gceRegional,omitempty"`
+	// GCEConcurrentRefreshes is the number of concurrent refreshes of the managed instance groups the cluster autoscaler performs.
+	// Only supported on GCE.
+	// Default: 1
+	GCEConcurrentRefreshes *int32 `json:"gceConcurrentRefreshes,omitempty"`
+	// NodeGroupAutoDiscoveryTags are the ASG tags the cluster autoscaler uses to discover node groups, instead of
+	// the node groups kOps configures explicitly for each instance group. A tag with an empty value only matches on the key.
+	// Only supported on AWS.
+	// Default: none
+	NodeGroupAutoDiscoveryTags map[string]string `json:"nodeGroupAutoDiscoveryTags,omitempty"`
 	// IgnoreDaemonSetsUtilization causes the cluster autoscaler to ignore DaemonSet-managed pods when calculating resource utilization for scaling down.
 	// Default: false
 	IgnoreDaemonSetsUtilization *bool `json:"ignoreDaemonSetsUtilization,omitempty"`
+	// DaemonSetEvictionForOccupiedNodes causes DaemonSet pods to be gracefully evicted from non-empty nodes when scaling down.
+	// Default: true
+	DaemonSetEvictionForOccupiedNodes *bool `json:"daemonSetEvictionForOccupiedNodes,omitempty"`
+	// DaemonSetEvictionForEmptyNodes causes DaemonSet pods to be gracefully evicted from empty nodes when scaling down.
+	// Default: false
+	DaemonSetEvictionForEmptyNodes *bool `json:"daemonSetEvictionForEmptyNodes,omitempty"`
 	// ScaleDownUtilizationThreshold determines the utilization threshold for node scale-down.
 	// Default: 0.5
 	ScaleDownUtilizationThreshold *string `json:"scaleDownUtilizationThreshold,omitempty"`
@@ -1101,9 +1127,16 @@ type ClusterAutoscalerConfig struct {
 	// SkipNodesWithLocalStorage makes the cluster autoscaler skip scale-down of nodes with local storage.
 	// Default: true
 	SkipNodesWithLocalStorage *bool `json:"skipNodesWithLocalStorage,omitempty"`
+	// ExpendablePodsPriorityCutoff is the priority below which pods are expendable. Expendable pods don't block scale-down,
+	// being killed together with their node, and don't trigger scale-up.
+	// Default: -10
+	ExpendablePodsPriorityCutoff *int32 `json:"expendablePodsPriorityCutoff,omitempty"`
 	// NewPodScaleUpDelay causes the cluster autoscaler to ignore unschedulable pods until they are a certain "age", regardless of the scan-interval
 	// Default: 0s
 	NewPodScaleUpDelay *string `json:"newPodScaleUpDelay,omitempty"`
+	// ScanInterval is how often the cluster is reevaluated for scale up or down.
+	// Default: 10s
+	ScanInterval *string `json:"scanInterval,omitempty"`
 	// ScaleDownDelayAfterAdd determines the time after scale up that scale down evaluation resumes
 	// Default: 10m0s
 	ScaleDownDelayAfterAdd *string `json:"scaleDownDelayAfterAdd,omitempty"`
@@ -1113,6 +1146,12 @@ type ClusterAutoscalerConfig struct {
 	// ScaleDownUnreadyTime determines the time an unready node should be unneeded before it is eligible for scale down
 	// Default: 20m0s
 	ScaleDownUnreadyTime *string `json:"scaleDownUnreadyTime,omitempty"`
+	// ScaleDownGPUUnneededTime determines the time a GPU node should be unneeded before it is eligible for scale down
+	// Only passed to cluster autoscaler when set, so the image must support the --scale-down-gpu-unneeded-time flag
+	ScaleDownGPUUnneededTime *string `json:"scaleDownGPUUnneededTime,omitempty"`
+	// GPULabel is the label used to identify GPU nodes.
+	// Only passed to cluster autoscaler when set, so the image must support the --gpu-label flag
+	GPULabel *string `json:"gpuLabel,omitempty"`
 	// CordonNodeBeforeTerminating should CA cordon nodes before terminating during downscale process
 	// Default: false
 	CordonNodeBeforeTerminating *bool `json:"cordonNodeBeforeTerminating,omitempty"`
@@ -1127,6 +1166,26 @@ type ClusterAutoscalerConfig struct {
 	CPURequest *resource.Quantity `json:"cpuRequest,omitempty"`
 	// MaxNodeProvisionTime determines how long CAS will wait for a node to join the cluster.
 	MaxNodeProvisionTime string `json:"maxNodeProvisionTime,omitempty"`
+	// MaxNodesTotal is the maximum number of nodes in the cluster, across all instance groups.
+	// It caps scale-up regardless of the maximum size of each instance group.
+	// Default: no limit
+	MaxNodesTotal *int32 `json:"maxNodesTotal,omitempty"`
+	// MetricsAddress is the IP address the cluster autoscaler serves metrics and health checks on.
+	// Default: all addresses
+	MetricsAddress *string `json:"metricsAddress,omitempty"`
+	// MetricsPort is the port the cluster autoscaler serves metrics and health checks on.
+	// Default: 8085
+	MetricsPort *int32 `json:"metricsPort,omitempty"`
+	// LogLevel is the logging verbosity of the cluster autoscaler.
+	// Default: 4
+	LogLevel *int32 `json:"logLevel,omitempty"`
+	// LogFormat is the logging format of the cluster autoscaler, text or json.
+	// Default: text
+	LogFormat string `json:"logFormat,omitempty"`
+	// LeaderElectResourceLock is the type of the resource the cluster autoscaler instances use as the leader election lock.
+	// Supported values: leases, and the endpointsleases and configmapsleases migration locks before Kubernetes 1.28.
+	// Default: leases
+	LeaderElectResourceLock string `json:"leaderElectResourceLock,omitempty"`
 	// PodAnnotations are the annotations added to cluster autoscaler pods when they are created.
 	// Default: none
 	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
@@ -1136,6 +1195,12 @@ type ClusterAutoscalerConfig struct {
 	// CustomPriorityExpanderConfig overides the priority-expander ConfigMap with the provided configuration. Any InstanceGroup configuration will be ignored if this is set.
 	// This could be useful in order to use regex on priorities configuration
 	CustomPriorityExpanderConfig map[string][]string `json:"customPriorityExpanderConfig,omitempty"`
+	// ConfigNamespace is the namespace the cluster autoscaler keeps its configuration and state in. It reads the
+	// priority-expander ConfigMap, which is always named cluster-autoscaler-priority-expander, from this namespace,
+	// and writes its status ConfigMap and its leader election lock to it. kOps creates the namespace if needed,
+	// and creates the Role and RoleBinding of the cluster autoscaler in it.
+	// Default: kube-system
+	ConfigNamespace *string `json:"configNamespace,omitempty"`
 }
 
 // MetricsServerConfig determines the metrics server configuration.
@@ -74,6 +74,9 @@ type InstanceGroupSpec struct {
 	RootVolume *InstanceRootVolumeSpec `json:"rootVolume,omitempty"`
 	// Volumes is a collection of additional volumes to create for instances within this InstanceGroup
 	Volumes []VolumeSpec `json:"volumes,omitempty"`
+	// EphemeralDevices overrides how the instance store volumes of the machine type are mapped to devices,
+	// for images expecting a different layout (AWS only). Volumes not listed keep the device kOps maps them to.
+	EphemeralDevices []EphemeralDeviceSpec `json:"ephemeralDevices,omitempty"`
 	// VolumeMounts a collection of volume mounts
 	VolumeMounts []VolumeMountSpec `json:"volumeMounts,omitempty"`
 	// Subnets is the names of the Subnets (as specified in the Cluster) where machines in this instance group should be placed
@@ -256,6 +259,17 @@ type VolumeSpec struct {
 	Type string `json:"type,omitempty"`
 }
 
+// EphemeralDeviceSpec overrides the device mapping of an instance store volume
+type EphemeralDeviceSpec struct {
+	// VirtualName is the name of the instance store volume, e.g. ephemeral0 for the first one
+	VirtualName string `json:"virtualName,omitempty"`
+	// Device is the device name the instance store volume is mapped to, e.g. /dev/sdc
+	Device string `json:"device,omitempty"`
+	// Exclude leaves the instance store volume unmapped.
+	// NVMe instance store volumes are presented to the instances regardless of the mapping.
+	Exclude bool `json:"exclude,omitempty"`
+}
+
 // VolumeMountSpec defines the specification for mounting a device
 type VolumeMountSpec struct {
 	// Device is the device name to provision and mount
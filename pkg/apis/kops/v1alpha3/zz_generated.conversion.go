@@ -444,6 +444,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EphemeralDeviceSpec)(nil), (*kops.EphemeralDeviceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_EphemeralDeviceSpec_To_kops_EphemeralDeviceSpec(a.(*EphemeralDeviceSpec), b.(*kops.EphemeralDeviceSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.EphemeralDeviceSpec)(nil), (*EphemeralDeviceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_EphemeralDeviceSpec_To_v1alpha3_EphemeralDeviceSpec(a.(*kops.EphemeralDeviceSpec), b.(*EphemeralDeviceSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EtcdBackupSpec)(nil), (*kops.EtcdBackupSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_EtcdBackupSpec_To_kops_EtcdBackupSpec(a.(*EtcdBackupSpec), b.(*kops.EtcdBackupSpec), scope)
 	}); err != nil {
@@ -1506,6 +1516,7 @@ func autoConvert_v1alpha3_AWSSpec_To_kops_AWSSpec(in *AWSSpec, out *kops.AWSSpec
 	out.NodeIPFamilies = in.NodeIPFamilies
 	out.DisableSecurityGroupIngress = in.DisableSecurityGroupIngress
 	out.ElbSecurityGroup = in.ElbSecurityGroup
+	out.VolumeEncryptionKey = in.VolumeEncryptionKey
 	out.SpotinstProduct = in.SpotinstProduct
 	out.SpotinstOrientation = in.SpotinstOrientation
 	out.BinariesLocation = in.BinariesLocation
@@ -1566,6 +1577,7 @@ func autoConvert_kops_AWSSpec_To_v1alpha3_AWSSpec(in *kops.AWSSpec, out *AWSSpec
 	out.NodeIPFamilies = in.NodeIPFamilies
 	out.DisableSecurityGroupIngress = in.DisableSecurityGroupIngress
 	out.ElbSecurityGroup = in.ElbSecurityGroup
+	out.VolumeEncryptionKey = in.VolumeEncryptionKey
 	out.SpotinstProduct = in.SpotinstProduct
 	out.SpotinstOrientation = in.SpotinstOrientation
 	out.BinariesLocation = in.BinariesLocation
@@ -2559,25 +2571,43 @@ func autoConvert_v1alpha3_ClusterAutoscalerConfig_To_kops_ClusterAutoscalerConfi
 	out.Enabled = in.Enabled
 	out.Expander = in.Expander
 	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	out.BalancingIgnoreLabels = in.BalancingIgnoreLabels
+	out.IgnoreTaints = in.IgnoreTaints
 	out.EmitPerNodegroupMetrics = in.EmitPerNodegroupMetrics
 	out.AWSUseStaticInstanceList = in.AWSUseStaticInstanceList
+	out.GCERegional = in.GCERegional
+	out.GCEConcurrentRefreshes = in.GCEConcurrentRefreshes
+	out.NodeGroupAutoDiscoveryTags = in.NodeGroupAutoDiscoveryTags
 	out.IgnoreDaemonSetsUtilization = in.IgnoreDaemonSetsUtilization
+	out.DaemonSetEvictionForOccupiedNodes = in.DaemonSetEvictionForOccupiedNodes
+	out.DaemonSetEvictionForEmptyNodes = in.DaemonSetEvictionForEmptyNodes
 	out.ScaleDownUtilizationThreshold = in.ScaleDownUtilizationThreshold
 	out.SkipNodesWithCustomControllerPods = in.SkipNodesWithCustomControllerPods
 	out.SkipNodesWithSystemPods = in.SkipNodesWithSystemPods
 	out.SkipNodesWithLocalStorage = in.SkipNodesWithLocalStorage
+	out.ExpendablePodsPriorityCutoff = in.ExpendablePodsPriorityCutoff
 	out.NewPodScaleUpDelay = in.NewPodScaleUpDelay
+	out.ScanInterval = in.ScanInterval
 	out.ScaleDownDelayAfterAdd = in.ScaleDownDelayAfterAdd
 	out.ScaleDownUnneededTime = in.ScaleDownUnneededTime
 	out.ScaleDownUnreadyTime = in.ScaleDownUnreadyTime
+	out.ScaleDownGPUUnneededTime = in.ScaleDownGPUUnneededTime
+	out.GPULabel = in.GPULabel
 	out.CordonNodeBeforeTerminating = in.CordonNodeBeforeTerminating
 	out.Image = in.Image
 	out.MemoryRequest = in.MemoryRequest
 	out.CPURequest = in.CPURequest
 	out.MaxNodeProvisionTime = in.MaxNodeProvisionTime
+	out.MaxNodesTotal = in.MaxNodesTotal
+	out.MetricsAddress = in.MetricsAddress
+	out.MetricsPort = in.MetricsPort
+	out.LogLevel = in.LogLevel
+	out.LogFormat = in.LogFormat
+	out.LeaderElectResourceLock = in.LeaderElectResourceLock
 	out.PodAnnotations = in.PodAnnotations
 	out.CreatePriorityExpenderConfig = in.CreatePriorityExpenderConfig
 	out.CustomPriorityExpanderConfig = in.CustomPriorityExpanderConfig
+	out.ConfigNamespace = in.ConfigNamespace
 	return nil
 }
 
@@ -2590,25 +2620,43 @@ func autoConvert_kops_ClusterAutoscalerConfig_To_v1alpha3_ClusterAutoscalerConfi
 	out.Enabled = in.Enabled
 	out.Expander = in.Expander
 	out.BalanceSimilarNodeGroups = in.BalanceSimilarNodeGroups
+	out.BalancingIgnoreLabels = in.BalancingIgnoreLabels
+	out.IgnoreTaints = in.IgnoreTaints
 	out.EmitPerNodegroupMetrics = in.EmitPerNodegroupMetrics
 	out.AWSUseStaticInstanceList = in.AWSUseStaticInstanceList
+	out.GCERegional = in.GCERegional
+	out.GCEConcurrentRefreshes = in.GCEConcurrentRefreshes
+	out.NodeGroupAutoDiscoveryTags = in.NodeGroupAutoDiscoveryTags
 	out.IgnoreDaemonSetsUtilization = in.IgnoreDaemonSetsUtilization
+	out.DaemonSetEvictionForOccupiedNodes = in.DaemonSetEvictionForOccupiedNodes
+	out.DaemonSetEvictionForEmptyNodes = in.DaemonSetEvictionForEmptyNodes
 	out.ScaleDownUtilizationThreshold = in.ScaleDownUtilizationThreshold
 	out.SkipNodesWithCustomControllerPods = in.SkipNodesWithCustomControllerPods
 	out.SkipNodesWithSystemPods = in.SkipNodesWithSystemPods
 	out.SkipNodesWithLocalStorage = in.SkipNodesWithLocalStorage
+	out.ExpendablePodsPriorityCutoff = in.ExpendablePodsPriorityCutoff
 	out.NewPodScaleUpDelay = in.NewPodScaleUpDelay
+	out.ScanInterval = in.ScanInterval
 	out.ScaleDownDelayAfterAdd = in.ScaleDownDelayAfterAdd
 	out.ScaleDownUnneededTime = in.ScaleDownUnneededTime
 	out.ScaleDownUnreadyTime = in.ScaleDownUnreadyTime
+	out.ScaleDownGPUUnneededTime = in.ScaleDownGPUUnneededTime
+	out.GPULabel = in.GPULabel
 	out.CordonNodeBeforeTerminating = in.CordonNodeBeforeTerminating
 	out.Image = in.Image
 	out.MemoryRequest = in.MemoryRequest
 	out.CPURequest = in.CPURequest
 	out.MaxNodeProvisionTime = in.MaxNodeProvisionTime
+	out.MaxNodesTotal = in.MaxNodesTotal
+	out.MetricsAddress = in.MetricsAddress
+	out.MetricsPort = in.MetricsPort
+	out.LogLevel = in.LogLevel
+	out.LogFormat = in.LogFormat
+	out.LeaderElectResourceLock = in.LeaderElectResourceLock
 	out.PodAnnotations = in.PodAnnotations
 	out.CreatePriorityExpenderConfig = in.CreatePriorityExpenderConfig
 	out.CustomPriorityExpanderConfig = in.CustomPriorityExpanderConfig
+	out.ConfigNamespace = in.ConfigNamespace
 	return nil
 }
 
@@ -3829,6 +3877,30 @@ func Convert_kops_EnvVar_To_v1alpha3_EnvVar(in *kops.EnvVar, out *EnvVar, s conv
 	return autoConvert_kops_EnvVar_To_v1alpha3_EnvVar(in, out, s)
 }
 
+func autoConvert_v1alpha3_EphemeralDeviceSpec_To_kops_EphemeralDeviceSpec(in *EphemeralDeviceSpec, out *kops.EphemeralDeviceSpec, s conversion.Scope) error {
+	out.VirtualName = in.VirtualName
+	out.Device = in.Device
+	out.Exclude = in.Exclude
+	return nil
+}
+
+// Convert_v1alpha3_EphemeralDeviceSpec_To_kops_EphemeralDeviceSpec is an autogenerated conversion function.
+func Convert_v1alpha3_EphemeralDeviceSpec_To_kops_EphemeralDeviceSpec(in *EphemeralDeviceSpec, out *kops.EphemeralDeviceSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_EphemeralDeviceSpec_To_kops_EphemeralDeviceSpec(in, out, s)
+}
+
+func autoConvert_kops_EphemeralDeviceSpec_To_v1alpha3_EphemeralDeviceSpec(in *kops.EphemeralDeviceSpec, out *EphemeralDeviceSpec, s conversion.Scope) error {
+	out.VirtualName = in.VirtualName
+	out.Device = in.Device
+	out.Exclude = in.Exclude
+	return nil
+}
+
+// Convert_kops_EphemeralDeviceSpec_To_v1alpha3_EphemeralDeviceSpec is an autogenerated conversion function.
+func Convert_kops_EphemeralDeviceSpec_To_v1alpha3_EphemeralDeviceSpec(in *kops.EphemeralDeviceSpec, out *EphemeralDeviceSpec, s conversion.Scope) error {
+	return autoConvert_kops_EphemeralDeviceSpec_To_v1alpha3_EphemeralDeviceSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_EtcdBackupSpec_To_kops_EtcdBackupSpec(in *EtcdBackupSpec, out *kops.EtcdBackupSpec, s conversion.Scope) error {
 	out.BackupStore = in.BackupStore
 	out.Image = in.Image
@@ -4590,6 +4662,17 @@ func autoConvert_v1alpha3_InstanceGroupSpec_To_kops_InstanceGroupSpec(in *Instan
 	} else {
 		out.Volumes = nil
 	}
+	if in.EphemeralDevices != nil {
+		in, out := &in.EphemeralDevices, &out.EphemeralDevices
+		*out = make([]kops.EphemeralDeviceSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_EphemeralDeviceSpec_To_kops_EphemeralDeviceSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.EphemeralDevices = nil
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]kops.VolumeMountSpec, len(*in))
@@ -4779,6 +4862,17 @@ func autoConvert_kops_InstanceGroupSpec_To_v1alpha3_InstanceGroupSpec(in *kops.I
 	} else {
 		out.Volumes = nil
 	}
+	if in.EphemeralDevices != nil {
+		in, out := &in.EphemeralDevices, &out.EphemeralDevices
+		*out = make([]EphemeralDeviceSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_EphemeralDeviceSpec_To_v1alpha3_EphemeralDeviceSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.EphemeralDevices = nil
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]VolumeMountSpec, len(*in))
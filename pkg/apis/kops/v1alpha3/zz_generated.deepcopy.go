@@ -193,6 +193,11 @@ func (in *AWSSpec) DeepCopyInto(out *AWSSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.VolumeEncryptionKey != nil {
+		in, out := &in.VolumeEncryptionKey, &out.VolumeEncryptionKey
+		*out = new(string)
+		**out = **in
+	}
 	if in.SpotinstProduct != nil {
 		in, out := &in.SpotinstProduct, &out.SpotinstProduct
 		*out = new(string)
@@ -897,6 +902,16 @@ func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.BalancingIgnoreLabels != nil {
+		in, out := &in.BalancingIgnoreLabels, &out.BalancingIgnoreLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IgnoreTaints != nil {
+		in, out := &in.IgnoreTaints, &out.IgnoreTaints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.EmitPerNodegroupMetrics != nil {
 		in, out := &in.EmitPerNodegroupMetrics, &out.EmitPerNodegroupMetrics
 		*out = new(bool)
@@ -907,11 +922,38 @@ func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.GCERegional != nil {
+		in, out := &in.GCERegional, &out.GCERegional
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GCEConcurrentRefreshes != nil {
+		in, out := &in.GCEConcurrentRefreshes, &out.GCEConcurrentRefreshes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeGroupAutoDiscoveryTags != nil {
+		in, out := &in.NodeGroupAutoDiscoveryTags, &out.NodeGroupAutoDiscoveryTags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.IgnoreDaemonSetsUtilization != nil {
 		in, out := &in.IgnoreDaemonSetsUtilization, &out.IgnoreDaemonSetsUtilization
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DaemonSetEvictionForOccupiedNodes != nil {
+		in, out := &in.DaemonSetEvictionForOccupiedNodes, &out.DaemonSetEvictionForOccupiedNodes
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DaemonSetEvictionForEmptyNodes != nil {
+		in, out := &in.DaemonSetEvictionForEmptyNodes, &out.DaemonSetEvictionForEmptyNodes
+		*out = new(bool)
+		**out = **in
+	}
 	if in.ScaleDownUtilizationThreshold != nil {
 		in, out := &in.ScaleDownUtilizationThreshold, &out.ScaleDownUtilizationThreshold
 		*out = new(string)
@@ -932,11 +974,21 @@ func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.ExpendablePodsPriorityCutoff != nil {
+		in, out := &in.ExpendablePodsPriorityCutoff, &out.ExpendablePodsPriorityCutoff
+		*out = new(int32)
+		**out = **in
+	}
 	if in.NewPodScaleUpDelay != nil {
 		in, out := &in.NewPodScaleUpDelay, &out.NewPodScaleUpDelay
 		*out = new(string)
 		**out = **in
 	}
+	if in.ScanInterval != nil {
+		in, out := &in.ScanInterval, &out.ScanInterval
+		*out = new(string)
+		**out = **in
+	}
 	if in.ScaleDownDelayAfterAdd != nil {
 		in, out := &in.ScaleDownDelayAfterAdd, &out.ScaleDownDelayAfterAdd
 		*out = new(string)
@@ -952,6 +1004,16 @@ func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ScaleDownGPUUnneededTime != nil {
+		in, out := &in.ScaleDownGPUUnneededTime, &out.ScaleDownGPUUnneededTime
+		*out = new(string)
+		**out = **in
+	}
+	if in.GPULabel != nil {
+		in, out := &in.GPULabel, &out.GPULabel
+		*out = new(string)
+		**out = **in
+	}
 	if in.CordonNodeBeforeTerminating != nil {
 		in, out := &in.CordonNodeBeforeTerminating, &out.CordonNodeBeforeTerminating
 		*out = new(bool)
@@ -972,6 +1034,26 @@ func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 		x := (*in).DeepCopy()
 		*out = &x
 	}
+	if in.MaxNodesTotal != nil {
+		in, out := &in.MaxNodesTotal, &out.MaxNodesTotal
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MetricsAddress != nil {
+		in, out := &in.MetricsAddress, &out.MetricsAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.MetricsPort != nil {
+		in, out := &in.MetricsPort, &out.MetricsPort
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LogLevel != nil {
+		in, out := &in.LogLevel, &out.LogLevel
+		*out = new(int32)
+		**out = **in
+	}
 	if in.PodAnnotations != nil {
 		in, out := &in.PodAnnotations, &out.PodAnnotations
 		*out = make(map[string]string, len(*in))
@@ -999,6 +1081,11 @@ func (in *ClusterAutoscalerConfig) DeepCopyInto(out *ClusterAutoscalerConfig) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.ConfigNamespace != nil {
+		in, out := &in.ConfigNamespace, &out.ConfigNamespace
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -1812,6 +1899,22 @@ func (in *EnvVar) DeepCopy() *EnvVar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralDeviceSpec) DeepCopyInto(out *EphemeralDeviceSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralDeviceSpec.
+func (in *EphemeralDeviceSpec) DeepCopy() *EphemeralDeviceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralDeviceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EtcdBackupSpec) DeepCopyInto(out *EtcdBackupSpec) {
 	*out = *in
@@ -2533,6 +2636,11 @@ func (in *InstanceGroupSpec) DeepCopyInto(out *InstanceGroupSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EphemeralDevices != nil {
+		in, out := &in.EphemeralDevices, &out.EphemeralDevices
+		*out = make([]EphemeralDeviceSpec, len(*in))
+		copy(*out, *in)
+	}
 	if in.VolumeMounts != nil {
 		in, out := &in.VolumeMounts, &out.VolumeMounts
 		*out = make([]VolumeMountSpec, len(*in))
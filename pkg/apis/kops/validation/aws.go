@@ -101,6 +101,42 @@ func awsValidateInstanceGroup(ig *kops.InstanceGroup, cloud awsup.AWSCloud) fiel
 		allErrs = append(allErrs, awsValidateMaximumInstanceLifetime(field.NewPath(ig.GetName(), "spec"), ig.Spec.MaxInstanceLifetime)...)
 	}
 
+	if ig.Spec.RootVolume != nil {
+		rootVolume := ig.Spec.RootVolume
+		allErrs = append(allErrs, awsValidateVolumeIops(field.NewPath("spec", "rootVolume", "iops"), fi.ValueOf(rootVolume.Type), int64(fi.ValueOf(rootVolume.IOPS)), int64(fi.ValueOf(rootVolume.Size)))...)
+	}
+
+	for i, volume := range ig.Spec.Volumes {
+		allErrs = append(allErrs, awsValidateVolumeIops(field.NewPath("spec", "volumes").Index(i).Child("iops"), volume.Type, fi.ValueOf(volume.IOPS), volume.Size)...)
+	}
+
+	return allErrs
+}
+
+// awsValidateVolumeIops checks that the provisioned IOPS of an EBS volume can be set for its type and do not exceed the limits
+// of its type and size, in GiB.
+// Volumes without a type are gp3 volumes, and the ratio of IOPS to size is not checked if the size is not set.
+func awsValidateVolumeIops(fieldPath *field.Path, volumeType string, iops int64, size int64) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if iops == 0 {
+		return allErrs
+	}
+	if volumeType == "" {
+		volumeType = string(ec2types.VolumeTypeGp3)
+	}
+	limits, found := awsup.VolumeIopsLimits[ec2types.VolumeType(volumeType)]
+	if !found {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, fmt.Sprintf("cannot be set for %s volumes, only for gp3, io1 and io2 volumes", volumeType)))
+		return allErrs
+	}
+
+	if iops > int64(limits.Max) {
+		allErrs = append(allErrs, field.Invalid(fieldPath, iops, fmt.Sprintf("must be at most %d for %s volumes", limits.Max, volumeType)))
+	} else if size > 0 && iops > int64(limits.MaxPerGiB)*size {
+		allErrs = append(allErrs, field.Invalid(fieldPath, iops, fmt.Sprintf("must be at most %d per GiB for %s volumes, %d for %d GiB", limits.MaxPerGiB, volumeType, int64(limits.MaxPerGiB)*size, size)))
+	}
+
 	return allErrs
 }
 
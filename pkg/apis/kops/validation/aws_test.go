@@ -233,6 +233,77 @@ func TestValidateInstanceGroupSpec(t *testing.T) {
 			},
 			ExpectedErrors: []string{},
 		},
+		{
+			Input: kops.InstanceGroupSpec{
+				RootVolume: &kops.InstanceRootVolumeSpec{
+					Size: fi.PtrTo(int32(160)),
+					IOPS: fi.PtrTo(int32(80000)),
+				},
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				RootVolume: &kops.InstanceRootVolumeSpec{
+					IOPS: fi.PtrTo(int32(90000)),
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.rootVolume.iops",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				RootVolume: &kops.InstanceRootVolumeSpec{
+					Type: fi.PtrTo("io1"),
+					Size: fi.PtrTo(int32(100)),
+					IOPS: fi.PtrTo(int32(6000)),
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.rootVolume.iops",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				RootVolume: &kops.InstanceRootVolumeSpec{
+					Type: fi.PtrTo("gp2"),
+					IOPS: fi.PtrTo(int32(3000)),
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.rootVolume.iops",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Volumes: []kops.VolumeSpec{
+					{
+						Device: "/dev/xvdd",
+						Size:   20,
+						Type:   "st1",
+						IOPS:   fi.PtrTo(int64(500)),
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Forbidden::spec.volumes[0].iops",
+			},
+		},
+		{
+			Input: kops.InstanceGroupSpec{
+				Volumes: []kops.VolumeSpec{
+					{
+						Device: "/dev/xvdd",
+						Size:   20,
+						Type:   "gp3",
+						IOPS:   fi.PtrTo(int64(16000)),
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.volumes[0].iops",
+			},
+		},
 	}
 	cloud := awsup.BuildMockAWSCloud("us-east-1", "abc")
 	mockEC2 := &mockec2.MockEC2{}
@@ -18,6 +18,7 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"k8s.io/kops/pkg/nodeidentity/aws"
@@ -129,6 +130,31 @@ func ValidateInstanceGroup(g *kops.InstanceGroup, cloud fi.Cloud, strict bool) f
 		devices[x.Device] = true
 	}
 
+	// @step: iterate and check the ephemeral device overrides
+	{
+		virtualNames := make(map[string]bool)
+		devices := make(map[string]bool)
+		for _, x := range g.Spec.Volumes {
+			devices[x.Device] = true
+		}
+		for i, x := range g.Spec.EphemeralDevices {
+			path := field.NewPath("spec", "ephemeralDevices").Index(i)
+
+			allErrs = append(allErrs, validateEphemeralDeviceSpec(path, x)...)
+
+			if virtualNames[x.VirtualName] {
+				allErrs = append(allErrs, field.Duplicate(path.Child("virtualName"), x.VirtualName))
+			}
+			virtualNames[x.VirtualName] = true
+			if x.Device != "" {
+				if devices[x.Device] {
+					allErrs = append(allErrs, field.Duplicate(path.Child("device"), x.Device))
+				}
+				devices[x.Device] = true
+			}
+		}
+	}
+
 	// @step: iterate and check the volume mount specs
 	for i, x := range g.Spec.VolumeMounts {
 		used := make(map[string]bool)
@@ -211,6 +237,29 @@ func validateVolumeSpec(path *field.Path, v kops.VolumeSpec) field.ErrorList {
 	return allErrs
 }
 
+// ephemeralVirtualName matches the virtual names of the instance store volumes
+var ephemeralVirtualName = regexp.MustCompile(`^ephemeral[0-9]+$`)
+
+// validateEphemeralDeviceSpec is responsible for checking the ephemeral device override is ok
+func validateEphemeralDeviceSpec(path *field.Path, spec kops.EphemeralDeviceSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if spec.VirtualName == "" {
+		allErrs = append(allErrs, field.Required(path.Child("virtualName"), "virtual name required"))
+	} else if !ephemeralVirtualName.MatchString(spec.VirtualName) {
+		allErrs = append(allErrs, field.Invalid(path.Child("virtualName"), spec.VirtualName, "must be of the form ephemeral<N>"))
+	}
+	if spec.Exclude {
+		if spec.Device != "" {
+			allErrs = append(allErrs, field.Forbidden(path.Child("device"), "device cannot be set for an excluded ephemeral device"))
+		}
+	} else if spec.Device == "" {
+		allErrs = append(allErrs, field.Required(path.Child("device"), "device name required"))
+	}
+
+	return allErrs
+}
+
 // validateVolumeMountSpec is responsible for checking the volume mount is ok
 func validateVolumeMountSpec(path *field.Path, spec kops.VolumeMountSpec) field.ErrorList {
 	allErrs := field.ErrorList{}
@@ -262,6 +311,21 @@ func CrossValidateInstanceGroup(g *kops.InstanceGroup, cluster *kops.Cluster, cl
 		}
 	}
 
+	// The cluster autoscaler node group auto-discovery tags are applied to the autoscaled node instance groups
+	if cas := cluster.Spec.ClusterAutoscaler; cas != nil && fi.ValueOf(cas.Enabled) {
+		if g.Spec.Role == kops.InstanceGroupRoleNode && (g.Spec.Autoscale == nil || fi.ValueOf(g.Spec.Autoscale)) {
+			for key := range g.Spec.CloudLabels {
+				if _, found := cas.NodeGroupAutoDiscoveryTags[key]; found {
+					allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "cloudLabels").Key(key), "label conflicts with a node group auto-discovery tag of the cluster autoscaler"))
+				}
+			}
+		}
+	}
+
+	if len(g.Spec.EphemeralDevices) > 0 && cluster.GetCloudProvider() != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec", "ephemeralDevices"), "ephemeral devices can only be overridden on AWS"))
+	}
+
 	if cluster.GetCloudProvider() == kops.CloudProviderAWS {
 		if g.Spec.RootVolume != nil && g.Spec.RootVolume.Type != nil {
 			allErrs = append(allErrs, IsValidValue(field.NewPath("spec", "rootVolume", "type"), g.Spec.RootVolume.Type, []string{"standard", "gp3", "gp2", "io1", "io2"})...)
@@ -229,6 +229,87 @@ func TestValidBootDevice(t *testing.T) {
 	}
 }
 
+func TestValidEphemeralDevices(t *testing.T) {
+	aws := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{},
+			},
+		},
+	}
+	gce := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{
+				GCE: &kops.GCESpec{},
+			},
+		},
+	}
+	grid := []struct {
+		name     string
+		cluster  *kops.Cluster
+		devices  []kops.EphemeralDeviceSpec
+		expected []string
+	}{
+		{
+			name:    "rename and exclude",
+			cluster: aws,
+			devices: []kops.EphemeralDeviceSpec{
+				{VirtualName: "ephemeral0", Device: "/dev/sdx"},
+				{VirtualName: "ephemeral1", Exclude: true},
+			},
+		},
+		{
+			name:     "invalid virtual name",
+			cluster:  aws,
+			devices:  []kops.EphemeralDeviceSpec{{VirtualName: "instance-store0", Device: "/dev/sdx"}},
+			expected: []string{"Invalid value::spec.ephemeralDevices[0].virtualName"},
+		},
+		{
+			name:     "missing device",
+			cluster:  aws,
+			devices:  []kops.EphemeralDeviceSpec{{VirtualName: "ephemeral0"}},
+			expected: []string{"Required value::spec.ephemeralDevices[0].device"},
+		},
+		{
+			name:     "excluded with device",
+			cluster:  aws,
+			devices:  []kops.EphemeralDeviceSpec{{VirtualName: "ephemeral0", Device: "/dev/sdx", Exclude: true}},
+			expected: []string{"Forbidden::spec.ephemeralDevices[0].device"},
+		},
+		{
+			name:    "duplicate virtual name",
+			cluster: aws,
+			devices: []kops.EphemeralDeviceSpec{
+				{VirtualName: "ephemeral0", Device: "/dev/sdx"},
+				{VirtualName: "ephemeral0", Exclude: true},
+			},
+			expected: []string{"Duplicate value::spec.ephemeralDevices[1].virtualName"},
+		},
+		{
+			name:    "duplicate device",
+			cluster: aws,
+			devices: []kops.EphemeralDeviceSpec{
+				{VirtualName: "ephemeral0", Device: "/dev/sdx"},
+				{VirtualName: "ephemeral1", Device: "/dev/sdx"},
+			},
+			expected: []string{"Duplicate value::spec.ephemeralDevices[1].device"},
+		},
+		{
+			name:     "not aws",
+			cluster:  gce,
+			devices:  []kops.EphemeralDeviceSpec{{VirtualName: "ephemeral0", Device: "/dev/sdx"}},
+			expected: []string{"Forbidden::spec.ephemeralDevices"},
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+		ig.Spec.EphemeralDevices = g.devices
+		errs := CrossValidateInstanceGroup(ig, g.cluster, nil, true)
+		testErrors(t, g.name, errs, g.expected)
+	}
+}
+
 func TestValidNodeLabels(t *testing.T) {
 	grid := []struct {
 		label    string
@@ -311,6 +392,61 @@ func TestIGCloudLabelIsIGName(t *testing.T) {
 	}
 }
 
+func TestIGCloudLabelsNodeGroupAutoDiscoveryTags(t *testing.T) {
+	cluster := &kops.Cluster{
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{},
+			},
+			ClusterAutoscaler: &kops.ClusterAutoscalerConfig{
+				Enabled: fi.PtrTo(true),
+				NodeGroupAutoDiscoveryTags: map[string]string{
+					"team": "data",
+				},
+			},
+		},
+	}
+	grid := []struct {
+		name      string
+		role      kops.InstanceGroupRole
+		autoscale *bool
+		label     string
+		expected  []string
+	}{
+		{
+			name:  "other label",
+			role:  kops.InstanceGroupRoleNode,
+			label: "owner",
+		},
+		{
+			name:     "conflicting label",
+			role:     kops.InstanceGroupRoleNode,
+			label:    "team",
+			expected: []string{"Forbidden::spec.cloudLabels[team]"},
+		},
+		{
+			name:      "conflicting label without autoscaling",
+			role:      kops.InstanceGroupRoleNode,
+			autoscale: fi.PtrTo(false),
+			label:     "team",
+		},
+		{
+			name:  "conflicting label on a bastion",
+			role:  kops.InstanceGroupRoleBastion,
+			label: "team",
+		},
+	}
+
+	for _, g := range grid {
+		ig := createMinimalInstanceGroup()
+		ig.Spec.Role = g.role
+		ig.Spec.Autoscale = g.autoscale
+		ig.Spec.CloudLabels[g.label] = "placeholder"
+		errs := CrossValidateInstanceGroup(ig, cluster, nil, true)
+		testErrors(t, g.name, errs, g.expected)
+	}
+}
+
 func TestValidTaints(t *testing.T) {
 	grid := []struct {
 		taints   []string
@@ -23,7 +23,9 @@ import (
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/blang/semver/v4"
@@ -1872,17 +1874,110 @@ func validateNodeLocalDNS(spec *kops.ClusterSpec, fldpath *field.Path) field.Err
 
 func validateClusterAutoscaler(cluster *kops.Cluster, spec *kops.ClusterAutoscalerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if spec.Expander != "" {
-		allErrs = append(allErrs, IsValidValue(fldPath.Child("expander"), &spec.Expander, []string{"least-waste", "random", "most-pods", "price", "priority"})...)
+		// Several expanders can be chained, separated by commas, each one breaking the ties of the previous
+		expanders := strings.Split(spec.Expander, ",")
+		for _, expander := range expanders {
+			allErrs = append(allErrs, IsValidValue(fldPath.Child("expander"), &expander, []string{"least-waste", "random", "most-pods", "price", "priority"})...)
+		}
+
+		if slices.Contains(expanders, "price") && cluster.Spec.CloudProvider.GCE == nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("expander"), "Cluster autoscaler price expander is only supported on GCE"))
+		}
 	}
 
-	if spec.Expander == "price" && cluster.Spec.CloudProvider.GCE == nil {
-		allErrs = append(allErrs, field.Forbidden(fldPath.Child("expander"), "Cluster autoscaler price expander is only supported on GCE"))
+	if spec.ConfigNamespace != nil {
+		for _, msg := range utilvalidation.IsDNS1123Label(*spec.ConfigNamespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("configNamespace"), *spec.ConfigNamespace, msg))
+		}
 	}
 
 	if cluster.GetCloudProvider() == kops.CloudProviderOpenstack {
 		allErrs = append(allErrs, field.Forbidden(fldPath, "Cluster autoscaler is not supported on OpenStack"))
 	}
 
+	if len(spec.NodeGroupAutoDiscoveryTags) > 0 && cluster.GetCloudProvider() != kops.CloudProviderAWS {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeGroupAutoDiscoveryTags"), "Node group auto-discovery is only supported on AWS"))
+	}
+	if cluster.GetCloudProvider() != kops.CloudProviderGCE {
+		if spec.GCERegional != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("gceRegional"), "Regional node group balancing is only supported on GCE"))
+		}
+		if spec.GCEConcurrentRefreshes != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("gceConcurrentRefreshes"), "Concurrent managed instance group refreshes are only supported on GCE"))
+		}
+	}
+	if spec.GCEConcurrentRefreshes != nil && *spec.GCEConcurrentRefreshes < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("gceConcurrentRefreshes"), *spec.GCEConcurrentRefreshes, "must be at least 1"))
+	}
+	for key, value := range spec.NodeGroupAutoDiscoveryTags {
+		if key == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeGroupAutoDiscoveryTags"), key, "tag keys must not be empty"))
+		} else if strings.ContainsAny(key, ",=") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeGroupAutoDiscoveryTags").Key(key), key, "tag keys must not contain ',' or '='"))
+		} else if strings.Contains(value, ",") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeGroupAutoDiscoveryTags").Key(key), value, "tag values must not contain ','"))
+		} else if _, found := cluster.Spec.CloudLabels[key]; found && fi.ValueOf(spec.Enabled) {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("nodeGroupAutoDiscoveryTags").Key(key), "tag conflicts with a cloud label of the cluster"))
+		}
+	}
+
+	if spec.MetricsAddress != nil && net.ParseIP(*spec.MetricsAddress) == nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("metricsAddress"), *spec.MetricsAddress, "must be a valid IP address"))
+	}
+	if spec.MetricsPort != nil {
+		for _, msg := range utilvalidation.IsValidPortNum(int(*spec.MetricsPort)) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("metricsPort"), *spec.MetricsPort, msg))
+		}
+	}
+
+	if spec.LogLevel != nil && *spec.LogLevel < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("logLevel"), *spec.LogLevel, "must not be negative"))
+	}
+	if spec.LogFormat != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("logFormat"), &spec.LogFormat, []string{"text", "json"})...)
+	}
+	if spec.LeaderElectResourceLock != "" {
+		allErrs = append(allErrs, IsValidValue(fldPath.Child("leaderElectResourceLock"), &spec.LeaderElectResourceLock, []string{"leases", "endpointsleases", "configmapsleases"})...)
+		if spec.LeaderElectResourceLock != "leases" && cluster.IsKubernetesGTE("1.28") {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("leaderElectResourceLock"), "The migration resource locks were removed from the cluster autoscaler in Kubernetes 1.28"))
+		}
+	}
+
+	for i, label := range spec.BalancingIgnoreLabels {
+		for _, msg := range utilvalidation.IsQualifiedName(label) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("balancingIgnoreLabels").Index(i), label, msg))
+		}
+	}
+	for i, taint := range spec.IgnoreTaints {
+		for _, msg := range utilvalidation.IsQualifiedName(taint) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("ignoreTaints").Index(i), taint, msg))
+		}
+	}
+
+	allErrs = append(allErrs, validateDuration(fldPath.Child("newPodScaleUpDelay"), spec.NewPodScaleUpDelay)...)
+	allErrs = append(allErrs, validateDuration(fldPath.Child("scanInterval"), spec.ScanInterval)...)
+	allErrs = append(allErrs, validateDuration(fldPath.Child("scaleDownDelayAfterAdd"), spec.ScaleDownDelayAfterAdd)...)
+	allErrs = append(allErrs, validateDuration(fldPath.Child("scaleDownUnneededTime"), spec.ScaleDownUnneededTime)...)
+	allErrs = append(allErrs, validateDuration(fldPath.Child("scaleDownGPUUnneededTime"), spec.ScaleDownGPUUnneededTime)...)
+	allErrs = append(allErrs, validateDuration(fldPath.Child("scaleDownUnreadyTime"), spec.ScaleDownUnreadyTime)...)
+	if spec.MaxNodeProvisionTime != "" {
+		allErrs = append(allErrs, validateDuration(fldPath.Child("maxNodeProvisionTime"), &spec.MaxNodeProvisionTime)...)
+	}
+	if spec.MaxNodesTotal != nil && *spec.MaxNodesTotal <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxNodesTotal"), *spec.MaxNodesTotal, "must be greater than 0"))
+	}
+
+	return allErrs
+}
+
+// validateDuration checks that the value, if set, is a valid duration
+func validateDuration(fldPath *field.Path, value *string) (allErrs field.ErrorList) {
+	if value == nil {
+		return allErrs
+	}
+	if _, err := time.ParseDuration(*value); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath, *value, "must be a valid duration, e.g. 10m0s"))
+	}
 	return allErrs
 }
 
@@ -1777,3 +1777,246 @@ func Test_Validate_NriConfig(t *testing.T) {
 		testErrors(t, g.Input.Containerd, errs, g.ExpectedErrors)
 	}
 }
+
+func Test_Validate_ClusterAutoscaler(t *testing.T) {
+	grid := []struct {
+		Input             kops.ClusterAutoscalerConfig
+		KubernetesVersion string
+		CloudLabels       map[string]string
+		ExpectedErrors    []string
+	}{
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				ScaleDownUnneededTime:    fi.PtrTo("10m0s"),
+				ScaleDownGPUUnneededTime: fi.PtrTo("1h"),
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				ScaleDownGPUUnneededTime: fi.PtrTo("1 hour"),
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.scaleDownGPUUnneededTime"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				ScaleDownUnreadyTime: fi.PtrTo("20"),
+				MaxNodeProvisionTime: "15m",
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.scaleDownUnreadyTime"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander: "priority,least-waste",
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander: "leastwaste",
+			},
+			ExpectedErrors: []string{"Unsupported value::clusterAutoscaler.expander"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander: "priority,leastwaste",
+			},
+			ExpectedErrors: []string{"Unsupported value::clusterAutoscaler.expander"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander: "least-waste,price",
+			},
+			ExpectedErrors: []string{"Forbidden::clusterAutoscaler.expander"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander:        "priority,least-waste",
+				ConfigNamespace: fi.PtrTo("autoscaling"),
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander:        "priority",
+				ConfigNamespace: fi.PtrTo("Autoscaling"),
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.configNamespace"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Expander:        "least-waste",
+				ConfigNamespace: fi.PtrTo("autoscaling"),
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				ScanInterval: fi.PtrTo("10"),
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.scanInterval"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				BalancingIgnoreLabels: []string{"topology.ebs.csi.aws.com/zone", "example.com/node pool"},
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.balancingIgnoreLabels[1]"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				IgnoreTaints: []string{"node.cilium.io/agent-not-ready", "ignore-taint.cluster-autoscaler.kubernetes.io/startup"},
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				IgnoreTaints: []string{"node.cilium.io/agent-not-ready", "node.cilium.io/agent-not-ready=true:NoSchedule"},
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.ignoreTaints[1]"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				MaxNodesTotal: fi.PtrTo(int32(0)),
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.maxNodesTotal"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				LogLevel:  fi.PtrTo(int32(-1)),
+				LogFormat: "yaml",
+			},
+			ExpectedErrors: []string{
+				"Invalid value::clusterAutoscaler.logLevel",
+				"Unsupported value::clusterAutoscaler.logFormat",
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				MetricsAddress: fi.PtrTo("localhost"),
+				MetricsPort:    fi.PtrTo(int32(70000)),
+			},
+			ExpectedErrors: []string{
+				"Invalid value::clusterAutoscaler.metricsAddress",
+				"Invalid value::clusterAutoscaler.metricsPort",
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				NodeGroupAutoDiscoveryTags: map[string]string{
+					"k8s.io/cluster-autoscaler/enabled": "",
+					"team":                              "a,b",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.nodeGroupAutoDiscoveryTags[team]"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				NodeGroupAutoDiscoveryTags: map[string]string{
+					"": "1",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.nodeGroupAutoDiscoveryTags"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Enabled: fi.PtrTo(true),
+				NodeGroupAutoDiscoveryTags: map[string]string{
+					"k8s.io/cluster-autoscaler/enabled": "",
+					"team":                              "data",
+				},
+			},
+			CloudLabels:    map[string]string{"team": "data"},
+			ExpectedErrors: []string{"Forbidden::clusterAutoscaler.nodeGroupAutoDiscoveryTags[team]"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				Enabled: fi.PtrTo(false),
+				NodeGroupAutoDiscoveryTags: map[string]string{
+					"team": "data",
+				},
+			},
+			CloudLabels: map[string]string{"team": "data"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				LeaderElectResourceLock: "leases",
+			},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				LeaderElectResourceLock: "endpointsleases",
+			},
+			KubernetesVersion: "1.27.0",
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				LeaderElectResourceLock: "configmapsleases",
+			},
+			ExpectedErrors: []string{"Forbidden::clusterAutoscaler.leaderElectResourceLock"},
+		},
+		{
+			Input: kops.ClusterAutoscalerConfig{
+				LeaderElectResourceLock: "endpoints",
+			},
+			KubernetesVersion: "1.27.0",
+			ExpectedErrors:    []string{"Unsupported value::clusterAutoscaler.leaderElectResourceLock"},
+		},
+	}
+	for _, g := range grid {
+		kubernetesVersion := g.KubernetesVersion
+		if kubernetesVersion == "" {
+			kubernetesVersion = "1.30.0"
+		}
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				KubernetesVersion: kubernetesVersion,
+				CloudProvider: kops.CloudProviderSpec{
+					AWS: &kops.AWSSpec{},
+				},
+				CloudLabels:       g.CloudLabels,
+				ClusterAutoscaler: &g.Input,
+			},
+		}
+		errs := validateClusterAutoscaler(cluster, &g.Input, field.NewPath("clusterAutoscaler"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_ClusterAutoscaler_GCE(t *testing.T) {
+	grid := []struct {
+		CloudProvider  kops.CloudProviderSpec
+		Input          kops.ClusterAutoscalerConfig
+		ExpectedErrors []string
+	}{
+		{
+			CloudProvider: kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			Input: kops.ClusterAutoscalerConfig{
+				GCERegional:            fi.PtrTo(true),
+				GCEConcurrentRefreshes: fi.PtrTo(int32(4)),
+			},
+		},
+		{
+			CloudProvider: kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			Input: kops.ClusterAutoscalerConfig{
+				GCEConcurrentRefreshes: fi.PtrTo(int32(0)),
+			},
+			ExpectedErrors: []string{"Invalid value::clusterAutoscaler.gceConcurrentRefreshes"},
+		},
+		{
+			CloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			Input: kops.ClusterAutoscalerConfig{
+				GCERegional:            fi.PtrTo(false),
+				GCEConcurrentRefreshes: fi.PtrTo(int32(2)),
+			},
+			ExpectedErrors: []string{
+				"Forbidden::clusterAutoscaler.gceRegional",
+				"Forbidden::clusterAutoscaler.gceConcurrentRefreshes",
+			},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider:     g.CloudProvider,
+				ClusterAutoscaler: &g.Input,
+			},
+		}
+		errs := validateClusterAutoscaler(cluster, &g.Input, field.NewPath("clusterAutoscaler"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
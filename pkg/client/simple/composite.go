@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simple
+
+import (
+	"context"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// NewCompositeClientset returns a Clientset that spans several state stores.
+// Clusters are looked up in the primary and then in each secondary in order, the first match winning.
+// The secondaries are read-only: new clusters are created in the primary, and operations on a cluster
+// found in a secondary fail with ErrReadOnly if they would modify it.
+func NewCompositeClientset(primary Clientset, secondaries ...Clientset) Clientset {
+	if len(secondaries) == 0 {
+		return primary
+	}
+	members := []Clientset{primary}
+	for _, secondary := range secondaries {
+		members = append(members, NewReadOnlyClientset(secondary))
+	}
+	return &compositeClientset{
+		members: members,
+		owners:  make(map[string]Clientset),
+	}
+}
+
+type compositeClientset struct {
+	// members holds the primary followed by the read-only secondaries
+	members []Clientset
+
+	// mutex protects owners
+	mutex sync.Mutex
+	// owners records the member each cluster was read from, by cluster name
+	owners map[string]Clientset
+}
+
+var _ Clientset = &compositeClientset{}
+
+func (c *compositeClientset) primary() Clientset {
+	return c.members[0]
+}
+
+// ownerOf returns the member the cluster was read from, defaulting to the primary.
+func (c *compositeClientset) ownerOf(cluster *kops.Cluster) Clientset {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if owner, found := c.owners[cluster.ObjectMeta.Name]; found {
+		return owner
+	}
+	return c.primary()
+}
+
+func (c *compositeClientset) setOwner(name string, owner Clientset) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.owners[name] = owner
+}
+
+func (c *compositeClientset) VFSContext() *vfs.VFSContext {
+	return c.primary().VFSContext()
+}
+
+func (c *compositeClientset) GetCluster(ctx context.Context, name string) (*kops.Cluster, error) {
+	for _, member := range c.members {
+		cluster, err := member.GetCluster(ctx, name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if cluster == nil {
+			continue
+		}
+		c.setOwner(name, member)
+		return cluster, nil
+	}
+	return nil, apierrors.NewNotFound(kops.Resource("Cluster"), name)
+}
+
+func (c *compositeClientset) CreateCluster(ctx context.Context, cluster *kops.Cluster) (*kops.Cluster, error) {
+	created, err := c.primary().CreateCluster(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+	c.setOwner(cluster.ObjectMeta.Name, c.primary())
+	return created, nil
+}
+
+func (c *compositeClientset) UpdateCluster(ctx context.Context, cluster *kops.Cluster, status *kops.ClusterStatus) (*kops.Cluster, error) {
+	return c.ownerOf(cluster).UpdateCluster(ctx, cluster, status)
+}
+
+func (c *compositeClientset) ListClusters(ctx context.Context, options metav1.ListOptions) (*kops.ClusterList, error) {
+	result := &kops.ClusterList{}
+	seen := make(map[string]bool)
+	for _, member := range c.members {
+		list, err := member.ListClusters(ctx, options)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range list.Items {
+			if seen[cluster.ObjectMeta.Name] {
+				continue
+			}
+			seen[cluster.ObjectMeta.Name] = true
+			c.setOwner(cluster.ObjectMeta.Name, member)
+			result.Items = append(result.Items, cluster)
+		}
+	}
+	return result, nil
+}
+
+func (c *compositeClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
+	return c.ownerOf(cluster).ConfigBaseFor(cluster)
+}
+
+func (c *compositeClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalversion.InstanceGroupInterface {
+	return c.ownerOf(cluster).InstanceGroupsFor(cluster)
+}
+
+func (c *compositeClientset) AddonsFor(cluster *kops.Cluster) AddonsClient {
+	return c.ownerOf(cluster).AddonsFor(cluster)
+}
+
+func (c *compositeClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
+	return c.ownerOf(cluster).SecretStore(cluster)
+}
+
+func (c *compositeClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
+	return c.ownerOf(cluster).KeyStore(cluster)
+}
+
+func (c *compositeClientset) SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredentialStore, error) {
+	return c.ownerOf(cluster).SSHCredentialStore(cluster)
+}
+
+func (c *compositeClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster) error {
+	return c.ownerOf(cluster).DeleteCluster(ctx, cluster)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simple_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestCompositeClientset(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+	writeCluster := func(store, name string) simple.Clientset {
+		basePath, err := vfs.Context.BuildVfsPath("memfs://" + store)
+		if err != nil {
+			t.Fatalf("error building path: %v", err)
+		}
+		clusterYAML := "apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: " + name + "\n"
+		if err := basePath.Join(name, "config").WriteFile(ctx, strings.NewReader(clusterYAML), nil); err != nil {
+			t.Fatalf("error writing cluster: %v", err)
+		}
+		return vfsclientset.NewVFSClientset(vfs.Context, basePath)
+	}
+	primary := writeCluster("team-a", "a.example.com")
+	secondary := writeCluster("team-b", "b.example.com")
+	writeCluster("team-b", "a.example.com")
+
+	clientset := simple.NewCompositeClientset(primary, secondary)
+
+	list, err := clientset.ListClusters(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error listing clusters: %v", err)
+	}
+	var names []string
+	for _, cluster := range list.Items {
+		names = append(names, cluster.ObjectMeta.Name)
+	}
+	if strings.Join(names, ",") != "a.example.com,b.example.com" {
+		t.Errorf("unexpected clusters listed: %v", names)
+	}
+
+	clusterA, err := clientset.GetCluster(ctx, "a.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster from primary: %v", err)
+	}
+	configBase, err := clientset.ConfigBaseFor(clusterA)
+	if err != nil {
+		t.Fatalf("unexpected error getting config base: %v", err)
+	}
+	if configBase.Path() != "memfs://team-a/a.example.com" {
+		t.Errorf("expected cluster to be read from the primary, got %q", configBase.Path())
+	}
+
+	clusterB, err := clientset.GetCluster(ctx, "b.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster from secondary: %v", err)
+	}
+	configBase, err = clientset.ConfigBaseFor(clusterB)
+	if err != nil {
+		t.Fatalf("unexpected error getting config base: %v", err)
+	}
+	if configBase.Path() != "memfs://team-b/b.example.com" {
+		t.Errorf("expected cluster to be read from the secondary, got %q", configBase.Path())
+	}
+	if err := clientset.DeleteCluster(ctx, clusterB); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly deleting cluster from secondary, got %v", err)
+	}
+
+	if _, err := clientset.GetCluster(ctx, "missing.example.com"); !apierrors.IsNotFound(err) {
+		t.Errorf("expected NotFound for missing cluster, got %v", err)
+	}
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simple
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kops/pkg/apis/kops"
+	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
+	"k8s.io/kops/pkg/kubemanifest"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// ErrReadOnly is returned by a read-only Clientset for any operation that would mutate the state store.
+var ErrReadOnly = errors.New("clientset is read-only")
+
+func readOnlyError(op string) error {
+	return fmt.Errorf("cannot %s: %w", op, ErrReadOnly)
+}
+
+// NewReadOnlyClientset wraps a Clientset so that read operations are passed through,
+// while create, update and delete operations, including writes through the vfs.Paths
+// it returns, fail with ErrReadOnly.
+func NewReadOnlyClientset(inner Clientset) Clientset {
+	if _, ok := inner.(*readOnlyClientset); ok {
+		return inner
+	}
+	return &readOnlyClientset{inner: inner}
+}
+
+type readOnlyClientset struct {
+	inner Clientset
+}
+
+var _ Clientset = &readOnlyClientset{}
+
+func (c *readOnlyClientset) VFSContext() *vfs.VFSContext {
+	return c.inner.VFSContext()
+}
+
+func (c *readOnlyClientset) GetCluster(ctx context.Context, name string) (*kops.Cluster, error) {
+	return c.inner.GetCluster(ctx, name)
+}
+
+func (c *readOnlyClientset) CreateCluster(ctx context.Context, cluster *kops.Cluster) (*kops.Cluster, error) {
+	return nil, readOnlyError(fmt.Sprintf("create cluster %q", cluster.ObjectMeta.Name))
+}
+
+func (c *readOnlyClientset) UpdateCluster(ctx context.Context, cluster *kops.Cluster, status *kops.ClusterStatus) (*kops.Cluster, error) {
+	return nil, readOnlyError(fmt.Sprintf("update cluster %q", cluster.ObjectMeta.Name))
+}
+
+func (c *readOnlyClientset) ListClusters(ctx context.Context, options metav1.ListOptions) (*kops.ClusterList, error) {
+	return c.inner.ListClusters(ctx, options)
+}
+
+func (c *readOnlyClientset) ConfigBaseFor(cluster *kops.Cluster) (vfs.Path, error) {
+	configBase, err := c.inner.ConfigBaseFor(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return newReadOnlyPath(configBase), nil
+}
+
+func (c *readOnlyClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalversion.InstanceGroupInterface {
+	return &readOnlyInstanceGroups{InstanceGroupInterface: c.inner.InstanceGroupsFor(cluster)}
+}
+
+func (c *readOnlyClientset) AddonsFor(cluster *kops.Cluster) AddonsClient {
+	return &readOnlyAddons{inner: c.inner.AddonsFor(cluster)}
+}
+
+func (c *readOnlyClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
+	secretStore, err := c.inner.SecretStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+	readOnly := &readOnlySecretStore{SecretStore: secretStore}
+	if hasVFSPath, ok := secretStore.(fi.HasVFSPath); ok {
+		return &readOnlySecretStoreWithVFSPath{readOnlySecretStore: readOnly, HasVFSPath: hasVFSPath}, nil
+	}
+	return readOnly, nil
+}
+
+func (c *readOnlyClientset) KeyStore(cluster *kops.Cluster) (fi.CAStore, error) {
+	keyStore, err := c.inner.KeyStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+	readOnly := &readOnlyKeyStore{CAStore: keyStore}
+	if hasVFSPath, ok := keyStore.(fi.HasVFSPath); ok {
+		return &readOnlyKeyStoreWithVFSPath{readOnlyKeyStore: readOnly, HasVFSPath: hasVFSPath}, nil
+	}
+	return readOnly, nil
+}
+
+func (c *readOnlyClientset) SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredentialStore, error) {
+	sshCredentialStore, err := c.inner.SSHCredentialStore(cluster)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlySSHCredentialStore{SSHCredentialStore: sshCredentialStore}, nil
+}
+
+func (c *readOnlyClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster) error {
+	return readOnlyError(fmt.Sprintf("delete cluster %q", cluster.ObjectMeta.Name))
+}
+
+// readOnlyInstanceGroups passes through Get, List and Watch, and rejects all mutations.
+type readOnlyInstanceGroups struct {
+	kopsinternalversion.InstanceGroupInterface
+}
+
+func (c *readOnlyInstanceGroups) Create(ctx context.Context, ig *kops.InstanceGroup, opts metav1.CreateOptions) (*kops.InstanceGroup, error) {
+	return nil, readOnlyError(fmt.Sprintf("create instance group %q", ig.ObjectMeta.Name))
+}
+
+func (c *readOnlyInstanceGroups) Update(ctx context.Context, ig *kops.InstanceGroup, opts metav1.UpdateOptions) (*kops.InstanceGroup, error) {
+	return nil, readOnlyError(fmt.Sprintf("update instance group %q", ig.ObjectMeta.Name))
+}
+
+func (c *readOnlyInstanceGroups) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return readOnlyError(fmt.Sprintf("delete instance group %q", name))
+}
+
+func (c *readOnlyInstanceGroups) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	return readOnlyError("delete instance groups")
+}
+
+func (c *readOnlyInstanceGroups) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*kops.InstanceGroup, error) {
+	return nil, readOnlyError(fmt.Sprintf("patch instance group %q", name))
+}
+
+type readOnlyAddons struct {
+	inner AddonsClient
+}
+
+func (c *readOnlyAddons) Replace(objects kubemanifest.ObjectList) error {
+	return readOnlyError("replace addons")
+}
+
+func (c *readOnlyAddons) List(ctx context.Context) (kubemanifest.ObjectList, error) {
+	return c.inner.List(ctx)
+}
+
+type readOnlySecretStore struct {
+	fi.SecretStore
+}
+
+func (s *readOnlySecretStore) DeleteSecret(id string) error {
+	return readOnlyError(fmt.Sprintf("delete secret %q", id))
+}
+
+func (s *readOnlySecretStore) GetOrCreateSecret(ctx context.Context, id string, secret *fi.Secret) (*fi.Secret, bool, error) {
+	return nil, false, readOnlyError(fmt.Sprintf("create secret %q", id))
+}
+
+func (s *readOnlySecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret, error) {
+	return nil, readOnlyError(fmt.Sprintf("replace secret %q", id))
+}
+
+func (s *readOnlySecretStore) MirrorTo(ctx context.Context, basedir vfs.Path) error {
+	return readOnlyError("mirror secrets")
+}
+
+// readOnlySecretStoreWithVFSPath preserves fi.HasVFSPath, which callers use to locate the backing store.
+type readOnlySecretStoreWithVFSPath struct {
+	*readOnlySecretStore
+	fi.HasVFSPath
+}
+
+func (s *readOnlySecretStoreWithVFSPath) VFSPath() vfs.Path {
+	return newReadOnlyPath(s.HasVFSPath.VFSPath())
+}
+
+type readOnlyKeyStore struct {
+	fi.CAStore
+}
+
+func (s *readOnlyKeyStore) StoreKeyset(ctx context.Context, name string, keyset *fi.Keyset) error {
+	return readOnlyError(fmt.Sprintf("store keyset %q", name))
+}
+
+func (s *readOnlyKeyStore) MirrorTo(ctx context.Context, basedir vfs.Path) error {
+	return readOnlyError("mirror keystore")
+}
+
+// readOnlyKeyStoreWithVFSPath preserves fi.HasVFSPath, which callers use to locate the backing store.
+type readOnlyKeyStoreWithVFSPath struct {
+	*readOnlyKeyStore
+	fi.HasVFSPath
+}
+
+func (s *readOnlyKeyStoreWithVFSPath) VFSPath() vfs.Path {
+	return newReadOnlyPath(s.HasVFSPath.VFSPath())
+}
+
+type readOnlySSHCredentialStore struct {
+	fi.SSHCredentialStore
+}
+
+func (s *readOnlySSHCredentialStore) DeleteSSHCredential() error {
+	return readOnlyError("delete SSH credential")
+}
+
+func (s *readOnlySSHCredentialStore) AddSSHPublicKey(ctx context.Context, data []byte) error {
+	return readOnlyError("add SSH public key")
+}
+
+// readOnlyPath passes through reads of a vfs.Path and the paths derived from it, and rejects all writes.
+type readOnlyPath struct {
+	inner vfs.Path
+}
+
+var _ vfs.Path = &readOnlyPath{}
+
+func newReadOnlyPath(inner vfs.Path) vfs.Path {
+	if _, ok := inner.(*readOnlyPath); ok {
+		return inner
+	}
+	return &readOnlyPath{inner: inner}
+}
+
+func (p *readOnlyPath) String() string {
+	return p.inner.Path()
+}
+
+func (p *readOnlyPath) Path() string {
+	return p.inner.Path()
+}
+
+func (p *readOnlyPath) Base() string {
+	return p.inner.Base()
+}
+
+func (p *readOnlyPath) Join(relativePath ...string) vfs.Path {
+	return newReadOnlyPath(p.inner.Join(relativePath...))
+}
+
+func (p *readOnlyPath) ReadFile(ctx context.Context) ([]byte, error) {
+	return p.inner.ReadFile(ctx)
+}
+
+func (p *readOnlyPath) WriteTo(w io.Writer) (int64, error) {
+	return p.inner.WriteTo(w)
+}
+
+func (p *readOnlyPath) ReadDir() ([]vfs.Path, error) {
+	paths, err := p.inner.ReadDir()
+	return newReadOnlyPaths(paths), err
+}
+
+func (p *readOnlyPath) ReadTree(ctx context.Context) ([]vfs.Path, error) {
+	paths, err := p.inner.ReadTree(ctx)
+	return newReadOnlyPaths(paths), err
+}
+
+func (p *readOnlyPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl vfs.ACL) error {
+	return readOnlyError(fmt.Sprintf("write %q", p.inner.Path()))
+}
+
+func (p *readOnlyPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl vfs.ACL) error {
+	return readOnlyError(fmt.Sprintf("create %q", p.inner.Path()))
+}
+
+func (p *readOnlyPath) Remove(ctx context.Context) error {
+	return readOnlyError(fmt.Sprintf("remove %q", p.inner.Path()))
+}
+
+func (p *readOnlyPath) RemoveAll(ctx context.Context) error {
+	return readOnlyError(fmt.Sprintf("remove %q", p.inner.Path()))
+}
+
+func (p *readOnlyPath) RemoveAllVersions(ctx context.Context) error {
+	return readOnlyError(fmt.Sprintf("remove %q", p.inner.Path()))
+}
+
+func newReadOnlyPaths(paths []vfs.Path) []vfs.Path {
+	for i := range paths {
+		paths[i] = newReadOnlyPath(paths[i])
+	}
+	return paths
+}
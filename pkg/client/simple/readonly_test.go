@@ -0,0 +1,126 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simple_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestReadOnlyClientset(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building path: %v", err)
+	}
+	inner := vfsclientset.NewVFSClientset(vfs.Context, basePath)
+
+	clusterYAML := "apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: minimal.example.com\n"
+	if err := basePath.Join("minimal.example.com", "config").WriteFile(ctx, strings.NewReader(clusterYAML), nil); err != nil {
+		t.Fatalf("error writing cluster: %v", err)
+	}
+
+	clientset := simple.NewReadOnlyClientset(inner)
+
+	cluster, err := clientset.GetCluster(ctx, "minimal.example.com")
+	if err != nil {
+		t.Fatalf("unexpected error getting cluster: %v", err)
+	}
+	if _, err := clientset.ListClusters(ctx, metav1.ListOptions{}); err != nil {
+		t.Errorf("unexpected error listing clusters: %v", err)
+	}
+	if _, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{}); err != nil {
+		t.Errorf("unexpected error listing instance groups: %v", err)
+	}
+
+	if _, err := clientset.CreateCluster(ctx, cluster); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly creating cluster, got %v", err)
+	}
+	if _, err := clientset.UpdateCluster(ctx, cluster, nil); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly updating cluster, got %v", err)
+	}
+	if err := clientset.DeleteCluster(ctx, cluster); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly deleting cluster, got %v", err)
+	}
+
+	ig := &kops.InstanceGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodes"},
+	}
+	if _, err := clientset.InstanceGroupsFor(cluster).Create(ctx, ig, metav1.CreateOptions{}); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly creating instance group, got %v", err)
+	}
+	if err := clientset.InstanceGroupsFor(cluster).Delete(ctx, ig.Name, metav1.DeleteOptions{}); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly deleting instance group, got %v", err)
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		t.Fatalf("error getting config base: %v", err)
+	}
+	if _, err := configBase.Join("config").ReadFile(ctx); err != nil {
+		t.Errorf("unexpected error reading config: %v", err)
+	}
+	if err := configBase.Join("config").WriteFile(ctx, strings.NewReader("overwritten"), nil); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly writing config, got %v", err)
+	}
+	if err := configBase.RemoveAll(ctx); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly removing config base, got %v", err)
+	}
+	configs, err := configBase.ReadTree(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error listing config base: %v", err)
+	}
+	for _, config := range configs {
+		if err := config.Remove(ctx); !errors.Is(err, simple.ErrReadOnly) {
+			t.Errorf("expected ErrReadOnly removing %s, got %v", config, err)
+		}
+	}
+
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		t.Fatalf("error building secret store: %v", err)
+	}
+	hasVFSPath, ok := secretStore.(fi.HasVFSPath)
+	if !ok {
+		t.Fatalf("expected secret store to expose its VFS path")
+	}
+	if err := hasVFSPath.VFSPath().Join("test").WriteFile(ctx, strings.NewReader("value"), nil); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly writing to the secret store path, got %v", err)
+	}
+	if _, err := secretStore.ReplaceSecret("test", &fi.Secret{Data: []byte("value")}); !errors.Is(err, simple.ErrReadOnly) {
+		t.Errorf("expected ErrReadOnly replacing secret, got %v", err)
+	}
+
+	// The wrapped clientset must not have written anything to the state store.
+	if _, err := inner.GetCluster(ctx, cluster.Name); err != nil {
+		t.Errorf("cluster was removed through read-only clientset: %v", err)
+	}
+	if data, err := basePath.Join("minimal.example.com", "config").ReadFile(ctx); err != nil || string(data) != clusterYAML {
+		t.Errorf("cluster config was modified through read-only clientset: %q, %v", data, err)
+	}
+}
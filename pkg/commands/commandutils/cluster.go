@@ -33,7 +33,7 @@ func CompleteClusterName(f Factory, suppressIfArgs bool, suppressArgs bool) func
 
 		ConfigureKlogForCompletion()
 
-		client, err := f.KopsClient()
+		client, err := f.KopsClientWithContext(ctx)
 		if err != nil {
 			return CompletionError("getting clientset", err)
 		}
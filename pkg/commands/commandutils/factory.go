@@ -17,11 +17,14 @@ limitations under the License.
 package commandutils
 
 import (
+	"context"
+
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/util/pkg/vfs"
 )
 
 type Factory interface {
 	KopsClient() (simple.Clientset, error)
+	KopsClientWithContext(ctx context.Context) (simple.Clientset, error)
 	VFSContext() *vfs.VFSContext
 }
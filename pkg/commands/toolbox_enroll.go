@@ -84,7 +84,7 @@ func RunToolboxEnroll(ctx context.Context, f commandutils.Factory, out io.Writer
 	if options.InstanceGroup == "" {
 		return fmt.Errorf("instance-group is required")
 	}
-	clientset, err := f.KopsClient()
+	clientset, err := f.KopsClientWithContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -19,46 +19,155 @@ package dump
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"log"
 	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/net/proxy"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/klog/v2"
 )
 
+const (
+	// DefaultDialTimeout is the default timeout for establishing a TCP connection directly to a node
+	DefaultDialTimeout = 5 * time.Second
+	// DefaultBastionDialTimeout is the default timeout for establishing a TCP connection to the bastion,
+	// which is longer because nodes behind a bastion are often further away.
+	DefaultBastionDialTimeout = 15 * time.Second
+	// DefaultCaptureConcurrency is the default number of commands run concurrently on each node,
+	// well below the default limit of 10 sessions per connection of sshd.
+	DefaultCaptureConcurrency = 4
+
+	// sessionCloseTimeout is how long we wait for an aborted command to terminate,
+	// before giving up on it and moving on
+	sessionCloseTimeout = 5 * time.Second
+)
+
+// JournalCapture selects which systemd journals are captured from each node
+type JournalCapture string
+
+const (
+	// JournalCaptureAll captures the full journal as well as the journal of each known service
+	JournalCaptureAll JournalCapture = "all"
+	// JournalCaptureFull captures only the full journal
+	JournalCaptureFull JournalCapture = "full"
+	// JournalCaptureServices captures only the journal of each known service
+	JournalCaptureServices JournalCapture = "services"
+)
+
+// JournalCaptures are the supported values of JournalCapture
+var JournalCaptures = []JournalCapture{JournalCaptureAll, JournalCaptureFull, JournalCaptureServices}
+
+// JournaldFormats are the output formats of journalctl that can be selected with WithJournaldFormat
+var JournaldFormats = []string{
+	"short", "short-full", "short-iso", "short-iso-precise", "short-precise", "short-monotonic", "short-delta", "short-unix",
+	"verbose", "export", "json", "json-pretty", "json-sse", "json-seq", "cat", "with-unit",
+}
+
+// NodeDumpResult records the outcome of dumping the logs of a single node
+type NodeDumpResult struct {
+	// Name is the name of the node, or its IP address if it is not registered in kubernetes
+	Name string
+	// Address is the IP address used to connect to the node
+	Address string
+	// Registered is true if the node was found through the Kubernetes APIs
+	Registered bool
+	// Connected is true if we were able to connect to the node over SSH
+	Connected bool
+	// Err is the error that prevented connecting to the node, if any
+	Err error
+	// CollectionErrors is the number of logs that could not be collected from a connected node.
+	// Failing to collect logs is not considered a failure to dump the node.
+	CollectionErrors int
+	// BytesCaptured is the total size of the logs collected from the node
+	BytesCaptured int64
+	// FailedUnits are the systemd units in a failed state on the node, or nil if they could not be determined
+	FailedUnits []string
+}
+
 // logDumper gets all the nodes from a kubernetes cluster and dumps a well-known set of logs
 type logDumper struct {
 	sshClientFactory sshClientFactory
 
-	artifactsDir string
+	sink ArtifactSink
+
+	journalCapture JournalCapture
+	// journaldFormat is the output format of all journal captures; if empty, each capture uses its own default
+	journaldFormat string
+
+	userForNode func(node *corev1.Node) string
+
+	captureClusterEvents bool
+
+	preservePaths bool
+
+	// followSymlinks lists the files under /var/log through symlinks too
+	followSymlinks bool
 
-	services     []string
-	files        []string
-	podSelectors []string
+	commandTimeout time.Duration
+
+	// captureConcurrency is the number of commands run concurrently on each node
+	captureConcurrency int
+
+	controllerPprofAddress string
+
+	// redactConfigs removes secrets such as tokens from the config files captured from each node
+	redactConfigs bool
+
+	// checksums writes a .sha256 sidecar next to each captured file
+	checksums bool
+
+	progress *progressStream
+
+	nodeSelector  labels.Selector
+	nodeTaintKeys []string
+
+	services       []string
+	files          []string
+	bootstrapFiles []string
+	configFiles    []string
+	podSelectors   []string
 }
 
 // NewLogDumper is the constructor for a logDumper
 func NewLogDumper(bastionAddress string, sshConfig *ssh.ClientConfig, keyRing agent.Agent, artifactsDir string) *logDumper {
 	sshClientFactory := &sshClientFactoryImplementation{
-		keyRing:   keyRing,
-		sshConfig: sshConfig,
+		keyRing:            keyRing,
+		sshConfig:          sshConfig,
+		dialTimeout:        DefaultDialTimeout,
+		bastionDialTimeout: DefaultBastionDialTimeout,
+
+		strictHostKeyChecking: "yes",
 	}
 	if bastionAddress != "" {
 		log.Printf("detected a bastion instance, with the address: %s", bastionAddress)
-		sshClientFactory.bastion = bastionAddress
+		sshClientFactory.jumpHosts = []string{bastionAddress}
 	}
 
 	d := &logDumper{
-		sshClientFactory: sshClientFactory,
-		artifactsDir:     artifactsDir,
+		sshClientFactory:   sshClientFactory,
+		sink:               &dirSink{dir: artifactsDir},
+		journalCapture:     JournalCaptureAll,
+		captureConcurrency: DefaultCaptureConcurrency,
+		redactConfigs:      true,
 	}
 
 	d.services = []string{
@@ -91,6 +200,15 @@ func NewLogDumper(bastionAddress string, sshConfig *ssh.ClientConfig, keyRing ag
 		"aws-routed-eni/ipamd",
 		"aws-routed-eni/plugin",
 	}
+	d.bootstrapFiles = []string{
+		"/var/log/cloud-init-output.log",
+		"/var/log/nodeup.log",
+	}
+	d.configFiles = []string{
+		"/var/lib/kubelet/config.yaml",
+		"/etc/kubernetes/kubelet.conf",
+		"/etc/containerd/config.toml",
+	}
 	d.podSelectors = []string{
 		"k8s-app=external-dns",
 		"k8s-app=dns-controller",
@@ -99,37 +217,283 @@ func NewLogDumper(bastionAddress string, sshConfig *ssh.ClientConfig, keyRing ag
 	return d
 }
 
+// WithJournalCapture selects which systemd journals are captured from each node.
+// Capturing only one of them speeds up dumping busy nodes.
+func (d *logDumper) WithJournalCapture(journalCapture JournalCapture) *logDumper {
+	d.journalCapture = journalCapture
+	return d
+}
+
+// WithJournaldFormat sets the journalctl output format of all journal captures, e.g. json to feed them into a structured pipeline.
+// An empty format keeps the defaults: short-precise for the kernel log and the full journal, and cat for the services.
+func (d *logDumper) WithJournaldFormat(format string) (*logDumper, error) {
+	if format != "" && !slices.Contains(JournaldFormats, format) {
+		return nil, fmt.Errorf("unsupported journald output format %q, must be one of %s", format, strings.Join(JournaldFormats, ", "))
+	}
+	d.journaldFormat = format
+	return d, nil
+}
+
+// journalOutput returns the --output flag of journalctl for a capture with the given default format
+func (d *logDumper) journalOutput(defaultFormat string) string {
+	format := defaultFormat
+	if d.journaldFormat != "" {
+		format = d.journaldFormat
+	}
+	return "--output=" + format
+}
+
+// WithDialTimeout sets the timeout for establishing a TCP connection directly to a node,
+// and through the bastion. A zero value keeps the current timeout.
+// Cancelling the context aborts the connection regardless of the timeout.
+func (d *logDumper) WithDialTimeout(dialTimeout, bastionDialTimeout time.Duration) *logDumper {
+	if f, ok := d.sshClientFactory.(*sshClientFactoryImplementation); ok {
+		if dialTimeout != 0 {
+			f.dialTimeout = dialTimeout
+		}
+		if bastionDialTimeout != 0 {
+			f.bastionDialTimeout = bastionDialTimeout
+		}
+	}
+	return d
+}
+
+// WithCommandTimeout bounds the time each command run on a node may take, so that a single stuck command
+// fails on its own and the dump moves on to the next capture. A zero value disables the timeout.
+func (d *logDumper) WithCommandTimeout(commandTimeout time.Duration) *logDumper {
+	d.commandTimeout = commandTimeout
+	return d
+}
+
+// WithCaptureConcurrency sets the number of commands run concurrently on each node, each in its own session
+// of the SSH connection to the node. A value of 1 runs the captures sequentially.
+func (d *logDumper) WithCaptureConcurrency(captureConcurrency int) *logDumper {
+	if captureConcurrency > 0 {
+		d.captureConcurrency = captureConcurrency
+	}
+	return d
+}
+
+// WithJumpHosts reaches the bastion through a chain of jump hosts, dialed in order,
+// for networks where the bastion itself is not directly reachable.
+// Without a bastion, nodes that only have a private IP are reached through the last jump host instead.
+// Jump hosts are logged in to as the default user, on port 22 unless the address has a port.
+func (d *logDumper) WithJumpHosts(jumpHosts []string) *logDumper {
+	if len(jumpHosts) == 0 {
+		return d
+	}
+	if f, ok := d.sshClientFactory.(*sshClientFactoryImplementation); ok {
+		f.jumpHosts = append(append([]string{}, jumpHosts...), f.jumpHosts...)
+	}
+	return d
+}
+
+// WithKnownHosts verifies the host keys of the jump hosts, the bastion and the nodes against the known hosts file,
+// instead of using the HostKeyCallback of the ssh.ClientConfig.
+// Nodes reached through the bastion are verified by the ssh command forwarding to them, against a copy of the known hosts
+// uploaded to a temporary file on the bastion for as long as the connection is open.
+// If allowUnknownHosts is set, hosts missing from the known hosts are accepted, but changed host keys are still rejected.
+func (d *logDumper) WithKnownHosts(knownHostsPath string, allowUnknownHosts bool) (*logDumper, error) {
+	knownHosts, err := os.ReadFile(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading known hosts %q: %w", knownHostsPath, err)
+	}
+	callback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading known hosts %q: %w", knownHostsPath, err)
+	}
+
+	hostKeyCallback := callback
+	strictHostKeyChecking := "yes"
+	if allowUnknownHosts {
+		strictHostKeyChecking = "accept-new"
+		hostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			err := callback(hostname, remote, key)
+			var keyErr *knownhosts.KeyError
+			if errors.As(err, &keyErr) && len(keyErr.Want) == 0 {
+				klog.Warningf("accepting unknown SSH host key for %s", hostname)
+				return nil
+			}
+			return err
+		}
+	}
+
+	if err := d.setHostKeyChecking(hostKeyCallback, strictHostKeyChecking, knownHosts); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// WithInsecureIgnoreHostKey accepts any host key from the jump hosts, the bastion and the nodes,
+// leaving the connections open to man-in-the-middle attacks.
+func (d *logDumper) WithInsecureIgnoreHostKey() (*logDumper, error) {
+	if err := d.setHostKeyChecking(ssh.InsecureIgnoreHostKey(), "no", nil); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// setHostKeyChecking sets how the host keys are verified, both by the SSH client and by the ssh command forwarding through the bastion.
+// If knownHosts is nil, the ssh command uses the known hosts of the bastion.
+func (d *logDumper) setHostKeyChecking(hostKeyCallback ssh.HostKeyCallback, strictHostKeyChecking string, knownHosts []byte) error {
+	f, ok := d.sshClientFactory.(*sshClientFactoryImplementation)
+	if !ok {
+		return fmt.Errorf("host key verification cannot be configured for SSH client factory %T", d.sshClientFactory)
+	}
+	sshConfig := *f.sshConfig
+	sshConfig.HostKeyCallback = hostKeyCallback
+	f.sshConfig = &sshConfig
+	f.strictHostKeyChecking = strictHostKeyChecking
+	f.knownHosts = knownHosts
+	return nil
+}
+
+// WithSOCKSProxy opens the TCP connections to the nodes, or to the first jump host or the bastion, through a SOCKS5 proxy,
+// given as socks5://[user:password@]host:port or just host:port.
+// The dial timeouts cover both the connection to the proxy and the one the proxy opens.
+func (d *logDumper) WithSOCKSProxy(proxyAddress string) (*logDumper, error) {
+	if !strings.Contains(proxyAddress, "://") {
+		proxyAddress = "socks5://" + proxyAddress
+	}
+	u, err := url.Parse(proxyAddress)
+	if err != nil {
+		return nil, fmt.Errorf("parsing SOCKS proxy address %q: %w", proxyAddress, err)
+	}
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("unsupported SOCKS proxy scheme %q, must be socks5 or socks5h", u.Scheme)
+	}
+	dialer, err := proxy.FromURL(u, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("building SOCKS proxy dialer for %s: %w", u.Host, err)
+	}
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("SOCKS proxy dialer for %s does not support cancellation", u.Host)
+	}
+
+	if f, ok := d.sshClientFactory.(*sshClientFactoryImplementation); ok {
+		f.socksProxy = contextDialer
+	}
+	return d, nil
+}
+
+// WithTarballOutput streams the artifacts as a gzip-compressed tarball to w,
+// instead of writing them into the artifacts directory.
+// The tarball is finalized when DumpAllNodes returns.
+func (d *logDumper) WithTarballOutput(w io.Writer) *logDumper {
+	d.sink = NewTarSink(w)
+	return d
+}
+
+// WithClusterEvents enables capturing the events of the whole cluster once per dump,
+// from the first reachable control-plane node.
+func (d *logDumper) WithClusterEvents(captureClusterEvents bool) *logDumper {
+	d.captureClusterEvents = captureClusterEvents
+	return d
+}
+
+// WithPreservePaths keeps the directory structure of the files captured from each node,
+// e.g. /var/log/containers/x.log is written to <node>/var/log/containers/x.log instead of <node>/containers_x.log.
+func (d *logDumper) WithPreservePaths(preservePaths bool) *logDumper {
+	d.preservePaths = preservePaths
+	return d
+}
+
+// WithFollowSymlinks selects whether the log files under /var/log are also searched for through symlinks,
+// e.g. when /var/log/containers links to the logs of the container runtime on another volume.
+func (d *logDumper) WithFollowSymlinks(followSymlinks bool) *logDumper {
+	d.followSymlinks = followSymlinks
+	return d
+}
+
+// WithControllerProfiles captures the heap profile and the goroutine stacks of kops-controller
+// from each control-plane node, through its pprof endpoint listening on address (host:port) on the node.
+// Nothing is captured if the endpoint is not reachable; an empty address disables the capture.
+func (d *logDumper) WithControllerProfiles(address string) *logDumper {
+	d.controllerPprofAddress = address
+	return d
+}
+
+// WithRedactedConfigs selects whether secrets such as tokens and passwords are removed from the
+// kubelet and containerd config files captured from each node. Secrets are redacted by default.
+func (d *logDumper) WithRedactedConfigs(redactConfigs bool) *logDumper {
+	d.redactConfigs = redactConfigs
+	return d
+}
+
+// WithChecksums writes a <file>.sha256 sidecar, in the format of sha256sum, next to each captured file.
+// The hash is computed as the file is captured, and the sidecar is written once the file is complete.
+func (d *logDumper) WithChecksums(checksums bool) *logDumper {
+	d.checksums = checksums
+	return d
+}
+
+// WithProgress streams progress events to w, as JSON Lines, while nodes are dumped.
+// This allows wrapping tools to report progress without parsing the log output.
+func (d *logDumper) WithProgress(w io.Writer) *logDumper {
+	d.progress = newProgressStream(w)
+	return d
+}
+
+// WithNodeSelector restricts the registered nodes that are dumped to those matching the label selector,
+// and, if taintKeys is not empty, carrying a taint with one of the keys. A nil selector matches all nodes.
+// Instances that are not registered in kubernetes are dumped regardless.
+func (d *logDumper) WithNodeSelector(selector labels.Selector, taintKeys []string) *logDumper {
+	d.nodeSelector = selector
+	d.nodeTaintKeys = taintKeys
+	return d
+}
+
+// WithUserForNode sets a callback resolving the SSH user used to log in to each node,
+// for clusters where nodes run different OS images. The callback is called with a nil node
+// for instances that are not registered in kubernetes. If it returns an empty string,
+// the user from the SSH client config is used.
+func (d *logDumper) WithUserForNode(userForNode func(node *corev1.Node) string) *logDumper {
+	d.userForNode = userForNode
+	return d
+}
+
 // DumpAllNodes connects to every node from kubectl get nodes and dumps the logs.
 // additionalIPs holds IP addresses of instances found by the deployment tool;
 // if the IPs are not found from kubectl get nodes, then these will be dumped also.
 // This allows for dumping log on nodes even if they don't register as a kubernetes
 // node, or if a node fails to register, or if the whole cluster fails to start.
-func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, maxNodesToDump int, additionalIPs, additionalPrivateIPs []string) error {
-	var special, regular, dumped []*corev1.Node
+// A result is returned for each node we attempted to dump, so that callers can tell
+// nodes that could not be reached apart from nodes where some logs could not be collected.
+func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, maxNodesToDump int, additionalIPs, additionalPrivateIPs []string) (results []NodeDumpResult, err error) {
+	var special, regular, dumped, skipped []*corev1.Node
+
+	// Always finalize the artifacts, even if we could not dump some nodes
+	defer func() {
+		if finishErr := d.finish(results); finishErr != nil && err == nil {
+			err = fmt.Errorf("error finalizing artifacts: %w", finishErr)
+		}
+	}()
 
 	log.Printf("starting to dump %d nodes fetched through the Kubernetes APIs", len(nodes.Items))
 	for i := range nodes.Items {
 		node := &nodes.Items[i]
 
-		if _, ok := node.Labels["node-role.kubernetes.io/master"]; ok {
-			special = append(special, node)
-			continue
-		}
-		if _, ok := node.Labels["node-role.kubernetes.io/control-plane"]; ok {
-			special = append(special, node)
+		if !d.nodeSelected(node) {
+			skipped = append(skipped, node)
 			continue
 		}
-		if _, ok := node.Labels["node-role.kubernetes.io/api-server"]; ok {
+
+		if isControlPlaneNode(node) {
 			special = append(special, node)
 			continue
 		}
 
 		regular = append(regular, node)
 	}
+	if len(skipped) > 0 {
+		log.Printf("skipping %d nodes not matching the node selector", len(skipped))
+	}
 
 	for i := range special {
 		node := special[i]
-		err := d.dumpRegistered(ctx, node)
+		result, err := d.dumpRegistered(ctx, node)
+		results = append(results, result)
 		if err != nil {
 			log.Printf("could not dump node %s: %v", node.Name, err)
 		} else {
@@ -137,13 +501,18 @@ func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, max
 		}
 	}
 
+	if d.captureClusterEvents {
+		d.dumpClusterEvents(ctx, dumped)
+	}
+
 	for i := range regular {
 		if len(dumped) >= maxNodesToDump {
 			log.Printf("stopping dumping nodes: %d nodes dumped", maxNodesToDump)
-			return nil
+			return results, nil
 		}
 		node := regular[i]
-		err := d.dumpRegistered(ctx, node)
+		result, err := d.dumpRegistered(ctx, node)
+		results = append(results, result)
 		if err != nil {
 			log.Printf("could not dump node %s: %v", node.Name, err)
 		} else {
@@ -151,39 +520,140 @@ func (d *logDumper) DumpAllNodes(ctx context.Context, nodes corev1.NodeList, max
 		}
 	}
 
-	notDumped := findInstancesNotDumped(additionalIPs, dumped)
+	// Registered nodes that were not selected must not be dumped through their IPs either
+	known := append(append([]*corev1.Node{}, dumped...), skipped...)
+
+	notDumped := findInstancesNotDumped(additionalIPs, known)
 	for _, ip := range notDumped {
 		if len(dumped) >= maxNodesToDump {
 			log.Printf("stopping dumping nodes: %d nodes dumped", maxNodesToDump)
-			return nil
+			return results, nil
 		}
-		err := d.dumpNotRegistered(ctx, ip, false)
+		result, err := d.dumpNotRegistered(ctx, ip, false)
+		results = append(results, result)
 		if err != nil {
-			return err
+			return results, err
 		}
 	}
 
-	notDumped = findInstancesNotDumped(additionalPrivateIPs, dumped)
+	notDumped = findInstancesNotDumped(additionalPrivateIPs, known)
 	for _, ip := range notDumped {
 		if len(dumped) >= maxNodesToDump {
 			log.Printf("stopping dumping nodes: %d nodes dumped", maxNodesToDump)
-			return nil
+			return results, nil
 		}
-		err := d.dumpNotRegistered(ctx, ip, true)
+		result, err := d.dumpNotRegistered(ctx, ip, true)
+		results = append(results, result)
 		if err != nil {
-			return err
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// DumpByIPs dumps logs from the instances with the given IPs, without enumerating the nodes through the
+// Kubernetes APIs. This allows for dumping logs when the API server is not reachable at all.
+// If useBastion is true, the instances are reached through the bastion.
+func (d *logDumper) DumpByIPs(ctx context.Context, ips []string, useBastion bool) (results []NodeDumpResult, err error) {
+	// Always finalize the artifacts, even if we could not dump some nodes
+	defer func() {
+		if finishErr := d.finish(results); finishErr != nil && err == nil {
+			err = fmt.Errorf("error finalizing artifacts: %w", finishErr)
+		}
+	}()
+
+	log.Printf("starting to dump %d nodes by IP", len(ips))
+	seen := make(map[string]bool)
+	for _, ip := range ips {
+		if seen[ip] {
+			continue
+		}
+		seen[ip] = true
+
+		result, err := d.dumpNotRegistered(ctx, ip, useBastion)
+		results = append(results, result)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// finish writes the summaries of all the dumped nodes, then finalizes the artifacts
+func (d *logDumper) finish(results []NodeDumpResult) error {
+	summaryErr := d.writeFailedUnitsSummary(results)
+	if err := d.sink.Close(); err != nil {
+		return err
+	}
+	return summaryErr
+}
+
+// writeFailedUnitsSummary writes the failed systemd units of all the dumped nodes to a single file,
+// so that e.g. the nodes with a crashed kubelet stand out without opening the directory of each node
+func (d *logDumper) writeFailedUnitsSummary(results []NodeDumpResult) error {
+	var summary bytes.Buffer
+	for _, result := range results {
+		if result.FailedUnits == nil {
+			continue
+		}
+		units := "none"
+		if len(result.FailedUnits) != 0 {
+			units = strings.Join(result.FailedUnits, ", ")
 		}
+		fmt.Fprintf(&summary, "%s: %s\n", result.Name, units)
+	}
+	if summary.Len() == 0 {
+		return nil
 	}
 
+	f, err := d.createFile("failed-units-summary.txt")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(summary.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing file %q: %v", "failed-units-summary.txt", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing file %q: %v", "failed-units-summary.txt", err)
+	}
 	return nil
 }
 
-func (d *logDumper) dumpRegistered(ctx context.Context, node *corev1.Node) error {
+// isControlPlaneNode returns true if the node is a control-plane or api-server node
+func isControlPlaneNode(node *corev1.Node) bool {
+	if node == nil {
+		return false
+	}
+	for _, role := range []string{"master", "control-plane", "api-server"} {
+		if _, ok := node.Labels["node-role.kubernetes.io/"+role]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isWindowsNode returns true if the node runs Windows.
+// Nodes that are not registered are assumed to run Linux.
+func isWindowsNode(node *corev1.Node) bool {
+	return node != nil && node.Labels[corev1.LabelOSStable] == "windows"
+}
+
+func (d *logDumper) dumpRegistered(ctx context.Context, node *corev1.Node) (NodeDumpResult, error) {
 	if ctx.Err() != nil {
 		log.Printf("stopping dumping nodes: %v", ctx.Err())
-		return ctx.Err()
+		return NodeDumpResult{Name: node.Name, Registered: true, Err: ctx.Err()}, ctx.Err()
 	}
 
+	ip, useBastion := nodeAddress(node)
+	return d.dumpNode(ctx, node, node.Name, ip, useBastion)
+}
+
+// nodeAddress returns the address used to connect to the node, preferring the public IP.
+// Nodes that only have a private IP are reached through the bastion.
+func nodeAddress(node *corev1.Node) (string, bool) {
 	var publicIP, privateIP string
 	for _, address := range node.Status.Addresses {
 		if address.Type == "ExternalIP" {
@@ -197,24 +667,70 @@ func (d *logDumper) dumpRegistered(ctx context.Context, node *corev1.Node) error
 	}
 
 	if publicIP != "" {
-		return d.dumpNode(ctx, node.Name, publicIP, false)
-	} else {
-		return d.dumpNode(ctx, node.Name, privateIP, true)
+		return publicIP, false
+	}
+	return privateIP, true
+}
+
+// dumpClusterEvents captures the events of the whole cluster from the first control-plane node
+// we can connect to. Failures are logged, but are not considered an error in dumping the nodes.
+func (d *logDumper) dumpClusterEvents(ctx context.Context, controlPlaneNodes []*corev1.Node) {
+	for _, node := range controlPlaneNodes {
+		if ctx.Err() != nil {
+			return
+		}
+
+		ip, useBastion := nodeAddress(node)
+		var user string
+		if d.userForNode != nil {
+			user = d.userForNode(node)
+		}
+		n, err := d.connectToNode(ctx, node.Name, ip, useBastion, user)
+		if err != nil {
+			log.Printf("could not connect to node %s to capture cluster events: %v", node.Name, err)
+			continue
+		}
+
+		err = n.shellToFile(ctx, "if command -v kubectl &> /dev/null; then kubectl get events -A --sort-by=.lastTimestamp; fi", "cluster-events.log")
+		if err != nil {
+			log.Printf("error capturing cluster events from node %s: %v", node.Name, err)
+		}
+		if err := n.Close(); err != nil {
+			log.Printf("error closing connection: %v", err)
+		}
+		return
 	}
+	log.Printf("no reachable control-plane node to capture cluster events from")
 }
 
-func (d *logDumper) dumpNotRegistered(ctx context.Context, ip string, useBastion bool) error {
+func (d *logDumper) dumpNotRegistered(ctx context.Context, ip string, useBastion bool) (NodeDumpResult, error) {
 	if ctx.Err() != nil {
 		log.Printf("stopping dumping nodes: %v", ctx.Err())
-		return ctx.Err()
+		return NodeDumpResult{Name: ip, Address: ip, Err: ctx.Err()}, ctx.Err()
 	}
 
 	log.Printf("dumping node not registered in kubernetes: %s", ip)
-	err := d.dumpNode(ctx, ip, ip, useBastion)
+	result, err := d.dumpNode(ctx, nil, ip, ip, useBastion)
 	if err != nil {
 		log.Printf("error dumping node %s: %v", ip, err)
 	}
-	return nil
+	return result, nil
+}
+
+// nodeSelected returns true if the node matches the node selector and taint keys.
+func (d *logDumper) nodeSelected(node *corev1.Node) bool {
+	if d.nodeSelector != nil && !d.nodeSelector.Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if len(d.nodeTaintKeys) == 0 {
+		return true
+	}
+	for _, taint := range node.Spec.Taints {
+		if slices.Contains(d.nodeTaintKeys, taint.Key) {
+			return true
+		}
+	}
+	return false
 }
 
 // findInstancesNotDumped returns ips from the slice that do not appear as any address of the nodes
@@ -236,32 +752,81 @@ func findInstancesNotDumped(ips []string, dumped []*corev1.Node) []string {
 }
 
 // DumpNode connects to a node and dumps the logs.
-func (d *logDumper) dumpNode(ctx context.Context, name string, ip string, useBastion bool) error {
+// If the node is not registered (node is nil), the bootstrap logs are also captured explicitly.
+// An error is only returned if we could not connect to the node; the result is populated in either case.
+func (d *logDumper) dumpNode(ctx context.Context, node *corev1.Node, name string, ip string, useBastion bool) (NodeDumpResult, error) {
+	registered := node != nil
+	result := NodeDumpResult{
+		Name:       name,
+		Address:    ip,
+		Registered: registered,
+	}
+
+	start := time.Now()
+	d.progress.emit(ProgressEvent{Event: ProgressNodeStarted, Node: name, Address: ip})
+	defer func() {
+		event := ProgressEvent{
+			Event:            ProgressNodeCompleted,
+			Node:             name,
+			Address:          ip,
+			Connected:        result.Connected,
+			BytesCaptured:    result.BytesCaptured,
+			CollectionErrors: result.CollectionErrors,
+			ElapsedSeconds:   time.Since(start).Seconds(),
+		}
+		if result.Err != nil {
+			event.Error = result.Err.Error()
+		}
+		d.progress.emit(event)
+	}()
+
 	if ip == "" {
-		return fmt.Errorf("could not find address for %v, ", name)
+		result.Err = fmt.Errorf("could not find address for %v, ", name)
+		return result, result.Err
 	}
 
 	log.Printf("Dumping node %s", name)
 
-	n, err := d.connectToNode(ctx, name, ip, useBastion)
+	var user string
+	if d.userForNode != nil {
+		user = d.userForNode(node)
+	}
+
+	n, err := d.connectToNode(ctx, name, ip, useBastion, user)
 	if err != nil {
-		return fmt.Errorf("connecting: %w", err)
+		result.Err = fmt.Errorf("connecting: %w", err)
+		return result, result.Err
 	}
+	result.Connected = true
 
 	// As long as we connect to the node we will not return an error;
 	// a failure to collect a log (or even any logs at all) is not
 	// considered an error in dumping the node.
 	// TODO(justinsb): clean up / rationalize
-	errors := n.dump(ctx)
+	var errors []error
+	if isWindowsNode(node) {
+		errors = n.dumpWindows(ctx)
+	} else {
+		errors = n.dump(ctx)
+	}
+	if !registered {
+		errors = append(errors, n.dumpBootstrap(ctx)...)
+	}
+	if d.controllerPprofAddress != "" && isControlPlaneNode(node) {
+		errors = append(errors, n.dumpControllerProfiles(ctx)...)
+	}
 	for _, e := range errors {
 		log.Printf("error dumping node %s: %v", name, e)
 	}
+	result.CollectionErrors = len(errors)
+	result.BytesCaptured = n.bytesCaptured.Load()
+	result.FailedUnits = n.failedUnits
 
 	if err := n.Close(); err != nil {
 		log.Printf("error closing connection: %v", err)
 	}
 
-	return nil
+	return result, nil
 }
 
 // sshClient is an interface abstracting *ssh.Client, which allows us to test it
@@ -274,7 +839,8 @@ type sshClient interface {
 
 // sshClientFactory is an interface abstracting to a node over SSH
 type sshClientFactory interface {
-	Dial(ctx context.Context, host string, useBastion bool) (sshClient, error)
+	// Dial connects to the host, logging in as user, or the default user if empty
+	Dial(ctx context.Context, host string, useBastion bool, user string) (sshClient, error)
 }
 
 // logDumperNode holds state for a particular node we are dumping
@@ -282,18 +848,27 @@ type logDumperNode struct {
 	client sshClient
 	dumper *logDumper
 
+	name string
+	// dir is the directory of the node's artifacts, relative to the root of the artifacts
 	dir string
+
+	// bytesCaptured is the total size of the files written for the node
+	bytesCaptured atomic.Int64
+
+	// failedUnits are the systemd units found in a failed state, or nil if they were not determined
+	failedUnits []string
 }
 
 // connectToNode makes an SSH connection to the node and returns a logDumperNode
-func (d *logDumper) connectToNode(ctx context.Context, nodeName string, host string, useBastion bool) (*logDumperNode, error) {
-	client, err := d.sshClientFactory.Dial(ctx, host, useBastion)
+func (d *logDumper) connectToNode(ctx context.Context, nodeName string, host string, useBastion bool, user string) (*logDumperNode, error) {
+	client, err := d.sshClientFactory.Dial(ctx, host, useBastion, user)
 	if err != nil {
 		return nil, fmt.Errorf("unable to SSH to %q: %v", host, err)
 	}
 	return &logDumperNode{
 		client: client,
-		dir:    filepath.Join(d.artifactsDir, nodeName),
+		name:   nodeName,
+		dir:    nodeName,
 		dumper: d,
 	}, nil
 }
@@ -303,26 +878,230 @@ func (n *logDumperNode) Close() error {
 	return n.client.Close()
 }
 
-// dump captures the well-known set of logs
+// dump captures the well-known set of logs.
+// The captures are independent of each other, so they run concurrently, each writing its own file.
 func (n *logDumperNode) dump(ctx context.Context) []error {
 	if ctx.Err() != nil {
 		return []error{ctx.Err()}
 	}
 
-	var errors []error
+	g := n.newCaptureGroup()
 
 	// Capture kernel log
-	if err := n.shellToFile(ctx, "sudo journalctl --output=short-precise -k", filepath.Join(n.dir, "kern.log")); err != nil {
-		errors = append(errors, err)
-	}
+	g.shellToFile(ctx, "sudo journalctl "+n.dumper.journalOutput("short-precise")+" -k", filepath.Join(n.dir, "kern.log"))
 
 	// Capture full journal - needed so we can see e.g. disk mounts
 	// This does duplicate the other files, but ensures we have all output
-	if err := n.shellToFile(ctx, "sudo journalctl --output=short-precise", filepath.Join(n.dir, "journal.log")); err != nil {
-		errors = append(errors, err)
+	if n.dumper.journalCapture != JournalCaptureServices {
+		g.shellToFile(ctx, "sudo journalctl "+n.dumper.journalOutput("short-precise"), filepath.Join(n.dir, "journal.log"))
 	}
 
 	// Capture logs from any systemd services in our list that are registered
+	if n.dumper.journalCapture != JournalCaptureFull {
+		g.Go(func() []error {
+			return n.dumpServiceJournals(ctx, g)
+		})
+	}
+
+	// Capture iptables configuration
+	g.shellToFile(ctx, "sudo iptables -t nat --list-rules", filepath.Join(n.dir, "iptables-nat.log"))
+	g.shellToFile(ctx, "sudo iptables -t filter --list-rules", filepath.Join(n.dir, "iptables-filter.log"))
+
+	// Capture the neighbor table and the conntrack statistics, revealing ARP issues and packets dropped by a full conntrack table
+	g.shellToFile(ctx, "ip neigh show", filepath.Join(n.dir, "ip-neigh.log"))
+	g.shellToFile(ctx, "if command -v conntrack &> /dev/null; then sudo conntrack -S; echo \"entries: $(sudo conntrack -L 2> /dev/null | wc -l)\"; fi", filepath.Join(n.dir, "conntrack.log"))
+
+	// Capture the state of the systemd services, so that failed units stand out without reading every journal
+	g.shellToFile(ctx, "sudo systemctl list-units -t service --all --no-pager", filepath.Join(n.dir, "systemd-units.log"))
+	g.Go(func() []error {
+		return n.dumpFailedUnits(ctx)
+	})
+
+	// Capture any file logs where the files exist
+	g.Go(func() []error {
+		fileList, err := n.findFiles(ctx, "/var/log")
+		if err != nil {
+			return []error{fmt.Errorf("error reading /var/log: %v", err)}
+		}
+		for _, name := range n.dumper.files {
+			for _, f := range fileList {
+				if !isLogFile(f, name) {
+					continue
+				}
+				// Rotated and compressed files are captured as-is
+				g.shellToFile(ctx, "sudo cat '"+strings.ReplaceAll(f, "'", "'\\''")+"'", n.capturePath(f))
+			}
+		}
+		return nil
+	})
+
+	for _, selector := range n.dumper.podSelectors {
+		kv := strings.Split(selector, "=")
+		logFile := fmt.Sprintf("%v.log", kv[len(kv)-1])
+		g.shellToFile(ctx, "if command -v kubectl &> /dev/null; then kubectl logs -n kube-system --all-containers -l \""+selector+"\"; fi", filepath.Join(n.dir, logFile))
+	}
+
+	g.shellToFile(ctx, "cat /etc/hosts", filepath.Join(n.dir, "etchosts"))
+
+	// Capture the DNS resolution state, both as seen by pods using the host's resolv.conf and by systemd-resolved
+	g.shellToFile(ctx, "cat /etc/resolv.conf", filepath.Join(n.dir, "resolv.conf"))
+	g.shellToFile(ctx, "if [ -f /run/systemd/resolve/resolv.conf ]; then cat /run/systemd/resolve/resolv.conf; fi", filepath.Join(n.dir, "systemd-resolv.conf"))
+	g.shellToFile(ctx, "if command -v resolvectl &> /dev/null; then resolvectl status --no-pager; fi", filepath.Join(n.dir, "resolvectl.log"))
+	g.shellToFile(ctx, "sysctl -a", filepath.Join(n.dir, "sysctls"))
+
+	// Capture the time synchronization status, as clock skew breaks the validation of certificates and etcd
+	g.shellToFile(ctx, "if command -v timedatectl &> /dev/null; then timedatectl status --no-pager; fi", filepath.Join(n.dir, "timedatectl.log"))
+	g.shellToFile(ctx, "if command -v chronyc &> /dev/null; then chronyc sources; chronyc tracking; fi", filepath.Join(n.dir, "chrony.log"))
+
+	g.Go(func() []error {
+		return n.dumpConfigFiles(ctx)
+	})
+
+	// Capture the disk and inode usage, as a full disk causes evictions that do not show up in the journals
+	g.shellToFile(ctx, "df -h; for d in /var/lib/containerd /var/lib/docker /var/log; do if [ -d $d ]; then sudo du -sh $d; fi; done", filepath.Join(n.dir, "disk-usage.log"))
+	g.shellToFile(ctx, "df -i", filepath.Join(n.dir, "disk-inodes.log"))
+
+	return g.Wait()
+}
+
+// captureGroup runs captures of a node concurrently, bounded by the capture concurrency of the dumper,
+// and collects the errors of each of them
+type captureGroup struct {
+	node *logDumperNode
+
+	wg sync.WaitGroup
+	// slots holds a token for each running capture
+	slots chan struct{}
+
+	// mutex protects errors, as the captures complete concurrently
+	mutex  sync.Mutex
+	errors []error
+}
+
+// newCaptureGroup returns a captureGroup for the captures of the node
+func (n *logDumperNode) newCaptureGroup() *captureGroup {
+	return &captureGroup{
+		node:  n,
+		slots: make(chan struct{}, max(n.dumper.captureConcurrency, 1)),
+	}
+}
+
+// Go schedules the capture once a slot is free.
+// A capture may schedule further captures; it does not need to wait for them.
+func (g *captureGroup) Go(capture func() []error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		g.slots <- struct{}{}
+		errors := capture()
+		<-g.slots
+
+		g.mutex.Lock()
+		defer g.mutex.Unlock()
+		g.errors = append(g.errors, errors...)
+	}()
+}
+
+// shellToFile schedules executing the command and copying its output to a file, relative to the root of the artifacts
+func (g *captureGroup) shellToFile(ctx context.Context, command string, destPath string) {
+	g.Go(func() []error {
+		if err := g.node.shellToFile(ctx, command, destPath); err != nil {
+			return []error{err}
+		}
+		return nil
+	})
+}
+
+// Wait waits for all the scheduled captures, and returns their errors
+func (g *captureGroup) Wait() []error {
+	g.wg.Wait()
+	return g.errors
+}
+
+// windowsKubeletLogDir is the directory of the kubelet logs on Windows nodes
+const windowsKubeletLogDir = `C:\var\log\kubelet`
+
+// dumpWindows captures the well-known set of logs of a Windows node, using PowerShell
+func (n *logDumperNode) dumpWindows(ctx context.Context) []error {
+	if ctx.Err() != nil {
+		return []error{ctx.Err()}
+	}
+
+	var errors []error
+
+	// Capture the event logs, which are the Windows equivalent of the journal
+	for _, logName := range []string{"System", "Application"} {
+		script := "Get-WinEvent -LogName " + logName + " -MaxEvents 10000 | Sort-Object TimeCreated | Format-List TimeCreated,Id,LevelDisplayName,ProviderName,Message"
+		if err := n.shellToFile(ctx, powershell(script), filepath.Join(n.dir, strings.ToLower(logName)+"-events.log")); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	// Capture the network configuration
+	if err := n.shellToFile(ctx, powershell("ipconfig /all"), filepath.Join(n.dir, "ipconfig.log")); err != nil {
+		errors = append(errors, err)
+	}
+	if err := n.shellToFile(ctx, powershell("Get-NetRoute | Format-Table -AutoSize"), filepath.Join(n.dir, "routes.log")); err != nil {
+		errors = append(errors, err)
+	}
+
+	// Capture the state of the services, so that stopped services stand out
+	if err := n.shellToFile(ctx, powershell("Get-Service | Format-Table -AutoSize Status,StartType,Name,DisplayName"), filepath.Join(n.dir, "services.log")); err != nil {
+		errors = append(errors, err)
+	}
+
+	// Capture the kubelet logs
+	fileList, err := n.findWindowsFiles(ctx, windowsKubeletLogDir)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("error reading %s: %v", windowsKubeletLogDir, err))
+	}
+	for _, f := range fileList {
+		name := strings.ReplaceAll(strings.TrimPrefix(f, windowsKubeletLogDir+`\`), `\`, "_")
+		if err := n.shellToFile(ctx, powershell("Get-Content -Raw -LiteralPath "+quotePowerShell(f)), filepath.Join(n.dir, "kubelet", name)); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return errors
+}
+
+// findWindowsFiles lists files under the specified directory of a Windows node (recursively)
+func (n *logDumperNode) findWindowsFiles(ctx context.Context, dir string) ([]string, error) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	script := "if (Test-Path -LiteralPath " + quotePowerShell(dir) + ") { Get-ChildItem -LiteralPath " + quotePowerShell(dir) + " -Recurse -File | ForEach-Object { $_.FullName } }"
+	if err := n.exec(ctx, powershell(script), &stdout, &stderr); err != nil {
+		return nil, fmt.Errorf("error listing %q: %v", dir, err)
+	}
+
+	var paths []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		paths = append(paths, line)
+	}
+	return paths, nil
+}
+
+// powershell returns the command running the PowerShell script on a Windows node.
+// The script must not contain double quotes, as it is passed to the default shell of the node.
+func powershell(script string) string {
+	return `powershell.exe -NoLogo -NoProfile -NonInteractive -Command "` + script + `"`
+}
+
+// quotePowerShell quotes s as a literal PowerShell string
+func quotePowerShell(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// dumpServiceJournals schedules capturing the journal of each systemd service in our list that is registered
+func (n *logDumperNode) dumpServiceJournals(ctx context.Context, g *captureGroup) []error {
+	var errors []error
+
 	services, err := n.listSystemdUnits(ctx)
 	if err != nil {
 		errors = append(errors, fmt.Errorf("error listing systemd services: %v", err))
@@ -331,62 +1110,166 @@ func (n *logDumperNode) dump(ctx context.Context) []error {
 		name := s + ".service"
 		for _, service := range services {
 			if service == name {
-				if err := n.shellToFile(ctx, "sudo journalctl --output=cat -u "+name, filepath.Join(n.dir, s+".log")); err != nil {
-					errors = append(errors, err)
-				}
+				g.shellToFile(ctx, "sudo journalctl "+n.dumper.journalOutput("cat")+" -u "+name, filepath.Join(n.dir, s+".log"))
 			}
 		}
 	}
 
-	// Capture iptables configuration
-	if err := n.shellToFile(ctx, "sudo iptables -t nat --list-rules", filepath.Join(n.dir, "iptables-nat.log")); err != nil {
-		errors = append(errors, err)
+	return errors
+}
+
+// dumpFailedUnits captures the systemd units in a failed state, and records them for the summary of all nodes
+func (n *logDumperNode) dumpFailedUnits(ctx context.Context) []error {
+	command := "sudo systemctl --failed --no-pager"
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	execErr := n.exec(ctx, command, &stdout, &stderr)
+	if err := n.writeFile(filepath.Join(n.dir, "systemd-failed.log"), append(stdout.Bytes(), stderr.Bytes()...)); err != nil {
+		return []error{err}
 	}
-	if err := n.shellToFile(ctx, "sudo iptables -t filter --list-rules", filepath.Join(n.dir, "iptables-filter.log")); err != nil {
-		errors = append(errors, err)
+	if execErr != nil {
+		return []error{fmt.Errorf("error executing command %q: %v", command, execErr)}
 	}
 
-	// Capture any file logs where the files exist
-	fileList, err := n.findFiles(ctx, "/var/log")
-	if err != nil {
-		errors = append(errors, fmt.Errorf("error reading /var/log: %v", err))
+	n.failedUnits = parseFailedUnits(stdout.String())
+	return nil
+}
+
+// parseFailedUnits returns the units listed by systemctl --failed, skipping its header and legend
+func parseFailedUnits(output string) []string {
+	units := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		// Failed units are marked with a bullet, unless --plain is used
+		fields := strings.Fields(strings.TrimLeft(line, "●* "))
+		if len(fields) >= 4 && fields[2] == "failed" {
+			units = append(units, fields[0])
+		}
 	}
-	for _, name := range n.dumper.files {
-		prefix := "/var/log/" + name + ".log"
-		for _, f := range fileList {
-			if !strings.HasPrefix(f, prefix) {
-				continue
-			}
-			if err := n.shellToFile(ctx, "sudo cat '"+strings.ReplaceAll(f, "'", "'\\''")+"'", filepath.Join(n.dir, strings.ReplaceAll(strings.TrimPrefix(f, "/var/log/"), "/", "_"))); err != nil {
-				errors = append(errors, err)
-			}
+	return units
+}
+
+// dumpBootstrap captures the cloud-init and nodeup output of a node.
+// These files may not exist yet on a half-booted node, so each one is tried individually.
+func (n *logDumperNode) dumpBootstrap(ctx context.Context) []error {
+	var errors []error
+
+	captured := 0
+	for _, f := range n.dumper.bootstrapFiles {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if err := n.exec(ctx, "sudo cat '"+strings.ReplaceAll(f, "'", "'\\''")+"'", &stdout, &stderr); err != nil {
+			klog.V(2).Infof("bootstrap log %q not found on node: %v", f, err)
+			continue
+		}
+		if err := n.writeFile(filepath.Join(n.dir, "bootstrap", filepath.Base(f)), stdout.Bytes()); err != nil {
+			errors = append(errors, err)
+			continue
 		}
+		captured++
 	}
 
-	for _, selector := range n.dumper.podSelectors {
-		kv := strings.Split(selector, "=")
-		logFile := fmt.Sprintf("%v.log", kv[len(kv)-1])
-		if err := n.shellToFile(ctx, "if command -v kubectl &> /dev/null; then kubectl logs -n kube-system --all-containers -l \""+selector+"\"; fi", filepath.Join(n.dir, logFile)); err != nil {
+	// nodeup runs as the kops-configuration systemd unit
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := n.exec(ctx, "sudo journalctl "+n.dumper.journalOutput("short-precise")+" --quiet -u kops-configuration.service", &stdout, &stderr); err != nil {
+		klog.V(2).Infof("nodeup journal not found on node: %v", err)
+	} else if stdout.Len() != 0 {
+		if err := n.writeFile(filepath.Join(n.dir, "bootstrap", "nodeup-journal.log"), stdout.Bytes()); err != nil {
 			errors = append(errors, err)
+		} else {
+			captured++
 		}
 	}
 
-	if err := n.shellToFile(ctx, "cat /etc/hosts", filepath.Join(n.dir, "etchosts")); err != nil {
+	if captured == 0 {
+		log.Printf("no bootstrap logs found on node %s (checked %s and the nodeup journal)", n.name, strings.Join(n.dumper.bootstrapFiles, ", "))
+	}
+
+	return errors
+}
+
+// secretConfigLine matches the lines of YAML, TOML and kubeconfig files setting a secret, such as a token or a password
+var secretConfigLine = regexp.MustCompile(`(?im)^(\s*-?\s*"?(?:[\w.-]*(?:token|password|secret|client-key-data)|auth)"?\s*[:=]\s*)\S.*$`)
+
+// redactSecrets replaces the values of the secrets in a config file
+func redactSecrets(data []byte) []byte {
+	return secretConfigLine.ReplaceAll(data, []byte("${1}<redacted>"))
+}
+
+// dumpConfigFiles captures the kubelet and containerd config files of a node, so that misconfigurations
+// can be diagnosed alongside the logs. Not all of the files exist on every node, so each one is tried individually.
+func (n *logDumperNode) dumpConfigFiles(ctx context.Context) []error {
+	var errors []error
+
+	for _, f := range n.dumper.configFiles {
+		var stdout bytes.Buffer
+		var stderr bytes.Buffer
+		if err := n.exec(ctx, "sudo cat '"+strings.ReplaceAll(f, "'", "'\\''")+"'", &stdout, &stderr); err != nil {
+			klog.V(2).Infof("config file %q not found on node: %v", f, err)
+			continue
+		}
+		data := stdout.Bytes()
+		if n.dumper.redactConfigs {
+			data = redactSecrets(data)
+		}
+		if err := n.writeFile(filepath.Join(n.dir, "config", filepath.FromSlash(strings.TrimPrefix(f, "/"))), data); err != nil {
+			errors = append(errors, err)
+		}
+	}
+
+	return errors
+}
+
+// dumpControllerProfiles captures the heap profile and the goroutine stacks of kops-controller.
+// The pprof endpoint is not exposed by default, so nothing is captured if it is not reachable.
+func (n *logDumperNode) dumpControllerProfiles(ctx context.Context) []error {
+	var errors []error
+
+	baseURL := "http://" + n.dumper.controllerPprofAddress + "/debug/pprof/"
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	if err := n.exec(ctx, "curl -sSf --max-time 5 -o /dev/null "+quoteShell(baseURL), &stdout, &stderr); err != nil {
+		log.Printf("kops-controller pprof endpoint not reachable on node %s, not capturing profiles: %v", n.name, err)
+		return nil
+	}
+
+	if err := n.shellToFile(ctx, "curl -sSf --max-time 60 "+quoteShell(baseURL+"heap"), filepath.Join(n.dir, "heap.pprof")); err != nil {
 		errors = append(errors, err)
 	}
-	if err := n.shellToFile(ctx, "sysctl -a", filepath.Join(n.dir, "sysctls")); err != nil {
+	if err := n.shellToFile(ctx, "curl -sSf --max-time 60 "+quoteShell(baseURL+"goroutine?debug=2"), filepath.Join(n.dir, "goroutine.txt")); err != nil {
 		errors = append(errors, err)
 	}
 
 	return errors
 }
 
+// rotatedLogFile matches the suffix, after the name of the log, of the log files rotated by logrotate with a number or a date
+// before the extension, such as kube-apiserver.1.log or kube-apiserver-20240101.log.gz
+var rotatedLogFile = regexp.MustCompile(`^[.-][0-9]+\.log(\.gz)?$`)
+
+// isLogFile returns true if f is the /var/log file of the log with the given name, including its rotated and compressed files,
+// such as kubelet.log, kubelet.log.1, kubelet.log.2.gz and kubelet.1.log.
+func isLogFile(f string, name string) bool {
+	prefix := "/var/log/" + name
+	if !strings.HasPrefix(f, prefix) {
+		return false
+	}
+	suffix := strings.TrimPrefix(f, prefix)
+	return strings.HasPrefix(suffix, ".log") || rotatedLogFile.MatchString(suffix)
+}
+
 // findFiles lists files under the specified directory (recursively)
 func (n *logDumperNode) findFiles(ctx context.Context, dir string) ([]string, error) {
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 
-	err := n.client.ExecPiped(ctx, "sudo find "+dir+" -print0", &stdout, &stderr)
+	command := "sudo find " + dir + " -print0"
+	if n.dumper.followSymlinks {
+		// find reports symlink loops and dangling symlinks as errors, but still lists the other files
+		command = "sudo find -L " + dir + " -print0 || true"
+	}
+	err := n.exec(ctx, command, &stdout, &stderr)
 	if err != nil {
 		return nil, fmt.Errorf("error listing %q: %v", dir, err)
 	}
@@ -407,7 +1290,7 @@ func (n *logDumperNode) listSystemdUnits(ctx context.Context) ([]string, error)
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
 
-	err := n.client.ExecPiped(ctx, "sudo systemctl list-units -t service --no-pager --no-legend --all", &stdout, &stderr)
+	err := n.exec(ctx, "sudo systemctl list-units -t service --no-pager --no-legend --all", &stdout, &stderr)
 	if err != nil {
 		return nil, fmt.Errorf("error listing systemd units: %v", err)
 	}
@@ -423,29 +1306,132 @@ func (n *logDumperNode) listSystemdUnits(ctx context.Context) ([]string, error)
 	return services, nil
 }
 
-// shellToFile executes a command and copies the output to a file
+// exec runs the command on the node, bounded by the command timeout of the dumper
+func (n *logDumperNode) exec(ctx context.Context, command string, stdout io.Writer, stderr io.Writer) error {
+	if n.dumper.commandTimeout == 0 {
+		return n.client.ExecPiped(ctx, command, stdout, stderr)
+	}
+
+	commandCtx, cancel := context.WithTimeout(ctx, n.dumper.commandTimeout)
+	defer cancel()
+
+	err := n.client.ExecPiped(commandCtx, command, stdout, stderr)
+	if err != nil && ctx.Err() == nil && commandCtx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("command timed out after %v", n.dumper.commandTimeout)
+	}
+	return err
+}
+
+// shellToFile executes a command and copies the output to a file, relative to the root of the artifacts
 func (n *logDumperNode) shellToFile(ctx context.Context, command string, destPath string) error {
-	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-		log.Printf("unable to mkdir on %q: %v", filepath.Dir(destPath), err)
+	f, err := n.dumper.createFile(destPath)
+	if err != nil {
+		return err
+	}
+
+	w := &countingWriter{w: f, count: &n.bytesCaptured}
+	execErr := n.exec(ctx, command, w, w)
+	closeErr := f.Close()
+	if execErr != nil {
+		return fmt.Errorf("error executing command %q: %v", command, execErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("error writing file %q: %v", destPath, closeErr)
+	}
+
+	return nil
+}
+
+// capturePath returns the path, relative to the root of the artifacts, to which the /var/log file f is captured
+func (n *logDumperNode) capturePath(f string) string {
+	if n.dumper.preservePaths {
+		return filepath.Join(n.dir, filepath.FromSlash(strings.TrimPrefix(f, "/")))
 	}
+	return filepath.Join(n.dir, strings.ReplaceAll(strings.TrimPrefix(f, "/var/log/"), "/", "_"))
+}
 
-	f, err := os.Create(destPath)
+// writeFile writes the data to a file, relative to the root of the artifacts
+func (n *logDumperNode) writeFile(destPath string, data []byte) error {
+	f, err := n.dumper.createFile(destPath)
 	if err != nil {
-		return fmt.Errorf("error creating file %q: %v", destPath, err)
+		return err
+	}
+	w := &countingWriter{w: f, count: &n.bytesCaptured}
+	if _, err := w.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing file %q: %v", destPath, err)
 	}
-	defer f.Close()
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("error writing file %q: %v", destPath, err)
+	}
+	return nil
+}
 
-	if err := n.client.ExecPiped(ctx, command, f, f); err != nil {
-		return fmt.Errorf("error executing command %q: %v", command, err)
+// createFile returns a writer for the captured file at destPath, relative to the root of the artifacts.
+// If checksums are enabled, the .sha256 sidecar of the file is written when the writer is closed.
+func (d *logDumper) createFile(destPath string) (io.WriteCloser, error) {
+	f, err := d.sink.Create(destPath)
+	if err != nil {
+		return nil, err
+	}
+	if !d.checksums {
+		return f, nil
 	}
+	return &checksumFile{f: f, hash: sha256.New(), sink: d.sink, destPath: destPath}, nil
+}
+
+// checksumFile hashes the bytes written to a captured file, and writes its .sha256 sidecar once the file is closed
+type checksumFile struct {
+	sink     ArtifactSink
+	destPath string
 
+	// mutex keeps the hash in the order of the file, as stdout and stderr of a command are copied concurrently
+	mutex sync.Mutex
+	f     io.WriteCloser
+	hash  hash.Hash
+}
+
+func (c *checksumFile) Write(p []byte) (int, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	n, err := c.f.Write(p)
+	c.hash.Write(p[:n])
+	return n, err
+}
+
+// Close closes the captured file, then writes its sidecar
+func (c *checksumFile) Close() error {
+	if err := c.f.Close(); err != nil {
+		return err
+	}
+
+	sidecarPath := c.destPath + ".sha256"
+	sidecar, err := c.sink.Create(sidecarPath)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(sidecar, "%s  %s\n", hex.EncodeToString(c.hash.Sum(nil)), filepath.Base(c.destPath)); err != nil {
+		sidecar.Close()
+		return fmt.Errorf("error writing file %q: %v", sidecarPath, err)
+	}
+	if err := sidecar.Close(); err != nil {
+		return fmt.Errorf("error writing file %q: %v", sidecarPath, err)
+	}
 	return nil
 }
 
 // sshClientImplementation is the default implementation of sshClient, binding to a *ssh.Client
 type sshClientImplementation struct {
-	client    *ssh.Client
-	forwardTo string
+	client *ssh.Client
+	// jumpClients are the connections to the jump hosts the client hops through, if any
+	jumpClients []*ssh.Client
+	forwardTo   string
+
+	// strictHostKeyChecking is the StrictHostKeyChecking option of the ssh command forwarding to the node
+	strictHostKeyChecking string
+	// knownHostsPath is the file on the bastion holding the known hosts the ssh command forwarding to the node verifies it against,
+	// or empty to use the known hosts of the bastion
+	knownHostsPath string
 }
 
 var _ sshClient = &sshClientImplementation{}
@@ -456,7 +1442,9 @@ func (s *sshClientImplementation) ExecPiped(ctx context.Context, cmd string, std
 		return ctx.Err()
 	}
 
-	finished := make(chan error)
+	// Both channels are buffered, so that the goroutine does not block once we stopped waiting for the command
+	finished := make(chan error, 1)
+	sessions := make(chan *ssh.Session, 1)
 	go func() {
 		session, err := s.client.NewSession()
 		if err != nil {
@@ -464,30 +1452,42 @@ func (s *sshClientImplementation) ExecPiped(ctx context.Context, cmd string, std
 			return
 		}
 		defer session.Close()
+		sessions <- session
+
+		// If the context was done while the session was being opened, the session was not there to abort
+		if ctx.Err() != nil {
+			finished <- ctx.Err()
+			return
+		}
 
 		session.Stdout = stdout
 		session.Stderr = stderr
 
+		command := cmd
 		if s.forwardTo != "" {
-			cmd = fmt.Sprintf("ssh -o 'StrictHostKeyChecking no' %s %s", quoteShell(s.forwardTo), quoteShell(cmd))
+			command = s.forwardedCommand(cmd)
 		}
 
-		klog.V(2).Infof("running SSH command: %v", cmd)
+		klog.V(2).Infof("running SSH command: %v", command)
 
-		finished <- session.Run(cmd)
+		finished <- session.Run(command)
 	}()
 
 	select {
 	case <-ctx.Done():
-		log.Print("closing SSH tcp connection due to context completion")
-
-		// terminate the TCP connection to force a disconnect - we assume everyone is using the same context.
-		// We could make this better by sending a signal on the session, waiting and then closing the session,
-		// and only if we still haven't succeeded then closing the TCP connection.  This is sufficient for our
-		// current usage though - and hopefully that logic will be implemented in the SSH package itself.
-		s.Close()
+		// Only abort this command, keeping the connection for the other commands run on the node
+		select {
+		case session := <-sessions:
+			_ = session.Signal(ssh.SIGKILL)
+			_ = session.Close()
+		default:
+		}
 
-		<-finished // Wait for cancellation
+		select {
+		case <-finished:
+		case <-time.After(sessionCloseTimeout):
+			klog.Warningf("aborted SSH command did not terminate within %v: %v", sessionCloseTimeout, cmd)
+		}
 		return ctx.Err()
 
 	case err := <-finished:
@@ -495,6 +1495,15 @@ func (s *sshClientImplementation) ExecPiped(ctx context.Context, cmd string, std
 	}
 }
 
+// forwardedCommand returns the command run on the bastion to run cmd on the node
+func (s *sshClientImplementation) forwardedCommand(cmd string) string {
+	options := "-o " + quoteShell("StrictHostKeyChecking "+s.strictHostKeyChecking)
+	if s.knownHostsPath != "" {
+		options += " -o " + quoteShell("UserKnownHostsFile "+s.knownHostsPath)
+	}
+	return "ssh " + options + " " + quoteShell(s.forwardTo) + " " + quoteShell(cmd)
+}
+
 func quoteShell(s string) string {
 	var q strings.Builder
 	q.WriteString("'")
@@ -513,31 +1522,60 @@ func quoteShell(s string) string {
 
 // Close implements sshClientImplementation::Close
 func (s *sshClientImplementation) Close() error {
-	return s.client.Close()
+	if s.knownHostsPath != "" {
+		if err := removeFile(s.client, s.knownHostsPath); err != nil {
+			klog.Warningf("error removing known hosts %q from bastion: %v", s.knownHostsPath, err)
+		}
+	}
+	return closeClients(append(append([]*ssh.Client{}, s.jumpClients...), s.client))
 }
 
 // sshClientFactoryImplementation is the default implementation of sshClientFactory
 type sshClientFactoryImplementation struct {
-	bastion   string
+	// jumpHosts are the hosts dialed in order to reach nodes through the bastion, the last one being the bastion itself
+	jumpHosts []string
 	sshConfig *ssh.ClientConfig
 	keyRing   agent.Agent
+
+	dialTimeout        time.Duration
+	bastionDialTimeout time.Duration
+
+	// socksProxy, if set, opens the TCP connections instead of dialing them directly
+	socksProxy proxy.ContextDialer
+
+	// strictHostKeyChecking is the StrictHostKeyChecking option used when forwarding through the bastion
+	strictHostKeyChecking string
+	// knownHosts, if set, are uploaded to the bastion to verify the nodes when forwarding through it
+	knownHosts []byte
 }
 
 var _ sshClientFactory = &sshClientFactoryImplementation{}
 
 // Dial implements sshClientFactory::Dial
-func (f *sshClientFactoryImplementation) Dial(ctx context.Context, host string, useBastion bool) (sshClient, error) {
+func (f *sshClientFactoryImplementation) Dial(ctx context.Context, host string, useBastion bool, user string) (sshClient, error) {
 	var addr string
+	sshConfig := f.sshConfig
+	timeout := f.dialTimeout
 	if useBastion {
-		addr = f.bastion
+		if len(f.jumpHosts) == 0 {
+			return nil, fmt.Errorf("no bastion to reach %s through", host)
+		}
+		addr = f.jumpHosts[0]
+		timeout = f.bastionDialTimeout
+		// We log in to the bastion as the default user, and to the node as the requested user
+		if user != "" {
+			host = user + "@" + host
+		}
 	} else {
 		addr = host
+		if user != "" && user != sshConfig.User {
+			c := *sshConfig
+			c.User = user
+			sshConfig = &c
+		}
 	}
-	addr = net.JoinHostPort(addr, "22")
-	d := net.Dialer{
-		Timeout: 5 * time.Second,
-	}
-	conn, err := d.DialContext(ctx, "tcp", addr)
+	addr = sshAddress(addr)
+	conn, err := f.dialTCP(ctx, addr, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -545,15 +1583,21 @@ func (f *sshClientFactoryImplementation) Dial(ctx context.Context, host string,
 	// We have a TCP connection; we will force-close it to support context cancellation
 
 	var client *ssh.Client
+	var jumpClients []*ssh.Client
+	var knownHostsPath string
 	finished := make(chan error)
 	go func() {
-		c, chans, reqs, err := ssh.NewClientConn(conn, addr, f.sshConfig)
+		c, chans, reqs, err := ssh.NewClientConn(conn, addr, sshConfig)
 		if err == nil {
 			client = ssh.NewClient(c, chans, reqs)
 			if useBastion {
-				err = agent.ForwardToAgent(client, f.keyRing)
-				if err != nil {
-					err = fmt.Errorf("forwarding ssh auth to keyring: %w", err)
+				// Hop through the remaining jump hosts; the last one forwards the commands to the node
+				client, jumpClients, err = f.dialJumpHosts(client)
+				if err == nil {
+					err = agent.ForwardToAgent(client, f.keyRing)
+					if err != nil {
+						err = fmt.Errorf("forwarding ssh auth to keyring: %w", err)
+					}
 				}
 			}
 		}
@@ -571,6 +1615,14 @@ func (f *sshClientFactoryImplementation) Dial(ctx context.Context, host string,
 				finished <- fmt.Errorf("requesting agent forwarding: %w", err)
 				return
 			}
+
+			if f.knownHosts != nil {
+				knownHostsPath, err = uploadKnownHosts(client, f.knownHosts)
+				if err != nil {
+					finished <- fmt.Errorf("uploading known hosts to bastion: %w", err)
+					return
+				}
+			}
 		}
 
 		finished <- err
@@ -590,8 +1642,108 @@ func (f *sshClientFactoryImplementation) Dial(ctx context.Context, host string,
 			host = ""
 		}
 		return &sshClientImplementation{
-			client:    client,
-			forwardTo: host,
+			client:      client,
+			jumpClients: jumpClients,
+			forwardTo:   host,
+
+			strictHostKeyChecking: f.strictHostKeyChecking,
+			knownHostsPath:        knownHostsPath,
 		}, nil
 	}
 }
+
+// uploadKnownHosts writes the known hosts to a new temporary file on the host the client is connected to, and returns its path
+func uploadKnownHosts(client *ssh.Client, knownHosts []byte) (string, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return "", fmt.Errorf("creating ssh session: %w", err)
+	}
+	defer session.Close()
+
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	session.Stdin = bytes.NewReader(knownHosts)
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err := session.Run(`f="$(mktemp)" && cat > "$f" && echo "$f"`); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	path := strings.TrimSpace(stdout.String())
+	if path == "" {
+		return "", fmt.Errorf("no temporary file was created")
+	}
+	return path, nil
+}
+
+// removeFile removes the file from the host the client is connected to
+func removeFile(client *ssh.Client, path string) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return fmt.Errorf("creating ssh session: %w", err)
+	}
+	defer session.Close()
+
+	return session.Run("rm -f " + quoteShell(path))
+}
+
+// dialTCP opens the TCP connection to addr, directly or through the SOCKS proxy
+func (f *sshClientFactoryImplementation) dialTCP(ctx context.Context, addr string, timeout time.Duration) (net.Conn, error) {
+	if f.socksProxy == nil {
+		d := net.Dialer{
+			Timeout: timeout,
+		}
+		return d.DialContext(ctx, "tcp", addr)
+	}
+
+	// The timeout of the dialer only applies to the connection to the proxy, so bound the whole dial instead
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := f.socksProxy.DialContext(dialCtx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s through SOCKS proxy: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// dialJumpHosts hops from the client connected to the first jump host through the remaining ones.
+// It returns the client connected to the last jump host, and the clients of the previous hops.
+func (f *sshClientFactoryImplementation) dialJumpHosts(client *ssh.Client) (*ssh.Client, []*ssh.Client, error) {
+	var jumpClients []*ssh.Client
+	for _, jumpHost := range f.jumpHosts[1:] {
+		addr := sshAddress(jumpHost)
+		conn, err := client.Dial("tcp", addr)
+		if err != nil {
+			closeClients(append(jumpClients, client))
+			return nil, nil, fmt.Errorf("connecting to jump host %s: %w", jumpHost, err)
+		}
+		c, chans, reqs, err := ssh.NewClientConn(conn, addr, f.sshConfig)
+		if err != nil {
+			conn.Close()
+			closeClients(append(jumpClients, client))
+			return nil, nil, fmt.Errorf("logging in to jump host %s: %w", jumpHost, err)
+		}
+		jumpClients = append(jumpClients, client)
+		client = ssh.NewClient(c, chans, reqs)
+	}
+	return client, jumpClients, nil
+}
+
+// closeClients closes the clients, from the last hop to the first one
+func closeClients(clients []*ssh.Client) error {
+	var errs []error
+	for i := len(clients) - 1; i >= 0; i-- {
+		if err := clients[i].Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sshAddress returns the address to dial for the host, defaulting to port 22
+func sshAddress(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, "22")
+}
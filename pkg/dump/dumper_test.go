@@ -0,0 +1,1028 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// fakeSSHClientFactory connects to the hosts with a client, and fails to connect to any other host
+type fakeSSHClientFactory struct {
+	clients map[string]*fakeSSHClient
+
+	// dials records the hosts dialed, in order
+	dials []fakeDial
+}
+
+type fakeDial struct {
+	Host       string
+	UseBastion bool
+}
+
+var _ sshClientFactory = &fakeSSHClientFactory{}
+
+func (f *fakeSSHClientFactory) Dial(ctx context.Context, host string, useBastion bool, user string) (sshClient, error) {
+	f.dials = append(f.dials, fakeDial{Host: host, UseBastion: useBastion})
+	client, found := f.clients[host]
+	if !found {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return client, nil
+}
+
+// fakeSSHClient writes the output of the known commands, and fails to execute any other command.
+// The commands that hang only return once their context is done.
+type fakeSSHClient struct {
+	outputs map[string]string
+	hangs   map[string]bool
+}
+
+var _ sshClient = &fakeSSHClient{}
+
+func (c *fakeSSHClient) ExecPiped(ctx context.Context, command string, stdout io.Writer, stderr io.Writer) error {
+	if c.hangs[command] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	output, found := c.outputs[command]
+	if !found {
+		fmt.Fprintf(stderr, "command not found")
+		return fmt.Errorf("exit status 127")
+	}
+	_, err := io.WriteString(stdout, output)
+	return err
+}
+
+func (c *fakeSSHClient) Close() error {
+	return nil
+}
+
+// newTestLogDumper returns a logDumper writing a tarball to out, which can connect to 10.0.0.1 but not to 10.0.0.2
+func newTestLogDumper(t *testing.T, out io.Writer) *logDumper {
+	t.Helper()
+
+	d := NewLogDumper("", &ssh.ClientConfig{}, nil, t.TempDir()).WithTarballOutput(out)
+	d.sshClientFactory = &fakeSSHClientFactory{
+		clients: map[string]*fakeSSHClient{
+			"10.0.0.1": {
+				outputs: map[string]string{
+					"cat /etc/hosts":                     "127.0.0.1 localhost\n",
+					"sudo systemctl --failed --no-pager": "  UNIT LOAD ACTIVE SUB DESCRIPTION\n● kubelet.service loaded failed failed kubelet\n",
+				},
+			},
+		},
+	}
+	return d
+}
+
+func TestDumpByIPsFinalizesTarballWhenNodeFails(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	d := newTestLogDumper(t, &out)
+
+	results, err := d.DumpByIPs(context.Background(), []string{"10.0.0.1", "10.0.0.2"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !results[0].Connected || results[0].Err != nil {
+		t.Errorf("expected 10.0.0.1 to be dumped, got %+v", results[0])
+	}
+	if results[1].Connected || results[1].Err == nil {
+		t.Errorf("expected 10.0.0.2 to fail, got %+v", results[1])
+	}
+
+	entries := readTarball(t, out.Bytes())
+	if entries["10.0.0.1/etchosts"] != "127.0.0.1 localhost\n" {
+		t.Errorf("unexpected content of 10.0.0.1/etchosts: %q", entries["10.0.0.1/etchosts"])
+	}
+	for name := range entries {
+		if strings.HasPrefix(name, "10.0.0.2/") {
+			t.Errorf("unexpected entry %q for the node that could not be dumped", name)
+		}
+	}
+}
+
+func TestDumpWritesProgress(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	var progress bytes.Buffer
+	d := newTestLogDumper(t, &out).WithProgress(&progress)
+
+	if _, err := d.DumpByIPs(context.Background(), []string{"10.0.0.1", "10.0.0.2"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var events []ProgressEvent
+	scanner := bufio.NewScanner(&progress)
+	for scanner.Scan() {
+		var event ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("error parsing progress event %q: %v", scanner.Text(), err)
+		}
+		if event.Time.IsZero() {
+			t.Errorf("expected the time to be set on progress event %q", scanner.Text())
+		}
+		events = append(events, event)
+	}
+
+	grid := []struct {
+		Event     ProgressEventType
+		Node      string
+		Connected bool
+		Failed    bool
+	}{
+		{Event: ProgressNodeStarted, Node: "10.0.0.1"},
+		{Event: ProgressNodeCompleted, Node: "10.0.0.1", Connected: true},
+		{Event: ProgressNodeStarted, Node: "10.0.0.2"},
+		{Event: ProgressNodeCompleted, Node: "10.0.0.2", Failed: true},
+	}
+	if len(events) != len(grid) {
+		t.Fatalf("expected %d progress events, got %d: %+v", len(grid), len(events), events)
+	}
+	for i, g := range grid {
+		event := events[i]
+		if event.Event != g.Event || event.Node != g.Node || event.Address != g.Node {
+			t.Errorf("expected event %d to be %s for %s, got %+v", i, g.Event, g.Node, event)
+		}
+		if event.Connected != g.Connected {
+			t.Errorf("expected event %d to have connected %v, got %v", i, g.Connected, event.Connected)
+		}
+		if (event.Error != "") != g.Failed {
+			t.Errorf("expected event %d to have failed %v, got error %q", i, g.Failed, event.Error)
+		}
+	}
+	if events[1].BytesCaptured == 0 {
+		t.Errorf("expected bytes to be captured from 10.0.0.1, got %+v", events[1])
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Input    string
+		Expected string
+	}{
+		{
+			Name:     "kubeconfig token",
+			Input:    "users:\n- name: kubelet\n  user:\n    token: abc123\n",
+			Expected: "users:\n- name: kubelet\n  user:\n    token: <redacted>\n",
+		},
+		{
+			Name:     "kubeconfig client key data",
+			Input:    "    client-certificate-data: Y2VydA==\n    client-key-data: a2V5\n",
+			Expected: "    client-certificate-data: Y2VydA==\n    client-key-data: <redacted>\n",
+		},
+		{
+			Name:     "toml password",
+			Input:    "[plugins.\"io.containerd.grpc.v1.cri\".registry.configs.\"registry\".auth]\n  username = \"user\"\n  password = \"hunter2\"\n",
+			Expected: "[plugins.\"io.containerd.grpc.v1.cri\".registry.configs.\"registry\".auth]\n  username = \"user\"\n  password = <redacted>\n",
+		},
+		{
+			Name:     "toml auth",
+			Input:    "  auth = \"dXNlcjpodW50ZXIy\"\n",
+			Expected: "  auth = <redacted>\n",
+		},
+		{
+			Name:     "quoted key",
+			Input:    "\"identity_token\": \"abc\"\n",
+			Expected: "\"identity_token\": <redacted>\n",
+		},
+		{
+			Name:     "list item",
+			Input:    "- secret: abc\n",
+			Expected: "- secret: <redacted>\n",
+		},
+		{
+			Name:     "case insensitive",
+			Input:    "Password: abc\n",
+			Expected: "Password: <redacted>\n",
+		},
+		{
+			Name:     "empty value",
+			Input:    "token:\n",
+			Expected: "token:\n",
+		},
+		{
+			Name:     "no secrets",
+			Input:    "authentication:\n  webhook:\n    enabled: true\nserverTLSBootstrap: true\n",
+			Expected: "authentication:\n  webhook:\n    enabled: true\nserverTLSBootstrap: true\n",
+		},
+		{
+			Name:     "secret in a comment",
+			Input:    "# set the token: below\n",
+			Expected: "# set the token: below\n",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			actual := string(redactSecrets([]byte(g.Input)))
+			if actual != g.Expected {
+				t.Errorf("expected %q, got %q", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDumpWritesChecksums(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	d := newTestLogDumper(t, &out).WithChecksums(true)
+
+	if _, err := d.DumpByIPs(context.Background(), []string{"10.0.0.1"}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := readTarball(t, out.Bytes())
+	if _, found := entries["10.0.0.1/etchosts.sha256"]; !found {
+		t.Fatalf("expected a sidecar for 10.0.0.1/etchosts")
+	}
+	for name, content := range entries {
+		if strings.HasSuffix(name, ".sha256") {
+			if _, found := entries[strings.TrimSuffix(name, ".sha256")]; !found {
+				t.Errorf("unexpected sidecar %q without its file", name)
+			}
+			continue
+		}
+		hash := sha256.Sum256([]byte(content))
+		expected := hex.EncodeToString(hash[:]) + "  " + filepath.Base(name) + "\n"
+		if entries[name+".sha256"] != expected {
+			t.Errorf("expected sidecar of %q to be %q, got %q", name, expected, entries[name+".sha256"])
+		}
+	}
+}
+
+func TestIsLogFile(t *testing.T) {
+	grid := []struct {
+		File     string
+		Name     string
+		Expected bool
+	}{
+		{File: "/var/log/kube-apiserver.log", Name: "kube-apiserver", Expected: true},
+		{File: "/var/log/kube-apiserver.log.1", Name: "kube-apiserver", Expected: true},
+		{File: "/var/log/kube-apiserver.log.2.gz", Name: "kube-apiserver", Expected: true},
+		{File: "/var/log/kube-apiserver.1.log", Name: "kube-apiserver", Expected: true},
+		{File: "/var/log/kube-apiserver-20240101.log", Name: "kube-apiserver", Expected: true},
+		{File: "/var/log/kube-apiserver-20240101.log.gz", Name: "kube-apiserver", Expected: true},
+		{File: "/var/log/kube-apiserver-audit.log", Name: "kube-apiserver", Expected: false},
+		{File: "/var/log/kube-apiserver.1.txt", Name: "kube-apiserver", Expected: false},
+		{File: "/var/log/etcd-events.log", Name: "etcd", Expected: false},
+		{File: "/var/log/etcd-events.log", Name: "etcd-events", Expected: true},
+		{File: "/var/log/aws-routed-eni/ipamd.log", Name: "aws-routed-eni/ipamd", Expected: true},
+		{File: "/var/log/containers/kubelet.log", Name: "kubelet", Expected: false},
+	}
+	for _, g := range grid {
+		t.Run(g.File+"/"+g.Name, func(t *testing.T) {
+			actual := isLogFile(g.File, g.Name)
+			if actual != g.Expected {
+				t.Errorf("expected %v, got %v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestParseFailedUnits(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Output   string
+		Expected []string
+	}{
+		{
+			Name: "no failed units",
+			Output: `  UNIT LOAD ACTIVE SUB DESCRIPTION
+0 loaded units listed.
+`,
+			Expected: []string{},
+		},
+		{
+			Name: "failed units with bullets",
+			Output: `  UNIT                      LOAD   ACTIVE SUB    DESCRIPTION
+● kubelet.service           loaded failed failed kubelet: The Kubernetes Node Agent
+● protokube.service         loaded failed failed Kubernetes Protokube Service
+
+LOAD   = Reflects whether the unit definition was properly loaded.
+ACTIVE = The high-level unit activation state, i.e. generalization of SUB.
+SUB    = The low-level unit activation state, values depend on unit type.
+2 loaded units listed.
+`,
+			Expected: []string{"kubelet.service", "protokube.service"},
+		},
+		{
+			Name: "failed units without bullets",
+			Output: `UNIT            LOAD   ACTIVE SUB    DESCRIPTION
+kubelet.service loaded failed failed kubelet: The Kubernetes Node Agent
+`,
+			Expected: []string{"kubelet.service"},
+		},
+		{
+			Name: "failed units with ascii bullets",
+			Output: `  UNIT            LOAD   ACTIVE SUB    DESCRIPTION
+* kubelet.service loaded failed failed kubelet: The Kubernetes Node Agent
+`,
+			Expected: []string{"kubelet.service"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			actual := parseFailedUnits(g.Output)
+			if !reflect.DeepEqual(actual, g.Expected) {
+				t.Errorf("expected %v, got %v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDumpWritesFailedUnitsSummary(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	d := newTestLogDumper(t, &out)
+
+	results, err := d.DumpByIPs(context.Background(), []string{"10.0.0.1", "10.0.0.2"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(results[0].FailedUnits, []string{"kubelet.service"}) {
+		t.Errorf("expected the failed units of 10.0.0.1 to be [kubelet.service], got %v", results[0].FailedUnits)
+	}
+	if results[1].FailedUnits != nil {
+		t.Errorf("expected the failed units of 10.0.0.2 to be unknown, got %v", results[1].FailedUnits)
+	}
+
+	// Nodes whose failed units could not be determined are left out of the summary
+	entries := readTarball(t, out.Bytes())
+	if entries["failed-units-summary.txt"] != "10.0.0.1: kubelet.service\n" {
+		t.Errorf("unexpected content of failed-units-summary.txt: %q", entries["failed-units-summary.txt"])
+	}
+}
+
+func TestQuotePowerShell(t *testing.T) {
+	grid := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "", Expected: `''`},
+		{Input: `C:\var\log\kubelet`, Expected: `'C:\var\log\kubelet'`},
+		{Input: `$env:TEMP; Restart-Computer`, Expected: `'$env:TEMP; Restart-Computer'`},
+		{Input: `C:\it's.log`, Expected: `'C:\it''s.log'`},
+	}
+	for _, g := range grid {
+		t.Run(g.Input, func(t *testing.T) {
+			actual := quotePowerShell(g.Input)
+			if actual != g.Expected {
+				t.Errorf("expected %q, got %q", g.Expected, actual)
+			}
+		})
+	}
+}
+
+// newTestHostKey returns a new SSH host key
+func newTestHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	key, err := ssh.NewPublicKey(public)
+	if err != nil {
+		t.Fatalf("error converting key: %v", err)
+	}
+	return key
+}
+
+func TestWithKnownHosts(t *testing.T) {
+	knownKey := newTestHostKey(t)
+	otherKey := newTestHostKey(t)
+
+	knownHosts := []byte(knownhosts.Line([]string{"10.0.0.1"}, knownKey) + "\n")
+	knownHostsPath := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHostsPath, knownHosts, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	grid := []struct {
+		Name              string
+		AllowUnknownHosts bool
+		Host              string
+		Key               ssh.PublicKey
+		ExpectedError     bool
+	}{
+		{Name: "known host", Host: "10.0.0.1", Key: knownKey},
+		{Name: "changed host key", Host: "10.0.0.1", Key: otherKey, ExpectedError: true},
+		{Name: "unknown host", Host: "10.0.0.2", Key: otherKey, ExpectedError: true},
+		{Name: "known host allowing unknown hosts", AllowUnknownHosts: true, Host: "10.0.0.1", Key: knownKey},
+		{Name: "changed host key allowing unknown hosts", AllowUnknownHosts: true, Host: "10.0.0.1", Key: otherKey, ExpectedError: true},
+		{Name: "unknown host allowing unknown hosts", AllowUnknownHosts: true, Host: "10.0.0.2", Key: otherKey},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			d, err := NewLogDumper("", &ssh.ClientConfig{}, nil, t.TempDir()).WithKnownHosts(knownHostsPath, g.AllowUnknownHosts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			f := d.sshClientFactory.(*sshClientFactoryImplementation)
+
+			expectedStrictHostKeyChecking := "yes"
+			if g.AllowUnknownHosts {
+				expectedStrictHostKeyChecking = "accept-new"
+			}
+			if f.strictHostKeyChecking != expectedStrictHostKeyChecking {
+				t.Errorf("expected StrictHostKeyChecking %q, got %q", expectedStrictHostKeyChecking, f.strictHostKeyChecking)
+			}
+			if !bytes.Equal(f.knownHosts, knownHosts) {
+				t.Errorf("expected the known hosts to be uploaded to the bastion, got %q", f.knownHosts)
+			}
+
+			remote := &net.TCPAddr{IP: net.ParseIP(g.Host), Port: 22}
+			err = f.sshConfig.HostKeyCallback(net.JoinHostPort(g.Host, "22"), remote, g.Key)
+			if g.ExpectedError && err == nil {
+				t.Errorf("expected the host key to be rejected")
+			}
+			if !g.ExpectedError && err != nil {
+				t.Errorf("expected the host key to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWithInsecureIgnoreHostKey(t *testing.T) {
+	d, err := NewLogDumper("", &ssh.ClientConfig{}, nil, t.TempDir()).WithInsecureIgnoreHostKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f := d.sshClientFactory.(*sshClientFactoryImplementation)
+	if f.strictHostKeyChecking != "no" {
+		t.Errorf("expected StrictHostKeyChecking %q, got %q", "no", f.strictHostKeyChecking)
+	}
+	if f.knownHosts != nil {
+		t.Errorf("expected no known hosts to be uploaded to the bastion, got %q", f.knownHosts)
+	}
+}
+
+func TestForwardedCommand(t *testing.T) {
+	grid := []struct {
+		Name     string
+		Client   sshClientImplementation
+		Expected string
+	}{
+		{
+			Name:     "known hosts of the bastion",
+			Client:   sshClientImplementation{forwardTo: "10.0.0.1", strictHostKeyChecking: "yes"},
+			Expected: `ssh -o 'StrictHostKeyChecking yes' '10.0.0.1' 'cat /etc/hosts'`,
+		},
+		{
+			Name:     "uploaded known hosts",
+			Client:   sshClientImplementation{forwardTo: "ubuntu@10.0.0.1", strictHostKeyChecking: "accept-new", knownHostsPath: "/tmp/tmp.abc"},
+			Expected: `ssh -o 'StrictHostKeyChecking accept-new' -o 'UserKnownHostsFile /tmp/tmp.abc' 'ubuntu@10.0.0.1' 'cat /etc/hosts'`,
+		},
+		{
+			Name:     "insecure",
+			Client:   sshClientImplementation{forwardTo: "10.0.0.1", strictHostKeyChecking: "no"},
+			Expected: `ssh -o 'StrictHostKeyChecking no' '10.0.0.1' 'cat /etc/hosts'`,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			actual := g.Client.forwardedCommand("cat /etc/hosts")
+			if actual != g.Expected {
+				t.Errorf("expected %q, got %q", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestQuoteShell(t *testing.T) {
+	grid := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "", Expected: `''`},
+		{Input: "kubelet", Expected: `'kubelet'`},
+		{Input: "http://127.0.0.1:3993/debug/pprof/goroutine?debug=2", Expected: `'http://127.0.0.1:3993/debug/pprof/goroutine?debug=2'`},
+		{Input: "$(reboot); `reboot`", Expected: "'$(reboot); `reboot`'"},
+		{Input: "it's", Expected: `'it'\''s'`},
+		{Input: `a\b`, Expected: `'a'\\'b'`},
+	}
+	for _, g := range grid {
+		t.Run(g.Input, func(t *testing.T) {
+			actual := quoteShell(g.Input)
+			if actual != g.Expected {
+				t.Errorf("expected %q, got %q", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestExecTimesOutCommand(t *testing.T) {
+	d := NewLogDumper("", &ssh.ClientConfig{}, nil, t.TempDir()).WithCommandTimeout(10 * time.Millisecond)
+	n := &logDumperNode{
+		client: &fakeSSHClient{
+			outputs: map[string]string{"cat /etc/hosts": "127.0.0.1 localhost\n"},
+			hangs:   map[string]bool{"sudo journalctl -k": true},
+		},
+		dumper: d,
+	}
+
+	var stdout bytes.Buffer
+	err := n.exec(context.Background(), "sudo journalctl -k", &stdout, io.Discard)
+	if err == nil || err.Error() != "command timed out after 10ms" {
+		t.Errorf("expected the command to time out, got %v", err)
+	}
+
+	// The next command is not affected by the timed out one
+	if err := n.exec(context.Background(), "cat /etc/hosts", &stdout, io.Discard); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if stdout.String() != "127.0.0.1 localhost\n" {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	// Cancelling the dump is not reported as a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = n.exec(ctx, "sudo journalctl -k", &stdout, io.Discard)
+	if err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestDumpContinuesAfterTimedOutCommand(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	d := newTestLogDumper(t, &out).WithCommandTimeout(10 * time.Millisecond)
+	client := d.sshClientFactory.(*fakeSSHClientFactory).clients["10.0.0.1"]
+	client.hangs = map[string]bool{"sudo iptables -t nat --list-rules": true}
+
+	results, err := d.DumpByIPs(context.Background(), []string{"10.0.0.1"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].Connected || results[0].Err != nil {
+		t.Errorf("expected 10.0.0.1 to be dumped, got %+v", results[0])
+	}
+
+	entries := readTarball(t, out.Bytes())
+	if _, found := entries["10.0.0.1/iptables-nat.log"]; !found {
+		t.Errorf("expected the output of the timed out command to be kept")
+	}
+	if entries["10.0.0.1/etchosts"] != "127.0.0.1 localhost\n" {
+		t.Errorf("unexpected content of 10.0.0.1/etchosts: %q", entries["10.0.0.1/etchosts"])
+	}
+}
+
+// makeTestNode returns a node with the labels and taints, reachable on its external IP
+func makeTestNode(name string, ip string, nodeLabels map[string]string, taintKeys ...string) corev1.Node {
+	node := corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: nodeLabels,
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeExternalIP, Address: ip},
+			},
+		},
+	}
+	for _, key := range taintKeys {
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{Key: key, Effect: corev1.TaintEffectNoSchedule})
+	}
+	return node
+}
+
+func TestNodeSelected(t *testing.T) {
+	grid := []struct {
+		Name      string
+		Selector  string
+		TaintKeys []string
+		Node      corev1.Node
+		Expected  bool
+	}{
+		{
+			Name:     "no selector",
+			Node:     makeTestNode("node", "10.0.0.1", nil),
+			Expected: true,
+		},
+		{
+			Name:     "matching label",
+			Selector: "kops.k8s.io/instancegroup=nodes",
+			Node:     makeTestNode("node", "10.0.0.1", map[string]string{"kops.k8s.io/instancegroup": "nodes"}),
+			Expected: true,
+		},
+		{
+			Name:     "other label",
+			Selector: "kops.k8s.io/instancegroup=nodes",
+			Node:     makeTestNode("node", "10.0.0.1", map[string]string{"kops.k8s.io/instancegroup": "gpu"}),
+			Expected: false,
+		},
+		{
+			Name:      "matching taint",
+			TaintKeys: []string{"nvidia.com/gpu", "dedicated"},
+			Node:      makeTestNode("node", "10.0.0.1", nil, "dedicated"),
+			Expected:  true,
+		},
+		{
+			Name:      "other taint",
+			TaintKeys: []string{"nvidia.com/gpu"},
+			Node:      makeTestNode("node", "10.0.0.1", nil, "dedicated"),
+			Expected:  false,
+		},
+		{
+			Name:      "no taint",
+			TaintKeys: []string{"nvidia.com/gpu"},
+			Node:      makeTestNode("node", "10.0.0.1", nil),
+			Expected:  false,
+		},
+		{
+			Name:      "matching label and taint",
+			Selector:  "kops.k8s.io/instancegroup=gpu",
+			TaintKeys: []string{"nvidia.com/gpu"},
+			Node:      makeTestNode("node", "10.0.0.1", map[string]string{"kops.k8s.io/instancegroup": "gpu"}, "nvidia.com/gpu"),
+			Expected:  true,
+		},
+		{
+			Name:      "matching taint but other label",
+			Selector:  "kops.k8s.io/instancegroup=gpu",
+			TaintKeys: []string{"nvidia.com/gpu"},
+			Node:      makeTestNode("node", "10.0.0.1", map[string]string{"kops.k8s.io/instancegroup": "nodes"}, "nvidia.com/gpu"),
+			Expected:  false,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			var selector labels.Selector
+			if g.Selector != "" {
+				var err error
+				selector, err = labels.Parse(g.Selector)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			d := NewLogDumper("", &ssh.ClientConfig{}, nil, t.TempDir()).WithNodeSelector(selector, g.TaintKeys)
+			actual := d.nodeSelected(&g.Node)
+			if actual != g.Expected {
+				t.Errorf("expected %v, got %v", g.Expected, actual)
+			}
+		})
+	}
+}
+
+func TestDumpAllNodesSkipsNodesNotSelected(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	selector, err := labels.Parse("kops.k8s.io/instancegroup=nodes")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	d := newTestLogDumper(t, &out).WithNodeSelector(selector, nil)
+
+	nodes := corev1.NodeList{
+		Items: []corev1.Node{
+			makeTestNode("selected", "10.0.0.1", map[string]string{"kops.k8s.io/instancegroup": "nodes"}),
+			makeTestNode("skipped", "10.0.0.2", map[string]string{"kops.k8s.io/instancegroup": "gpu"}),
+		},
+	}
+	// Instances that are not registered are dumped regardless of the selector,
+	// but the registered nodes that were skipped are not dumped through their IPs either
+	results, err := d.DumpAllNodes(context.Background(), nodes, 10, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var dumped []string
+	for _, result := range results {
+		dumped = append(dumped, result.Name)
+	}
+	if !reflect.DeepEqual(dumped, []string{"selected", "10.0.0.3"}) {
+		t.Errorf("expected nodes [selected 10.0.0.3] to be dumped, got %v", dumped)
+	}
+	expectedDials := []fakeDial{{Host: "10.0.0.1"}, {Host: "10.0.0.3"}}
+	if dials := d.sshClientFactory.(*fakeSSHClientFactory).dials; !reflect.DeepEqual(dials, expectedDials) {
+		t.Errorf("expected dials %v, got %v", expectedDials, dials)
+	}
+}
+
+func TestDumpByIPsDumpsEachInstanceOnce(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	var out bytes.Buffer
+	d := newTestLogDumper(t, &out)
+	client := d.sshClientFactory.(*fakeSSHClientFactory).clients["10.0.0.1"]
+	client.outputs["sudo cat '/var/log/nodeup.log'"] = "nodeup\n"
+
+	results, err := d.DumpByIPs(context.Background(), []string{"10.0.0.1", "10.0.0.2", "10.0.0.1"}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for _, result := range results {
+		if result.Registered {
+			t.Errorf("expected %s to be dumped as an instance not registered in kubernetes", result.Name)
+		}
+	}
+	expectedDials := []fakeDial{{Host: "10.0.0.1", UseBastion: true}, {Host: "10.0.0.2", UseBastion: true}}
+	if dials := d.sshClientFactory.(*fakeSSHClientFactory).dials; !reflect.DeepEqual(dials, expectedDials) {
+		t.Errorf("expected dials %v, got %v", expectedDials, dials)
+	}
+
+	// The bootstrap logs are captured, as the instances are not known to have registered
+	entries := readTarball(t, out.Bytes())
+	if entries["10.0.0.1/bootstrap/nodeup.log"] != "nodeup\n" {
+		t.Errorf("unexpected content of 10.0.0.1/bootstrap/nodeup.log: %q", entries["10.0.0.1/bootstrap/nodeup.log"])
+	}
+}
+
+// testSSHServer is an SSH server accepting any client, which forwards TCP connections
+// and records the commands run on it instead of executing them
+type testSSHServer struct {
+	addr    string
+	hostKey ssh.PublicKey
+	config  *ssh.ServerConfig
+
+	// mutex protects the fields below, as the connections are served concurrently
+	mutex sync.Mutex
+	// forwards are the addresses connections were forwarded to
+	forwards []string
+	// commands are the commands run
+	commands []string
+	// uploads are the files written to temporary files
+	uploads []string
+}
+
+// knownHostsPath is the path to which testSSHServer writes uploaded files
+const knownHostsPath = "/tmp/tmp.known_hosts"
+
+func newTestSSHServer(t *testing.T) *testSSHServer {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(private)
+	if err != nil {
+		t.Fatalf("error converting key: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("error listening: %v", err)
+	}
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	s := &testSSHServer{
+		addr:    listener.Addr().String(),
+		hostKey: signer.PublicKey(),
+		config:  &ssh.ServerConfig{NoClientAuth: true},
+	}
+	s.config.AddHostKey(signer)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *testSSHServer) serve(conn net.Conn) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		switch newChannel.ChannelType() {
+		case "direct-tcpip":
+			go s.forward(newChannel)
+		case "session":
+			go s.session(newChannel)
+		default:
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+		}
+	}
+}
+
+// forward connects the channel to the address it was opened for
+func (s *testSSHServer) forward(newChannel ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChannel.ExtraData(), &payload); err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	addr := net.JoinHostPort(payload.Host, fmt.Sprintf("%d", payload.Port))
+	target, err := net.Dial("tcp", addr)
+	if err != nil {
+		newChannel.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	s.mutex.Lock()
+	s.forwards = append(s.forwards, addr)
+	s.mutex.Unlock()
+
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		target.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	go func() {
+		io.Copy(channel, target)
+		channel.Close()
+	}()
+	io.Copy(target, channel)
+	target.Close()
+}
+
+// session records the command run in the session, and writes the uploaded file or the output of the command
+func (s *testSSHServer) session(newChannel ssh.NewChannel) {
+	channel, reqs, err := newChannel.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+
+	for req := range reqs {
+		switch req.Type {
+		case "auth-agent-req@openssh.com":
+			req.Reply(true, nil)
+		case "exec":
+			var payload struct {
+				Command string
+			}
+			if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+				req.Reply(false, nil)
+				continue
+			}
+			req.Reply(true, nil)
+
+			output := "127.0.0.1 localhost\n"
+			var upload []byte
+			if strings.Contains(payload.Command, "mktemp") {
+				upload, _ = io.ReadAll(channel)
+				output = knownHostsPath + "\n"
+			}
+
+			s.mutex.Lock()
+			s.commands = append(s.commands, payload.Command)
+			if upload != nil {
+				s.uploads = append(s.uploads, string(upload))
+			}
+			s.mutex.Unlock()
+
+			io.WriteString(channel, output)
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+			return
+		default:
+			if req.WantReply {
+				req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func TestDialThroughJumpHosts(t *testing.T) {
+	jumpHost := newTestSSHServer(t)
+	bastion := newTestSSHServer(t)
+
+	knownHosts := knownhosts.Line([]string{jumpHost.addr}, jumpHost.hostKey) + "\n" +
+		knownhosts.Line([]string{bastion.addr}, bastion.hostKey) + "\n"
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHostsFile, []byte(knownHosts), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := NewLogDumper(bastion.addr, &ssh.ClientConfig{User: "admin"}, agent.NewKeyring(), t.TempDir()).
+		WithJumpHosts([]string{jumpHost.addr}).
+		WithKnownHosts(knownHostsFile, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client, err := d.sshClientFactory.Dial(context.Background(), "10.0.0.1", true, "ubuntu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var stdout bytes.Buffer
+	if err := client.ExecPiped(context.Background(), "cat /etc/hosts", &stdout, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stdout.String() != "127.0.0.1 localhost\n" {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	// The bastion is reached through the jump host, and runs the commands forwarded to the node
+	jumpHost.mutex.Lock()
+	defer jumpHost.mutex.Unlock()
+	if !reflect.DeepEqual(jumpHost.forwards, []string{bastion.addr}) {
+		t.Errorf("expected the jump host to forward to %v, got %v", []string{bastion.addr}, jumpHost.forwards)
+	}
+	if len(jumpHost.commands) != 0 {
+		t.Errorf("expected no commands to be run on the jump host, got %v", jumpHost.commands)
+	}
+
+	bastion.mutex.Lock()
+	defer bastion.mutex.Unlock()
+	expectedCommands := []string{
+		`f="$(mktemp)" && cat > "$f" && echo "$f"`,
+		`ssh -o 'StrictHostKeyChecking yes' -o 'UserKnownHostsFile /tmp/tmp.known_hosts' 'ubuntu@10.0.0.1' 'cat /etc/hosts'`,
+		`rm -f '/tmp/tmp.known_hosts'`,
+	}
+	if !reflect.DeepEqual(bastion.commands, expectedCommands) {
+		t.Errorf("expected commands %q on the bastion, got %q", expectedCommands, bastion.commands)
+	}
+	if !reflect.DeepEqual(bastion.uploads, []string{knownHosts}) {
+		t.Errorf("expected the known hosts to be uploaded to the bastion, got %q", bastion.uploads)
+	}
+}
+
+func TestDialThroughJumpHostsRejectsUnknownJumpHost(t *testing.T) {
+	jumpHost := newTestSSHServer(t)
+	bastion := newTestSSHServer(t)
+
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(knownHostsFile, []byte(knownhosts.Line([]string{bastion.addr}, bastion.hostKey)+"\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	d, err := NewLogDumper(bastion.addr, &ssh.ClientConfig{User: "admin"}, agent.NewKeyring(), t.TempDir()).
+		WithJumpHosts([]string{jumpHost.addr}).
+		WithKnownHosts(knownHostsFile, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := d.sshClientFactory.Dial(context.Background(), "10.0.0.1", true, "ubuntu"); err == nil {
+		t.Errorf("expected the unknown jump host to be rejected")
+	}
+	jumpHost.mutex.Lock()
+	defer jumpHost.mutex.Unlock()
+	if len(jumpHost.forwards) != 0 {
+		t.Errorf("expected nothing to be forwarded through the unknown jump host, got %v", jumpHost.forwards)
+	}
+}
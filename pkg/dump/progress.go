@@ -0,0 +1,92 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ProgressEventType is the type of a ProgressEvent
+type ProgressEventType string
+
+const (
+	// ProgressNodeStarted is emitted before connecting to a node
+	ProgressNodeStarted ProgressEventType = "nodeStarted"
+	// ProgressNodeCompleted is emitted once a node has been dumped, or could not be dumped
+	ProgressNodeCompleted ProgressEventType = "nodeCompleted"
+)
+
+// ProgressEvent is written as a single line of JSON to the progress stream
+type ProgressEvent struct {
+	Time  time.Time         `json:"time"`
+	Event ProgressEventType `json:"event"`
+	// Node is the name of the node, or its IP address if it is not registered in kubernetes
+	Node    string `json:"node"`
+	Address string `json:"address,omitempty"`
+
+	// The following fields are only set for ProgressNodeCompleted
+	Connected        bool    `json:"connected,omitempty"`
+	BytesCaptured    int64   `json:"bytesCaptured,omitempty"`
+	CollectionErrors int     `json:"collectionErrors,omitempty"`
+	ElapsedSeconds   float64 `json:"elapsedSeconds,omitempty"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// progressStream writes ProgressEvents as JSON Lines; it is safe for concurrent use
+type progressStream struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+func newProgressStream(w io.Writer) *progressStream {
+	return &progressStream{encoder: json.NewEncoder(w)}
+}
+
+// emit writes the event; a nil progressStream discards it.
+// Failing to report progress does not fail the dump.
+func (p *progressStream) emit(event ProgressEvent) {
+	if p == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err := p.encoder.Encode(event); err != nil {
+		log.Printf("error writing progress event: %v", err)
+	}
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w     io.Writer
+	count *atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ArtifactSink stores the files captured by the dumper
+type ArtifactSink interface {
+	// Create returns a writer for the file at relPath, relative to the root of the artifacts.
+	// The file is complete once the writer is closed.
+	Create(relPath string) (io.WriteCloser, error)
+
+	// Close finalizes the artifacts
+	Close() error
+}
+
+// dirSink writes artifacts into a directory tree
+type dirSink struct {
+	dir string
+}
+
+var _ ArtifactSink = &dirSink{}
+
+// Create implements ArtifactSink::Create
+func (s *dirSink) Create(relPath string) (io.WriteCloser, error) {
+	destPath := filepath.Join(s.dir, relPath)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		log.Printf("unable to mkdir on %q: %v", filepath.Dir(destPath), err)
+	}
+
+	f, err := os.Create(destPath)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file %q: %v", destPath, err)
+	}
+	return f, nil
+}
+
+// Close implements ArtifactSink::Close
+func (s *dirSink) Close() error {
+	return nil
+}
+
+// tarSink streams artifacts as a gzip-compressed tarball
+type tarSink struct {
+	// mutex serializes writes of concurrent node dumps to the shared tarball
+	mutex sync.Mutex
+	gz    *gzip.Writer
+	tw    *tar.Writer
+}
+
+var _ ArtifactSink = &tarSink{}
+
+// NewTarSink returns an ArtifactSink that streams a gzip-compressed tarball of the artifacts to w.
+func NewTarSink(w io.Writer) ArtifactSink {
+	gz := gzip.NewWriter(w)
+	return &tarSink{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+	}
+}
+
+// Create implements ArtifactSink::Create.
+// Tar entries need their size up front, so the file is spooled to a temporary file until the writer is closed,
+// rather than held in memory, as captured files such as journals can be large.
+func (s *tarSink) Create(relPath string) (io.WriteCloser, error) {
+	spool, err := os.CreateTemp("", "kops-dump-")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temporary file for %q: %w", relPath, err)
+	}
+	return &tarEntryWriter{sink: s, name: filepath.ToSlash(relPath), spool: spool}, nil
+}
+
+// writeEntry copies the spooled file into the tarball as a single entry
+func (s *tarSink) writeEntry(name string, spool *os.File) error {
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("error getting size of %q: %w", name, err)
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("error rewinding %q: %w", name, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: time.Now(),
+	}
+	if err := s.tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing tar header for %q: %w", name, err)
+	}
+	if _, err := io.CopyN(s.tw, spool, size); err != nil {
+		return fmt.Errorf("error writing tar entry %q: %w", name, err)
+	}
+	return nil
+}
+
+// Close implements ArtifactSink::Close
+func (s *tarSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.tw.Close(); err != nil {
+		return fmt.Errorf("error closing tar: %w", err)
+	}
+	if err := s.gz.Close(); err != nil {
+		return fmt.Errorf("error closing gzip: %w", err)
+	}
+	return nil
+}
+
+// tarEntryWriter spools a single file of a tarSink
+type tarEntryWriter struct {
+	sink *tarSink
+	name string
+
+	// mutex protects spool, as stdout and stderr of a command are copied concurrently
+	mutex sync.Mutex
+	spool *os.File
+}
+
+func (w *tarEntryWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.spool.Write(p)
+}
+
+func (w *tarEntryWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	defer func() {
+		w.spool.Close()
+		os.Remove(w.spool.Name())
+	}()
+	return w.sink.writeEntry(w.name, w.spool)
+}
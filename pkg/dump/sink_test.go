@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dump
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+)
+
+// readTarball returns the contents of the entries of a gzip-compressed tarball, by name
+func readTarball(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("error opening gzip: %v", err)
+	}
+	tr := tar.NewReader(gz)
+	entries := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("error reading tar: %v", err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("error reading tar entry %q: %v", header.Name, err)
+		}
+		entries[header.Name] = string(b)
+	}
+	return entries
+}
+
+func TestTarSinkSpoolsEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("TMPDIR", tmpDir)
+
+	var out bytes.Buffer
+	sink := NewTarSink(&out)
+
+	first, err := sink.Create("node-1/kubelet.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := sink.Create("node-2/kubelet.log")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		io.WriteString(first, "first line\n")
+	}
+	io.WriteString(second, "second")
+
+	spooled, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spooled) != 2 {
+		t.Errorf("expected the open entries to be spooled to 2 temporary files, got %d", len(spooled))
+	}
+
+	if err := second.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := first.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spooled, err = os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spooled) != 0 {
+		t.Errorf("expected the temporary files to be removed, got %d", len(spooled))
+	}
+
+	entries := readTarball(t, out.Bytes())
+	if len(entries["node-1/kubelet.log"]) != 11000 {
+		t.Errorf("expected 11000 bytes in node-1/kubelet.log, got %d", len(entries["node-1/kubelet.log"]))
+	}
+	if entries["node-2/kubelet.log"] != "second" {
+		t.Errorf("unexpected content of node-2/kubelet.log: %q", entries["node-2/kubelet.log"])
+	}
+}
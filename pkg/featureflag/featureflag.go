@@ -98,6 +98,8 @@ var (
 	Metal = new("Metal", Bool(false))
 	// AWSSingleNodesInstanceGroup enables the creation of a single node instance group instead of one per availability zone.
 	AWSSingleNodesInstanceGroup = new("AWSSingleNodesInstanceGroup", Bool(false))
+	// TerraformGp2ToGp3 converts the gp2 volumes of launch templates to gp3 volumes with equivalent baseline performance when rendering to Terraform.
+	TerraformGp2ToGp3 = new("TerraformGp2ToGp3", Bool(false))
 )
 
 // FeatureFlag defines a feature flag
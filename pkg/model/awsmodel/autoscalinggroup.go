@@ -214,6 +214,9 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.CloudupMode
 		}
 		lt.RootVolumeOptimization = ig.Spec.RootVolume.Optimization
 	}
+	if key := fi.ValueOf(b.Cluster.Spec.CloudProvider.AWS.VolumeEncryptionKey); key != "" {
+		lt.DefaultVolumeKmsKey = fi.PtrTo(key)
+	}
 
 	if ig.Spec.Manager == kops.InstanceManagerCloudGroup {
 		lt.InstanceType = fi.PtrTo(ec2types.InstanceType(strings.Split(ig.Spec.MachineType, ",")[0]))
@@ -297,6 +300,18 @@ func (b *AutoscalingGroupModelBuilder) buildLaunchTemplateTask(c *fi.CloudupMode
 		lt.BlockDeviceMappings = append(lt.BlockDeviceMappings, bdm)
 	}
 
+	// @step: override the mapping of the instance store volumes
+	if len(ig.Spec.EphemeralDevices) > 0 {
+		lt.EphemeralDeviceNames = make(map[string]string)
+		for _, x := range ig.Spec.EphemeralDevices {
+			if x.Exclude {
+				lt.EphemeralDeviceNames[x.VirtualName] = ""
+			} else {
+				lt.EphemeralDeviceNames[x.VirtualName] = x.Device
+			}
+		}
+	}
+
 	if ig.Spec.DetailedInstanceMonitoring != nil {
 		lt.InstanceMonitoring = ig.Spec.DetailedInstanceMonitoring
 	}
@@ -419,21 +434,9 @@ func (b *AutoscalingGroupModelBuilder) buildAutoScalingGroupTask(c *fi.CloudupMo
 		InstanceProtection: fi.PtrTo(false),
 	}
 
-	minSize := fi.PtrTo(int32(1))
-	maxSize := fi.PtrTo(int32(1))
-	if ig.Spec.MinSize != nil {
-		minSize = fi.PtrTo(int32(*ig.Spec.MinSize))
-	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
-		minSize = fi.PtrTo(int32(2))
-	}
-	if ig.Spec.MaxSize != nil {
-		maxSize = fi.PtrTo(int32(*ig.Spec.MaxSize))
-	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
-		maxSize = fi.PtrTo(int32(2))
-	}
-
-	t.MinSize = minSize
-	t.MaxSize = maxSize
+	minSize, maxSize := b.InstanceGroupSize(ig)
+	t.MinSize = fi.PtrTo(minSize)
+	t.MaxSize = fi.PtrTo(maxSize)
 
 	subnets, err := b.GatherSubnets(ig)
 	if err != nil {
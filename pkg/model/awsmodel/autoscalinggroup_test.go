@@ -283,3 +283,41 @@ func TestAPIServerAdditionalSecurityGroupsWithNLB(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterAutoscalerNodeGroupAutoDiscoveryTags(t *testing.T) {
+	cluster := buildMinimalCluster()
+	cluster.Spec.ClusterAutoscaler = &kops.ClusterAutoscalerConfig{
+		Enabled: fi.PtrTo(true),
+		NodeGroupAutoDiscoveryTags: map[string]string{
+			"k8s.io/cluster-autoscaler/enabled": "",
+			"team":                              "ml",
+		},
+	}
+
+	nodes := buildNodeInstanceGroup("subnet-us-test-1a")
+	nodes.Spec.CloudLabels = map[string]string{"team": "data"}
+	fixed := buildNodeInstanceGroup("subnet-us-test-1a")
+	fixed.ObjectMeta.Name = "fixed"
+	fixed.Spec.Autoscale = fi.PtrTo(false)
+	controlPlane := buildNodeInstanceGroup("subnet-us-test-1a")
+	controlPlane.ObjectMeta.Name = "control-plane"
+	controlPlane.Spec.Role = kops.InstanceGroupRoleControlPlane
+
+	b := &model.KopsModelContext{
+		IAMModelContext: iam.IAMModelContext{Cluster: cluster},
+		InstanceGroups:  []*kops.InstanceGroup{nodes, fixed, controlPlane},
+	}
+	for _, ig := range b.InstanceGroups {
+		tags, err := b.CloudTagsForInstanceGroup(ig)
+		if err != nil {
+			t.Fatalf("error building tags for %s: %v", ig.Name, err)
+		}
+		expectTags := ig == nodes
+		for k, v := range cluster.Spec.ClusterAutoscaler.NodeGroupAutoDiscoveryTags {
+			actual, found := tags[k]
+			if found != expectTags || (found && actual != v) {
+				t.Errorf("instance group %s: unexpected value %q (found %v) for tag %q", ig.Name, actual, found, k)
+			}
+		}
+	}
+}
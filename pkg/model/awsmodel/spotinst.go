@@ -809,20 +809,7 @@ func (b *SpotInstanceGroupModelBuilder) buildRootVolumeOpts(ig *kops.InstanceGro
 }
 
 func (b *SpotInstanceGroupModelBuilder) buildCapacity(ig *kops.InstanceGroup) (*int64, *int64) {
-	minSize := int32(1)
-	if ig.Spec.MinSize != nil {
-		minSize = fi.ValueOf(ig.Spec.MinSize)
-	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
-		minSize = 2
-	}
-
-	maxSize := int32(1)
-	if ig.Spec.MaxSize != nil {
-		maxSize = *ig.Spec.MaxSize
-	} else if ig.Spec.Role == kops.InstanceGroupRoleNode {
-		maxSize = 2
-	}
-
+	minSize, maxSize := b.InstanceGroupSize(ig)
 	return fi.PtrTo(int64(minSize)), fi.PtrTo(int64(maxSize))
 }
 
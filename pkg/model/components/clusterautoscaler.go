@@ -17,6 +17,9 @@ limitations under the License.
 package components
 
 import (
+	"slices"
+	"strings"
+
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/upup/pkg/fi"
@@ -104,7 +107,16 @@ func (b *ClusterAutoscalerOptionsBuilder) BuildOptions(o *kops.Cluster) error {
 	if cas.MaxNodeProvisionTime == "" {
 		cas.MaxNodeProvisionTime = "15m0s"
 	}
-	if cas.Expander == "priority" {
+	if cas.MetricsPort == nil {
+		cas.MetricsPort = fi.PtrTo(int32(8085))
+	}
+	if cas.LogLevel == nil {
+		cas.LogLevel = fi.PtrTo(int32(4))
+	}
+	if cas.LeaderElectResourceLock == "" {
+		cas.LeaderElectResourceLock = "leases"
+	}
+	if slices.Contains(strings.Split(cas.Expander, ","), "priority") {
 		cas.CreatePriorityExpenderConfig = fi.PtrTo(true)
 	}
 
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/pkg/k8sversion"
@@ -51,6 +52,28 @@ func (c *OptionsContext) IsKubernetesLT(version string) bool {
 	return !c.IsKubernetesGTE(version)
 }
 
+// CloudProviderFlag returns the value of the --cloud-provider flag of the kubernetes components:
+// "external" if the cloud-specific control loops run in an external cloud controller manager,
+// otherwise the name of the in-tree cloud provider.
+func CloudProviderFlag(cluster *kops.Cluster) (string, error) {
+	if model.UseExternalCloudProvider(cluster) {
+		return "external", nil
+	}
+
+	switch cluster.GetCloudProvider() {
+	case kops.CloudProviderAWS:
+		return "aws", nil
+	case kops.CloudProviderGCE:
+		return "gce", nil
+	case kops.CloudProviderOpenstack:
+		return "openstack", nil
+	case kops.CloudProviderAzure:
+		return "azure", nil
+	default:
+		return "", fmt.Errorf("unknown cloudprovider %q", cluster.GetCloudProvider())
+	}
+}
+
 // UsesCNI returns true if the networking provider is a CNI plugin
 func UsesCNI(networking *kops.NetworkingSpec) bool {
 	// Kubenet and CNI are the only kubelet networking plugins right now.
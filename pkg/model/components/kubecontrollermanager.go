@@ -75,7 +75,11 @@ func (b *KubeControllerManagerOptionsBuilder) BuildOptions(o *kops.Cluster) erro
 	}
 
 	kcm.ClusterName = b.ClusterName
-	kcm.CloudProvider = "external"
+	cloudProvider, err := CloudProviderFlag(o)
+	if err != nil {
+		return err
+	}
+	kcm.CloudProvider = cloudProvider
 
 	if kcm.LogLevel == 0 {
 		kcm.LogLevel = 2
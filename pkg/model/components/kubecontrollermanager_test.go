@@ -163,3 +163,58 @@ func Test_Build_KCM_Builder_CIDR_Mask_Size(t *testing.T) {
 		})
 	}
 }
+
+func Test_Build_KCM_CloudProvider(t *testing.T) {
+	grid := []struct {
+		name              string
+		kubernetesVersion string
+		cloudProvider     api.CloudProviderSpec
+		externalCCM       bool
+		expected          string
+	}{
+		{
+			name:              "aws with in-tree cloud provider",
+			kubernetesVersion: "v1.26.0",
+			cloudProvider:     api.CloudProviderSpec{AWS: &api.AWSSpec{}},
+			expected:          "aws",
+		},
+		{
+			name:              "aws with external cloud controller manager",
+			kubernetesVersion: "v1.26.0",
+			cloudProvider:     api.CloudProviderSpec{AWS: &api.AWSSpec{}},
+			externalCCM:       true,
+			expected:          "external",
+		},
+		{
+			name:              "aws without in-tree cloud provider",
+			kubernetesVersion: "v1.28.0",
+			cloudProvider:     api.CloudProviderSpec{AWS: &api.AWSSpec{}},
+			expected:          "external",
+		},
+		{
+			name:              "hetzner",
+			kubernetesVersion: "v1.26.0",
+			cloudProvider:     api.CloudProviderSpec{Hetzner: &api.HetznerSpec{}},
+			expected:          "external",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			c := buildCluster()
+			c.Spec.KubernetesVersion = g.kubernetesVersion
+			c.Spec.CloudProvider = g.cloudProvider
+			if g.externalCCM {
+				c.Spec.ExternalCloudControllerManager = &api.CloudControllerManagerConfig{}
+			}
+			b := assets.NewAssetBuilder(vfs.Context, c.Spec.Assets, c.Spec.KubernetesVersion, false)
+
+			kcm := &KubeControllerManagerOptionsBuilder{
+				OptionsContext: &OptionsContext{
+					AssetBuilder: b,
+				},
+			}
+			require.NoError(t, kcm.BuildOptions(c))
+			assert.Equal(t, g.expected, c.Spec.KubeControllerManager.CloudProvider)
+		})
+	}
+}
@@ -118,16 +118,7 @@ func (b *KubeletOptionsBuilder) BuildOptions(cluster *kops.Cluster) error {
 	clusterSpec.Kubelet.CgroupRoot = "/"
 
 	klog.V(1).Infof("Cloud Provider: %s", cloudProvider)
-	if cloudProvider == kops.CloudProviderAWS {
-		clusterSpec.Kubelet.CloudProvider = "aws"
-	}
-
-	if cloudProvider == kops.CloudProviderDO {
-		clusterSpec.Kubelet.CloudProvider = "external"
-	}
-
 	if cloudProvider == kops.CloudProviderGCE {
-		clusterSpec.Kubelet.CloudProvider = "gce"
 		clusterSpec.Kubelet.HairpinMode = "promiscuous-bridge"
 
 		if clusterSpec.CloudConfig == nil {
@@ -138,24 +129,14 @@ func (b *KubeletOptionsBuilder) BuildOptions(cluster *kops.Cluster) error {
 
 	}
 
-	if cloudProvider == kops.CloudProviderHetzner {
-		clusterSpec.Kubelet.CloudProvider = "external"
-	}
-
-	if cloudProvider == kops.CloudProviderOpenstack {
-		clusterSpec.Kubelet.CloudProvider = "openstack"
-	}
-
-	if cloudProvider == kops.CloudProviderAzure {
-		clusterSpec.Kubelet.CloudProvider = "azure"
-	}
-
-	if cloudProvider == kops.CloudProviderScaleway {
-		clusterSpec.Kubelet.CloudProvider = "external"
-	}
-
-	if clusterSpec.ExternalCloudControllerManager != nil {
-		clusterSpec.Kubelet.CloudProvider = "external"
+	// Metal has no cloud controller manager to initialize the nodes, so the kubelet
+	// must not register them with the uninitialized taint.
+	if cloudProvider != kops.CloudProviderMetal || clusterSpec.ExternalCloudControllerManager != nil {
+		kubeletCloudProvider, err := CloudProviderFlag(cluster)
+		if err != nil {
+			return err
+		}
+		clusterSpec.Kubelet.CloudProvider = kubeletCloudProvider
 	}
 
 	// Prevent image GC from pruning the pause image
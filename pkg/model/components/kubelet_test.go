@@ -90,3 +90,49 @@ func TestFeatureGatesOverride(t *testing.T) {
 		t.Errorf("ExperimentalCriticalPodAnnotation feature should be disalbled")
 	}
 }
+
+func TestKubeletCloudProvider(t *testing.T) {
+	grid := []struct {
+		name     string
+		cluster  func(cluster *kops.Cluster)
+		expected string
+	}{
+		{
+			name: "aws",
+			cluster: func(cluster *kops.Cluster) {
+				cluster.Spec.KubernetesVersion = "1.30.0"
+				cluster.Spec.CloudProvider.AWS = &kops.AWSSpec{}
+			},
+			expected: "external",
+		},
+		{
+			name: "metal",
+			cluster: func(cluster *kops.Cluster) {
+				cluster.Spec.KubernetesVersion = "1.30.0"
+				cluster.Labels = map[string]string{kops.AlphaLabelCloudProvider: "metal"}
+			},
+			expected: "",
+		},
+		{
+			name: "metal with external cloud controller manager",
+			cluster: func(cluster *kops.Cluster) {
+				cluster.Spec.KubernetesVersion = "1.30.0"
+				cluster.Labels = map[string]string{kops.AlphaLabelCloudProvider: "metal"}
+				cluster.Spec.ExternalCloudControllerManager = &kops.CloudControllerManagerConfig{}
+			},
+			expected: "external",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			cluster := buildKubeletTestCluster()
+			g.cluster(cluster)
+			if err := buildOptions(cluster); err != nil {
+				t.Fatal(err)
+			}
+			if actual := cluster.Spec.Kubelet.CloudProvider; actual != g.expected {
+				t.Errorf("expected %q, got %q", g.expected, actual)
+			}
+		})
+	}
+}
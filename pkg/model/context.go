@@ -110,6 +110,11 @@ func (b *KopsModelContext) FindZonesForInstanceGroup(ig *kops.InstanceGroup) ([]
 	return model.FindZonesForInstanceGroup(b.Cluster, ig)
 }
 
+// InstanceGroupSize returns the minimum and maximum size of an InstanceGroup, applying the defaults
+func (b *KopsModelContext) InstanceGroupSize(ig *kops.InstanceGroup) (int32, int32) {
+	return model.InstanceGroupSize(ig)
+}
+
 // MasterInstanceGroups returns InstanceGroups with the master role
 func (b *KopsModelContext) MasterInstanceGroups() []*kops.InstanceGroup {
 	var groups []*kops.InstanceGroup
@@ -173,6 +178,17 @@ func (b *KopsModelContext) CloudTagsForInstanceGroup(ig *kops.InstanceGroup) (ma
 		}
 	}
 
+	// Apply the tags the cluster autoscaler discovers node groups by, so that it scales the instance group
+	// within the minimum and maximum size of its autoscaling group without tagging it manually.
+	// Validation rejects user-specified labels with the same keys.
+	if cas := b.Cluster.Spec.ClusterAutoscaler; cas != nil && fi.ValueOf(cas.Enabled) {
+		if ig.Spec.Role == kops.InstanceGroupRoleNode && (ig.Spec.Autoscale == nil || fi.ValueOf(ig.Spec.Autoscale)) {
+			for k, v := range cas.NodeGroupAutoDiscoveryTags {
+				labels[k] = v
+			}
+		}
+	}
+
 	// The system tags take priority because the cluster likely breaks without them...
 
 	if ig.Spec.Role == kops.InstanceGroupRoleControlPlane {
@@ -406,7 +406,8 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 
 		AddCCMPermissions(p, b.Cluster.Spec.Networking.Kubenet != nil)
 
-		if c := b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController; c != nil && fi.ValueOf(b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController.Enabled) {
+		if model.UseAWSLoadBalancerController(b.Cluster) {
+			c := b.Cluster.Spec.CloudProvider.AWS.LoadBalancerController
 			AddAWSLoadbalancerControllerPermissions(p, c.EnableWAF, c.EnableWAFv2, c.EnableShield)
 		}
 
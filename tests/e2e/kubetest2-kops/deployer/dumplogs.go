@@ -43,6 +43,8 @@ func (d *deployer) DumpClusterLogs() error {
 		"--dir", d.ArtifactsDir,
 		"--private-key", d.SSHPrivateKeyPath,
 		"--ssh-user", d.SSHUser,
+		// The nodes of the ephemeral test clusters are never in the known hosts
+		"--insecure-ignore-host-key",
 	}
 
 	if d.MaxNodesToDump != "" {
@@ -512,6 +512,7 @@ resource "aws_launch_template" "apiserver-apiservers-minimal-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.apiservers-minimal-example-com.id
   }
@@ -523,7 +524,6 @@ resource "aws_launch_template" "apiserver-apiservers-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -561,6 +561,18 @@ resource "aws_launch_template" "apiserver-apiservers-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                = "minimal.example.com"
+      "Name"                                                                             = "apiserver.apiservers.minimal.example.com"
+      "aws-node-termination-handler/managed"                                             = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/api-server" = ""
+      "k8s.io/role/apiserver"                                                            = "1"
+      "kops.k8s.io/instancegroup"                                                        = "apiserver"
+      "kubernetes.io/cluster/minimal.example.com"                                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                = "minimal.example.com"
     "Name"                                                                             = "apiserver.apiservers.minimal.example.com"
@@ -589,6 +601,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-minimal-example-com.id
   }
@@ -600,7 +613,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -646,6 +658,22 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/api-server"                      = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal.example.com"                                                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "minimal.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.minimal.example.com"
@@ -674,6 +702,7 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-example-com.id
   }
@@ -685,7 +714,6 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -723,6 +751,18 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal.example.com"
+      "Name"                                                                       = "nodes.minimal.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal.example.com"                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "minimal.example.com"
     "Name"                                                                       = "nodes.minimal.example.com"
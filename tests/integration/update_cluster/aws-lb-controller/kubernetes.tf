@@ -568,6 +568,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-minimal-example-com.id
   }
@@ -579,7 +580,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -623,6 +623,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal.example.com"                                                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "minimal.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.minimal.example.com"
@@ -650,6 +665,7 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-example-com.id
   }
@@ -661,7 +677,6 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -699,6 +714,18 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal.example.com"
+      "Name"                                                                       = "nodes.minimal.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal.example.com"                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "minimal.example.com"
     "Name"                                                                       = "nodes.minimal.example.com"
@@ -551,6 +551,7 @@ resource "aws_launch_template" "bastion-bastionuserdata-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster bastionuserdata.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-bastionuserdata-example-com.id
   }
@@ -562,7 +563,6 @@ resource "aws_launch_template" "bastion-bastionuserdata-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -598,6 +598,17 @@ resource "aws_launch_template" "bastion-bastionuserdata-example-com" {
       "kubernetes.io/cluster/bastionuserdata.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                 = "bastionuserdata.example.com"
+      "Name"                                              = "bastion.bastionuserdata.example.com"
+      "aws-node-termination-handler/managed"              = ""
+      "k8s.io/role/bastion"                               = "1"
+      "kops.k8s.io/instancegroup"                         = "bastion"
+      "kubernetes.io/cluster/bastionuserdata.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                 = "bastionuserdata.example.com"
     "Name"                                              = "bastion.bastionuserdata.example.com"
@@ -625,6 +636,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-bastionuserdata-exampl
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster bastionuserdata.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-bastionuserdata-example-com.id
   }
@@ -636,7 +648,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-bastionuserdata-exampl
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -680,6 +691,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-bastionuserdata-exampl
       "kubernetes.io/cluster/bastionuserdata.example.com"                                                     = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "bastionuserdata.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.bastionuserdata.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/bastionuserdata.example.com"                                                     = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "bastionuserdata.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.bastionuserdata.example.com"
@@ -707,6 +733,7 @@ resource "aws_launch_template" "nodes-bastionuserdata-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster bastionuserdata.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-bastionuserdata-example-com.id
   }
@@ -718,7 +745,6 @@ resource "aws_launch_template" "nodes-bastionuserdata-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -756,6 +782,18 @@ resource "aws_launch_template" "nodes-bastionuserdata-example-com" {
       "kubernetes.io/cluster/bastionuserdata.example.com"                          = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "bastionuserdata.example.com"
+      "Name"                                                                       = "nodes.bastionuserdata.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/bastionuserdata.example.com"                          = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "bastionuserdata.example.com"
     "Name"                                                                       = "nodes.bastionuserdata.example.com"
@@ -523,6 +523,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-cas-priority-expander-
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster cas-priority-expander.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-cas-priority-expander-example-com.id
   }
@@ -534,7 +535,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-cas-priority-expander-
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -578,6 +578,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-cas-priority-expander-
       "kubernetes.io/cluster/cas-priority-expander.example.com"                                               = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "cas-priority-expander.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.cas-priority-expander.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/cas-priority-expander.example.com"                                               = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "cas-priority-expander.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.cas-priority-expander.example.com"
@@ -605,6 +620,7 @@ resource "aws_launch_template" "nodes-cas-priority-expander-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster cas-priority-expander.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-cas-priority-expander-example-com.id
   }
@@ -616,7 +632,6 @@ resource "aws_launch_template" "nodes-cas-priority-expander-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -654,6 +669,18 @@ resource "aws_launch_template" "nodes-cas-priority-expander-example-com" {
       "kubernetes.io/cluster/cas-priority-expander.example.com"                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "cas-priority-expander.example.com"
+      "Name"                                                                       = "nodes.cas-priority-expander.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/cas-priority-expander.example.com"                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "cas-priority-expander.example.com"
     "Name"                                                                       = "nodes.cas-priority-expander.example.com"
@@ -678,6 +705,7 @@ resource "aws_launch_template" "nodes-high-priority-cas-priority-expander-exampl
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster cas-priority-expander.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-cas-priority-expander-example-com.id
   }
@@ -689,7 +717,6 @@ resource "aws_launch_template" "nodes-high-priority-cas-priority-expander-exampl
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -727,6 +754,18 @@ resource "aws_launch_template" "nodes-high-priority-cas-priority-expander-exampl
       "kubernetes.io/cluster/cas-priority-expander.example.com"                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "cas-priority-expander.example.com"
+      "Name"                                                                       = "nodes-high-priority.cas-priority-expander.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes-high-priority"
+      "kubernetes.io/cluster/cas-priority-expander.example.com"                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "cas-priority-expander.example.com"
     "Name"                                                                       = "nodes-high-priority.cas-priority-expander.example.com"
@@ -751,6 +790,7 @@ resource "aws_launch_template" "nodes-low-priority-cas-priority-expander-example
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster cas-priority-expander.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-cas-priority-expander-example-com.id
   }
@@ -762,7 +802,6 @@ resource "aws_launch_template" "nodes-low-priority-cas-priority-expander-example
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -800,6 +839,18 @@ resource "aws_launch_template" "nodes-low-priority-cas-priority-expander-example
       "kubernetes.io/cluster/cas-priority-expander.example.com"                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "cas-priority-expander.example.com"
+      "Name"                                                                       = "nodes-low-priority.cas-priority-expander.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes-low-priority"
+      "kubernetes.io/cluster/cas-priority-expander.example.com"                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "cas-priority-expander.example.com"
     "Name"                                                                       = "nodes-low-priority.cas-priority-expander.example.com"
@@ -463,6 +463,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-complex-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster complex.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-complex-example-com.id
   }
@@ -473,7 +474,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-complex-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -485,7 +485,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-complex-example-com" {
     associate_public_ip_address = true
     delete_on_termination       = true
     ipv6_address_count          = 0
-    security_groups             = [aws_security_group.masters-complex-example-com.id, "sg-exampleid5", "sg-exampleid6"]
+    security_groups             = ["sg-exampleid5", "sg-exampleid6", aws_security_group.masters-complex-example-com.id]
   }
   tag_specifications {
     resource_type = "instance"
@@ -521,6 +521,23 @@ resource "aws_launch_template" "master-us-test-1a-masters-complex-example-com" {
       "kubernetes.io/cluster/complex.example.com"                                                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "complex.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.complex.example.com"
+      "Owner"                                                                                                 = "John Doe"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "foo/bar"                                                                                               = "fib+baz"
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/complex.example.com"                                                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "complex.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.complex.example.com"
@@ -563,6 +580,7 @@ resource "aws_launch_template" "nodes-complex-example-com" {
   credit_specification {
     cpu_credits = "standard"
   }
+  description = "Managed by kOps for cluster complex.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-complex-example-com.id
   }
@@ -573,7 +591,6 @@ resource "aws_launch_template" "nodes-complex-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -585,7 +602,7 @@ resource "aws_launch_template" "nodes-complex-example-com" {
     associate_public_ip_address = true
     delete_on_termination       = true
     ipv6_address_count          = 0
-    security_groups             = [aws_security_group.nodes-complex-example-com.id, "sg-exampleid3", "sg-exampleid4"]
+    security_groups             = ["sg-exampleid3", "sg-exampleid4", aws_security_group.nodes-complex-example-com.id]
   }
   tag_specifications {
     resource_type = "instance"
@@ -615,6 +632,20 @@ resource "aws_launch_template" "nodes-complex-example-com" {
       "kubernetes.io/cluster/complex.example.com"                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "complex.example.com"
+      "Name"                                                                       = "nodes.complex.example.com"
+      "Owner"                                                                      = "John Doe"
+      "aws-node-termination-handler/managed"                                       = ""
+      "foo/bar"                                                                    = "fib+baz"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/complex.example.com"                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "complex.example.com"
     "Name"                                                                       = "nodes.complex.example.com"
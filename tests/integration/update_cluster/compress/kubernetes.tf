@@ -397,6 +397,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-compress-example-com"
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster compress.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-compress-example-com.id
   }
@@ -407,7 +408,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-compress-example-com"
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -451,6 +451,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-compress-example-com"
       "kubernetes.io/cluster/compress.example.com"                                                            = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "compress.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.compress.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/compress.example.com"                                                            = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "compress.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.compress.example.com"
@@ -478,6 +493,7 @@ resource "aws_launch_template" "nodes-compress-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster compress.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-compress-example-com.id
   }
@@ -488,7 +504,6 @@ resource "aws_launch_template" "nodes-compress-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -526,6 +541,18 @@ resource "aws_launch_template" "nodes-compress-example-com" {
       "kubernetes.io/cluster/compress.example.com"                                 = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "compress.example.com"
+      "Name"                                                                       = "nodes.compress.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/compress.example.com"                                 = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "compress.example.com"
     "Name"                                                                       = "nodes.compress.example.com"
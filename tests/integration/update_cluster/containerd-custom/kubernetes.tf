@@ -407,6 +407,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-containerd-example-com
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster containerd.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-containerd-example-com.id
   }
@@ -418,7 +419,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-containerd-example-com
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -462,6 +462,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-containerd-example-com
       "kubernetes.io/cluster/containerd.example.com"                                                          = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "containerd.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.containerd.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/containerd.example.com"                                                          = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "containerd.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.containerd.example.com"
@@ -489,6 +504,7 @@ resource "aws_launch_template" "nodes-containerd-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster containerd.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-containerd-example-com.id
   }
@@ -500,7 +516,6 @@ resource "aws_launch_template" "nodes-containerd-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -538,6 +553,18 @@ resource "aws_launch_template" "nodes-containerd-example-com" {
       "kubernetes.io/cluster/containerd.example.com"                               = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "containerd.example.com"
+      "Name"                                                                       = "nodes.containerd.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/containerd.example.com"                               = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "containerd.example.com"
     "Name"                                                                       = "nodes.containerd.example.com"
@@ -559,6 +559,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-existing-iam-example-c
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster existing-iam.example.com"
   iam_instance_profile {
     name = "kops-custom-master-role"
   }
@@ -570,7 +571,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-existing-iam-example-c
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -614,6 +614,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-existing-iam-example-c
       "kubernetes.io/cluster/existing-iam.example.com"                                                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "existing-iam.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.existing-iam.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/existing-iam.example.com"                                                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "existing-iam.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.existing-iam.example.com"
@@ -645,6 +660,7 @@ resource "aws_launch_template" "master-us-test-1b-masters-existing-iam-example-c
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster existing-iam.example.com"
   iam_instance_profile {
     name = "kops-custom-master-role"
   }
@@ -656,7 +672,6 @@ resource "aws_launch_template" "master-us-test-1b-masters-existing-iam-example-c
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -700,6 +715,21 @@ resource "aws_launch_template" "master-us-test-1b-masters-existing-iam-example-c
       "kubernetes.io/cluster/existing-iam.example.com"                                                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "existing-iam.example.com"
+      "Name"                                                                                                  = "master-us-test-1b.masters.existing-iam.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1b"
+      "kubernetes.io/cluster/existing-iam.example.com"                                                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "existing-iam.example.com"
     "Name"                                                                                                  = "master-us-test-1b.masters.existing-iam.example.com"
@@ -731,6 +761,7 @@ resource "aws_launch_template" "master-us-test-1c-masters-existing-iam-example-c
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster existing-iam.example.com"
   iam_instance_profile {
     name = "kops-custom-master-role"
   }
@@ -742,7 +773,6 @@ resource "aws_launch_template" "master-us-test-1c-masters-existing-iam-example-c
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -786,6 +816,21 @@ resource "aws_launch_template" "master-us-test-1c-masters-existing-iam-example-c
       "kubernetes.io/cluster/existing-iam.example.com"                                                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "existing-iam.example.com"
+      "Name"                                                                                                  = "master-us-test-1c.masters.existing-iam.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1c"
+      "kubernetes.io/cluster/existing-iam.example.com"                                                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "existing-iam.example.com"
     "Name"                                                                                                  = "master-us-test-1c.masters.existing-iam.example.com"
@@ -813,6 +858,7 @@ resource "aws_launch_template" "nodes-existing-iam-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster existing-iam.example.com"
   iam_instance_profile {
     name = "kops-custom-node-role"
   }
@@ -824,7 +870,6 @@ resource "aws_launch_template" "nodes-existing-iam-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -862,6 +907,18 @@ resource "aws_launch_template" "nodes-existing-iam-example-com" {
       "kubernetes.io/cluster/existing-iam.example.com"                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "existing-iam.example.com"
+      "Name"                                                                       = "nodes.existing-iam.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/existing-iam.example.com"                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "existing-iam.example.com"
     "Name"                                                                       = "nodes.existing-iam.example.com"
@@ -662,6 +662,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-existingsg-example-com
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster existingsg.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-existingsg-example-com.id
   }
@@ -673,7 +674,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-existingsg-example-com
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -717,6 +717,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-existingsg-example-com
       "kubernetes.io/cluster/existingsg.example.com"                                                          = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "existingsg.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.existingsg.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/existingsg.example.com"                                                          = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "existingsg.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.existingsg.example.com"
@@ -748,6 +763,7 @@ resource "aws_launch_template" "master-us-test-1b-masters-existingsg-example-com
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster existingsg.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-existingsg-example-com.id
   }
@@ -759,7 +775,6 @@ resource "aws_launch_template" "master-us-test-1b-masters-existingsg-example-com
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -803,6 +818,21 @@ resource "aws_launch_template" "master-us-test-1b-masters-existingsg-example-com
       "kubernetes.io/cluster/existingsg.example.com"                                                          = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "existingsg.example.com"
+      "Name"                                                                                                  = "master-us-test-1b.masters.existingsg.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1b"
+      "kubernetes.io/cluster/existingsg.example.com"                                                          = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "existingsg.example.com"
     "Name"                                                                                                  = "master-us-test-1b.masters.existingsg.example.com"
@@ -834,6 +864,7 @@ resource "aws_launch_template" "master-us-test-1c-masters-existingsg-example-com
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster existingsg.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-existingsg-example-com.id
   }
@@ -845,7 +876,6 @@ resource "aws_launch_template" "master-us-test-1c-masters-existingsg-example-com
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -889,6 +919,21 @@ resource "aws_launch_template" "master-us-test-1c-masters-existingsg-example-com
       "kubernetes.io/cluster/existingsg.example.com"                                                          = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "existingsg.example.com"
+      "Name"                                                                                                  = "master-us-test-1c.masters.existingsg.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1c"
+      "kubernetes.io/cluster/existingsg.example.com"                                                          = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "existingsg.example.com"
     "Name"                                                                                                  = "master-us-test-1c.masters.existingsg.example.com"
@@ -916,6 +961,7 @@ resource "aws_launch_template" "nodes-existingsg-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster existingsg.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-existingsg-example-com.id
   }
@@ -927,7 +973,6 @@ resource "aws_launch_template" "nodes-existingsg-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -965,6 +1010,18 @@ resource "aws_launch_template" "nodes-existingsg-example-com" {
       "kubernetes.io/cluster/existingsg.example.com"                               = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "existingsg.example.com"
+      "Name"                                                                       = "nodes.existingsg.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/existingsg.example.com"                               = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "existingsg.example.com"
     "Name"                                                                       = "nodes.existingsg.example.com"
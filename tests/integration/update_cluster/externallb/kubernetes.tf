@@ -411,6 +411,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-externallb-example-com
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster externallb.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-externallb-example-com.id
   }
@@ -422,7 +423,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-externallb-example-com
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -466,6 +466,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-externallb-example-com
       "kubernetes.io/cluster/externallb.example.com"                                                          = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "externallb.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.externallb.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/externallb.example.com"                                                          = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "externallb.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.externallb.example.com"
@@ -493,6 +508,7 @@ resource "aws_launch_template" "nodes-externallb-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster externallb.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-externallb-example-com.id
   }
@@ -504,7 +520,6 @@ resource "aws_launch_template" "nodes-externallb-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -542,6 +557,18 @@ resource "aws_launch_template" "nodes-externallb-example-com" {
       "kubernetes.io/cluster/externallb.example.com"                               = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "externallb.example.com"
+      "Name"                                                                       = "nodes.externallb.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/externallb.example.com"                               = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "externallb.example.com"
     "Name"                                                                       = "nodes.externallb.example.com"
@@ -493,6 +493,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-externalpolicies-examp
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster externalpolicies.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-externalpolicies-example-com.id
   }
@@ -504,7 +505,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-externalpolicies-examp
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -552,6 +552,23 @@ resource "aws_launch_template" "master-us-test-1a-masters-externalpolicies-examp
       "kubernetes.io/cluster/externalpolicies.example.com"                                                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "externalpolicies.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.externalpolicies.example.com"
+      "Owner"                                                                                                 = "John Doe"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "foo/bar"                                                                                               = "fib+baz"
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/externalpolicies.example.com"                                                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "externalpolicies.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.externalpolicies.example.com"
@@ -581,6 +598,7 @@ resource "aws_launch_template" "nodes-externalpolicies-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster externalpolicies.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-externalpolicies-example-com.id
   }
@@ -592,7 +610,6 @@ resource "aws_launch_template" "nodes-externalpolicies-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -604,7 +621,7 @@ resource "aws_launch_template" "nodes-externalpolicies-example-com" {
     associate_public_ip_address = true
     delete_on_termination       = true
     ipv6_address_count          = 0
-    security_groups             = [aws_security_group.nodes-externalpolicies-example-com.id, "sg-exampleid3", "sg-exampleid4"]
+    security_groups             = ["sg-exampleid3", "sg-exampleid4", aws_security_group.nodes-externalpolicies-example-com.id]
   }
   tag_specifications {
     resource_type = "instance"
@@ -634,6 +651,20 @@ resource "aws_launch_template" "nodes-externalpolicies-example-com" {
       "kubernetes.io/cluster/externalpolicies.example.com"                         = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "externalpolicies.example.com"
+      "Name"                                                                       = "nodes.externalpolicies.example.com"
+      "Owner"                                                                      = "John Doe"
+      "aws-node-termination-handler/managed"                                       = ""
+      "foo/bar"                                                                    = "fib+baz"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/externalpolicies.example.com"                         = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "externalpolicies.example.com"
     "Name"                                                                       = "nodes.externalpolicies.example.com"
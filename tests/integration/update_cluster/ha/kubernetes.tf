@@ -631,6 +631,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-ha-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster ha.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-ha-example-com.id
   }
@@ -642,7 +643,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-ha-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -686,6 +686,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-ha-example-com" {
       "kubernetes.io/cluster/ha.example.com"                                                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "ha.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.ha.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/ha.example.com"                                                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "ha.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.ha.example.com"
@@ -717,6 +732,7 @@ resource "aws_launch_template" "master-us-test-1b-masters-ha-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster ha.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-ha-example-com.id
   }
@@ -728,7 +744,6 @@ resource "aws_launch_template" "master-us-test-1b-masters-ha-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -772,6 +787,21 @@ resource "aws_launch_template" "master-us-test-1b-masters-ha-example-com" {
       "kubernetes.io/cluster/ha.example.com"                                                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "ha.example.com"
+      "Name"                                                                                                  = "master-us-test-1b.masters.ha.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1b"
+      "kubernetes.io/cluster/ha.example.com"                                                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "ha.example.com"
     "Name"                                                                                                  = "master-us-test-1b.masters.ha.example.com"
@@ -803,6 +833,7 @@ resource "aws_launch_template" "master-us-test-1c-masters-ha-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster ha.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-ha-example-com.id
   }
@@ -814,7 +845,6 @@ resource "aws_launch_template" "master-us-test-1c-masters-ha-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -858,6 +888,21 @@ resource "aws_launch_template" "master-us-test-1c-masters-ha-example-com" {
       "kubernetes.io/cluster/ha.example.com"                                                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "ha.example.com"
+      "Name"                                                                                                  = "master-us-test-1c.masters.ha.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1c"
+      "kubernetes.io/cluster/ha.example.com"                                                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "ha.example.com"
     "Name"                                                                                                  = "master-us-test-1c.masters.ha.example.com"
@@ -885,6 +930,7 @@ resource "aws_launch_template" "nodes-ha-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster ha.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-ha-example-com.id
   }
@@ -896,7 +942,6 @@ resource "aws_launch_template" "nodes-ha-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -934,6 +979,18 @@ resource "aws_launch_template" "nodes-ha-example-com" {
       "kubernetes.io/cluster/ha.example.com"                                       = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "ha.example.com"
+      "Name"                                                                       = "nodes.ha.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/ha.example.com"                                       = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "ha.example.com"
     "Name"                                                                       = "nodes.ha.example.com"
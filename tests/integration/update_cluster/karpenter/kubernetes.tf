@@ -564,6 +564,7 @@ resource "aws_launch_template" "karpenter-nodes-default-minimal-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-example-com.id
   }
@@ -574,7 +575,6 @@ resource "aws_launch_template" "karpenter-nodes-default-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -614,6 +614,19 @@ resource "aws_launch_template" "karpenter-nodes-default-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                   = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                           = "minimal.example.com"
+      "Name"                                                                        = "karpenter-nodes-default.minimal.example.com"
+      "aws-node-termination-handler/managed"                                        = ""
+      "k8s.io/cluster-autoscaler/node-template/label/karpenter.sh/provisioner-name" = "karpenter-nodes-default"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node"  = ""
+      "k8s.io/role/node"                                                            = "1"
+      "kops.k8s.io/instancegroup"                                                   = "karpenter-nodes-default"
+      "kubernetes.io/cluster/minimal.example.com"                                   = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                           = "minimal.example.com"
     "Name"                                                                        = "karpenter-nodes-default.minimal.example.com"
@@ -639,6 +652,7 @@ resource "aws_launch_template" "karpenter-nodes-single-machinetype-minimal-examp
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-example-com.id
   }
@@ -649,7 +663,6 @@ resource "aws_launch_template" "karpenter-nodes-single-machinetype-minimal-examp
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -689,6 +702,19 @@ resource "aws_launch_template" "karpenter-nodes-single-machinetype-minimal-examp
       "kubernetes.io/cluster/minimal.example.com"                                   = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                           = "minimal.example.com"
+      "Name"                                                                        = "karpenter-nodes-single-machinetype.minimal.example.com"
+      "aws-node-termination-handler/managed"                                        = ""
+      "k8s.io/cluster-autoscaler/node-template/label/karpenter.sh/provisioner-name" = "karpenter-nodes-single-machinetype"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node"  = ""
+      "k8s.io/role/node"                                                            = "1"
+      "kops.k8s.io/instancegroup"                                                   = "karpenter-nodes-single-machinetype"
+      "kubernetes.io/cluster/minimal.example.com"                                   = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                           = "minimal.example.com"
     "Name"                                                                        = "karpenter-nodes-single-machinetype.minimal.example.com"
@@ -718,6 +744,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-minimal-example-com.id
   }
@@ -729,7 +756,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -773,6 +799,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal.example.com"                                                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "minimal.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.minimal.example.com"
@@ -800,6 +841,7 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-example-com.id
   }
@@ -811,7 +853,6 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -849,6 +890,18 @@ resource "aws_launch_template" "nodes-minimal-example-com" {
       "kubernetes.io/cluster/minimal.example.com"                                  = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal.example.com"
+      "Name"                                                                       = "nodes.minimal.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal.example.com"                                  = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "minimal.example.com"
     "Name"                                                                       = "nodes.minimal.example.com"
@@ -470,6 +470,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-ipv6-example-c
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster minimal-ipv6.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-minimal-ipv6-example-com.id
   }
@@ -525,6 +526,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-ipv6-example-c
       "kubernetes.io/cluster/minimal-ipv6.example.com"                                                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal-ipv6.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal-ipv6.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal-ipv6.example.com"                                                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "minimal-ipv6.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.minimal-ipv6.example.com"
@@ -552,6 +568,7 @@ resource "aws_launch_template" "nodes-minimal-ipv6-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal-ipv6.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-ipv6-example-com.id
   }
@@ -601,6 +618,18 @@ resource "aws_launch_template" "nodes-minimal-ipv6-example-com" {
       "kubernetes.io/cluster/minimal-ipv6.example.com"                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal-ipv6.example.com"
+      "Name"                                                                       = "nodes.minimal-ipv6.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal-ipv6.example.com"                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "minimal-ipv6.example.com"
     "Name"                                                                       = "nodes.minimal-ipv6.example.com"
@@ -419,6 +419,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-warmpool-examp
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster minimal-warmpool.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-minimal-warmpool-example-com.id
   }
@@ -430,7 +431,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-warmpool-examp
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -474,6 +474,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-minimal-warmpool-examp
       "kubernetes.io/cluster/minimal-warmpool.example.com"                                                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "minimal-warmpool.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.minimal-warmpool.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/minimal-warmpool.example.com"                                                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "minimal-warmpool.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.minimal-warmpool.example.com"
@@ -501,6 +516,7 @@ resource "aws_launch_template" "nodes-minimal-warmpool-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster minimal-warmpool.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-minimal-warmpool-example-com.id
   }
@@ -512,7 +528,6 @@ resource "aws_launch_template" "nodes-minimal-warmpool-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -550,6 +565,18 @@ resource "aws_launch_template" "nodes-minimal-warmpool-example-com" {
       "kubernetes.io/cluster/minimal-warmpool.example.com"                         = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "minimal-warmpool.example.com"
+      "Name"                                                                       = "nodes.minimal-warmpool.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/minimal-warmpool.example.com"                         = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "minimal-warmpool.example.com"
     "Name"                                                                       = "nodes.minimal-warmpool.example.com"
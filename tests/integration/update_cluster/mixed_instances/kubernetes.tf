@@ -649,6 +649,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-mixedinstances-example
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster mixedinstances.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-mixedinstances-example-com.id
   }
@@ -660,7 +661,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-mixedinstances-example
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -704,6 +704,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-mixedinstances-example
       "kubernetes.io/cluster/mixedinstances.example.com"                                                      = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "mixedinstances.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.mixedinstances.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/mixedinstances.example.com"                                                      = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "mixedinstances.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.mixedinstances.example.com"
@@ -735,6 +750,7 @@ resource "aws_launch_template" "master-us-test-1b-masters-mixedinstances-example
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster mixedinstances.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-mixedinstances-example-com.id
   }
@@ -746,7 +762,6 @@ resource "aws_launch_template" "master-us-test-1b-masters-mixedinstances-example
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -790,6 +805,21 @@ resource "aws_launch_template" "master-us-test-1b-masters-mixedinstances-example
       "kubernetes.io/cluster/mixedinstances.example.com"                                                      = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "mixedinstances.example.com"
+      "Name"                                                                                                  = "master-us-test-1b.masters.mixedinstances.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1b"
+      "kubernetes.io/cluster/mixedinstances.example.com"                                                      = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "mixedinstances.example.com"
     "Name"                                                                                                  = "master-us-test-1b.masters.mixedinstances.example.com"
@@ -821,6 +851,7 @@ resource "aws_launch_template" "master-us-test-1c-masters-mixedinstances-example
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster mixedinstances.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-mixedinstances-example-com.id
   }
@@ -832,7 +863,6 @@ resource "aws_launch_template" "master-us-test-1c-masters-mixedinstances-example
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -876,6 +906,21 @@ resource "aws_launch_template" "master-us-test-1c-masters-mixedinstances-example
       "kubernetes.io/cluster/mixedinstances.example.com"                                                      = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "mixedinstances.example.com"
+      "Name"                                                                                                  = "master-us-test-1c.masters.mixedinstances.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1c"
+      "kubernetes.io/cluster/mixedinstances.example.com"                                                      = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "mixedinstances.example.com"
     "Name"                                                                                                  = "master-us-test-1c.masters.mixedinstances.example.com"
@@ -903,6 +948,7 @@ resource "aws_launch_template" "nodes-mixedinstances-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster mixedinstances.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-mixedinstances-example-com.id
   }
@@ -914,7 +960,6 @@ resource "aws_launch_template" "nodes-mixedinstances-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -952,6 +997,18 @@ resource "aws_launch_template" "nodes-mixedinstances-example-com" {
       "kubernetes.io/cluster/mixedinstances.example.com"                           = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "mixedinstances.example.com"
+      "Name"                                                                       = "nodes.mixedinstances.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/mixedinstances.example.com"                           = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "mixedinstances.example.com"
     "Name"                                                                       = "nodes.mixedinstances.example.com"
@@ -321,6 +321,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-nthimdsprocessor-longc
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster nthimdsprocessor.longclustername.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-nthimdsprocessor-longclustername-example-com.id
   }
@@ -332,7 +333,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-nthimdsprocessor-longc
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -374,6 +374,20 @@ resource "aws_launch_template" "master-us-test-1a-masters-nthimdsprocessor-longc
       "kubernetes.io/cluster/nthimdsprocessor.longclustername.example.com"                                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "nthimdsprocessor.longclustername.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.nthimdsprocessor.longclustername.example.com"
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/nthimdsprocessor.longclustername.example.com"                                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "nthimdsprocessor.longclustername.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.nthimdsprocessor.longclustername.example.com"
@@ -400,6 +414,7 @@ resource "aws_launch_template" "nodes-nthimdsprocessor-longclustername-example-c
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster nthimdsprocessor.longclustername.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-nthimdsprocessor-longclustername-example-com.id
   }
@@ -411,7 +426,6 @@ resource "aws_launch_template" "nodes-nthimdsprocessor-longclustername-example-c
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -447,6 +461,17 @@ resource "aws_launch_template" "nodes-nthimdsprocessor-longclustername-example-c
       "kubernetes.io/cluster/nthimdsprocessor.longclustername.example.com"         = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "nthimdsprocessor.longclustername.example.com"
+      "Name"                                                                       = "nodes.nthimdsprocessor.longclustername.example.com"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/nthimdsprocessor.longclustername.example.com"         = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "nthimdsprocessor.longclustername.example.com"
     "Name"                                                                       = "nodes.nthimdsprocessor.longclustername.example.com"
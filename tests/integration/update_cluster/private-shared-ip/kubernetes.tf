@@ -533,6 +533,7 @@ resource "aws_launch_template" "bastion-private-shared-ip-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster private-shared-ip.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-private-shared-ip-example-com.id
   }
@@ -544,7 +545,6 @@ resource "aws_launch_template" "bastion-private-shared-ip-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -580,6 +580,17 @@ resource "aws_launch_template" "bastion-private-shared-ip-example-com" {
       "kubernetes.io/cluster/private-shared-ip.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                   = "private-shared-ip.example.com"
+      "Name"                                                = "bastion.private-shared-ip.example.com"
+      "aws-node-termination-handler/managed"                = ""
+      "k8s.io/role/bastion"                                 = "1"
+      "kops.k8s.io/instancegroup"                           = "bastion"
+      "kubernetes.io/cluster/private-shared-ip.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                   = "private-shared-ip.example.com"
     "Name"                                                = "bastion.private-shared-ip.example.com"
@@ -606,6 +617,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-private-shared-ip-exam
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster private-shared-ip.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-private-shared-ip-example-com.id
   }
@@ -617,7 +629,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-private-shared-ip-exam
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -661,6 +672,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-private-shared-ip-exam
       "kubernetes.io/cluster/private-shared-ip.example.com"                                                   = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "private-shared-ip.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.private-shared-ip.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/private-shared-ip.example.com"                                                   = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "private-shared-ip.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.private-shared-ip.example.com"
@@ -688,6 +714,7 @@ resource "aws_launch_template" "nodes-private-shared-ip-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster private-shared-ip.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-private-shared-ip-example-com.id
   }
@@ -699,7 +726,6 @@ resource "aws_launch_template" "nodes-private-shared-ip-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -737,6 +763,18 @@ resource "aws_launch_template" "nodes-private-shared-ip-example-com" {
       "kubernetes.io/cluster/private-shared-ip.example.com"                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "private-shared-ip.example.com"
+      "Name"                                                                       = "nodes.private-shared-ip.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/private-shared-ip.example.com"                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "private-shared-ip.example.com"
     "Name"                                                                       = "nodes.private-shared-ip.example.com"
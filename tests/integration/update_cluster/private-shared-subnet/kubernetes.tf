@@ -528,6 +528,7 @@ resource "aws_launch_template" "bastion-private-shared-subnet-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster private-shared-subnet.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-private-shared-subnet-example-com.id
   }
@@ -539,7 +540,6 @@ resource "aws_launch_template" "bastion-private-shared-subnet-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -575,6 +575,17 @@ resource "aws_launch_template" "bastion-private-shared-subnet-example-com" {
       "kubernetes.io/cluster/private-shared-subnet.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                       = "private-shared-subnet.example.com"
+      "Name"                                                    = "bastion.private-shared-subnet.example.com"
+      "aws-node-termination-handler/managed"                    = ""
+      "k8s.io/role/bastion"                                     = "1"
+      "kops.k8s.io/instancegroup"                               = "bastion"
+      "kubernetes.io/cluster/private-shared-subnet.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                       = "private-shared-subnet.example.com"
     "Name"                                                    = "bastion.private-shared-subnet.example.com"
@@ -601,6 +612,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-private-shared-subnet-
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster private-shared-subnet.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-private-shared-subnet-example-com.id
   }
@@ -612,7 +624,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-private-shared-subnet-
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -656,6 +667,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-private-shared-subnet-
       "kubernetes.io/cluster/private-shared-subnet.example.com"                                               = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "private-shared-subnet.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.private-shared-subnet.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/private-shared-subnet.example.com"                                               = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "private-shared-subnet.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.private-shared-subnet.example.com"
@@ -683,6 +709,7 @@ resource "aws_launch_template" "nodes-private-shared-subnet-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster private-shared-subnet.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-private-shared-subnet-example-com.id
   }
@@ -694,7 +721,6 @@ resource "aws_launch_template" "nodes-private-shared-subnet-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -732,6 +758,18 @@ resource "aws_launch_template" "nodes-private-shared-subnet-example-com" {
       "kubernetes.io/cluster/private-shared-subnet.example.com"                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "private-shared-subnet.example.com"
+      "Name"                                                                       = "nodes.private-shared-subnet.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/private-shared-subnet.example.com"                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "private-shared-subnet.example.com"
     "Name"                                                                       = "nodes.private-shared-subnet.example.com"
@@ -551,6 +551,7 @@ resource "aws_launch_template" "bastion-privatecalico-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatecalico.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privatecalico-example-com.id
   }
@@ -562,7 +563,6 @@ resource "aws_launch_template" "bastion-privatecalico-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -598,6 +598,17 @@ resource "aws_launch_template" "bastion-privatecalico-example-com" {
       "kubernetes.io/cluster/privatecalico.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                               = "privatecalico.example.com"
+      "Name"                                            = "bastion.privatecalico.example.com"
+      "aws-node-termination-handler/managed"            = ""
+      "k8s.io/role/bastion"                             = "1"
+      "kops.k8s.io/instancegroup"                       = "bastion"
+      "kubernetes.io/cluster/privatecalico.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                               = "privatecalico.example.com"
     "Name"                                            = "bastion.privatecalico.example.com"
@@ -620,6 +631,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatecalico-example-
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatecalico.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privatecalico-example-com.id
   }
@@ -631,7 +643,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatecalico-example-
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -675,6 +686,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatecalico-example-
       "kubernetes.io/cluster/privatecalico.example.com"                                                       = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privatecalico.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privatecalico.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privatecalico.example.com"                                                       = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privatecalico.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privatecalico.example.com"
@@ -702,6 +728,7 @@ resource "aws_launch_template" "nodes-privatecalico-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatecalico.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privatecalico-example-com.id
   }
@@ -713,7 +740,6 @@ resource "aws_launch_template" "nodes-privatecalico-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 3
     http_tokens                 = "required"
   }
@@ -751,6 +777,18 @@ resource "aws_launch_template" "nodes-privatecalico-example-com" {
       "kubernetes.io/cluster/privatecalico.example.com"                            = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privatecalico.example.com"
+      "Name"                                                                       = "nodes.privatecalico.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privatecalico.example.com"                            = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privatecalico.example.com"
     "Name"                                                                       = "nodes.privatecalico.example.com"
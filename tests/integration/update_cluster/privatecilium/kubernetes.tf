@@ -551,6 +551,7 @@ resource "aws_launch_template" "bastion-privatecilium-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatecilium.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privatecilium-example-com.id
   }
@@ -562,7 +563,6 @@ resource "aws_launch_template" "bastion-privatecilium-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -598,6 +598,17 @@ resource "aws_launch_template" "bastion-privatecilium-example-com" {
       "kubernetes.io/cluster/privatecilium.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                               = "privatecilium.example.com"
+      "Name"                                            = "bastion.privatecilium.example.com"
+      "aws-node-termination-handler/managed"            = ""
+      "k8s.io/role/bastion"                             = "1"
+      "kops.k8s.io/instancegroup"                       = "bastion"
+      "kubernetes.io/cluster/privatecilium.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                               = "privatecilium.example.com"
     "Name"                                            = "bastion.privatecilium.example.com"
@@ -624,6 +635,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatecilium-example-
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster privatecilium.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privatecilium-example-com.id
   }
@@ -635,7 +647,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatecilium-example-
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -679,6 +690,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatecilium-example-
       "kubernetes.io/cluster/privatecilium.example.com"                                                       = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privatecilium.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privatecilium.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privatecilium.example.com"                                                       = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privatecilium.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privatecilium.example.com"
@@ -706,6 +732,7 @@ resource "aws_launch_template" "nodes-privatecilium-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatecilium.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privatecilium-example-com.id
   }
@@ -717,7 +744,6 @@ resource "aws_launch_template" "nodes-privatecilium-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -755,6 +781,18 @@ resource "aws_launch_template" "nodes-privatecilium-example-com" {
       "kubernetes.io/cluster/privatecilium.example.com"                            = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privatecilium.example.com"
+      "Name"                                                                       = "nodes.privatecilium.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privatecilium.example.com"                            = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privatecilium.example.com"
     "Name"                                                                       = "nodes.privatecilium.example.com"
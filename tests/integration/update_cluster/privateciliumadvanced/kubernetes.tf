@@ -568,6 +568,7 @@ resource "aws_launch_template" "bastion-privateciliumadvanced-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privateciliumadvanced.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privateciliumadvanced-example-com.id
   }
@@ -579,7 +580,6 @@ resource "aws_launch_template" "bastion-privateciliumadvanced-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -615,6 +615,17 @@ resource "aws_launch_template" "bastion-privateciliumadvanced-example-com" {
       "kubernetes.io/cluster/privateciliumadvanced.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                       = "privateciliumadvanced.example.com"
+      "Name"                                                    = "bastion.privateciliumadvanced.example.com"
+      "aws-node-termination-handler/managed"                    = ""
+      "k8s.io/role/bastion"                                     = "1"
+      "kops.k8s.io/instancegroup"                               = "bastion"
+      "kubernetes.io/cluster/privateciliumadvanced.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                       = "privateciliumadvanced.example.com"
     "Name"                                                    = "bastion.privateciliumadvanced.example.com"
@@ -641,6 +652,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privateciliumadvanced-
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster privateciliumadvanced.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privateciliumadvanced-example-com.id
   }
@@ -652,7 +664,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privateciliumadvanced-
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -696,6 +707,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-privateciliumadvanced-
       "kubernetes.io/cluster/privateciliumadvanced.example.com"                                               = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privateciliumadvanced.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privateciliumadvanced.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privateciliumadvanced.example.com"                                               = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privateciliumadvanced.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privateciliumadvanced.example.com"
@@ -723,6 +749,7 @@ resource "aws_launch_template" "nodes-privateciliumadvanced-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privateciliumadvanced.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privateciliumadvanced-example-com.id
   }
@@ -734,7 +761,6 @@ resource "aws_launch_template" "nodes-privateciliumadvanced-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -772,6 +798,18 @@ resource "aws_launch_template" "nodes-privateciliumadvanced-example-com" {
       "kubernetes.io/cluster/privateciliumadvanced.example.com"                    = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privateciliumadvanced.example.com"
+      "Name"                                                                       = "nodes.privateciliumadvanced.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privateciliumadvanced.example.com"                    = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privateciliumadvanced.example.com"
     "Name"                                                                       = "nodes.privateciliumadvanced.example.com"
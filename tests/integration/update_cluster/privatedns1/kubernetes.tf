@@ -613,6 +613,7 @@ resource "aws_launch_template" "bastion-privatedns1-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatedns1.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privatedns1-example-com.id
   }
@@ -624,7 +625,6 @@ resource "aws_launch_template" "bastion-privatedns1-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -664,6 +664,19 @@ resource "aws_launch_template" "bastion-privatedns1-example-com" {
       "kubernetes.io/cluster/privatedns1.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                             = "privatedns1.example.com"
+      "Name"                                          = "bastion.privatedns1.example.com"
+      "Owner"                                         = "John Doe"
+      "aws-node-termination-handler/managed"          = ""
+      "foo/bar"                                       = "fib+baz"
+      "k8s.io/role/bastion"                           = "1"
+      "kops.k8s.io/instancegroup"                     = "bastion"
+      "kubernetes.io/cluster/privatedns1.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                             = "privatedns1.example.com"
     "Name"                                          = "bastion.privatedns1.example.com"
@@ -692,6 +705,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatedns1-example-co
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster privatedns1.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privatedns1-example-com.id
   }
@@ -703,7 +717,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatedns1-example-co
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -751,6 +764,23 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatedns1-example-co
       "kubernetes.io/cluster/privatedns1.example.com"                                                         = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privatedns1.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privatedns1.example.com"
+      "Owner"                                                                                                 = "John Doe"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "foo/bar"                                                                                               = "fib+baz"
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privatedns1.example.com"                                                         = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privatedns1.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privatedns1.example.com"
@@ -780,6 +810,7 @@ resource "aws_launch_template" "nodes-privatedns1-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatedns1.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privatedns1-example-com.id
   }
@@ -791,7 +822,6 @@ resource "aws_launch_template" "nodes-privatedns1-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "required"
   }
@@ -833,6 +863,20 @@ resource "aws_launch_template" "nodes-privatedns1-example-com" {
       "kubernetes.io/cluster/privatedns1.example.com"                              = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privatedns1.example.com"
+      "Name"                                                                       = "nodes.privatedns1.example.com"
+      "Owner"                                                                      = "John Doe"
+      "aws-node-termination-handler/managed"                                       = ""
+      "foo/bar"                                                                    = "fib+baz"
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privatedns1.example.com"                              = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privatedns1.example.com"
     "Name"                                                                       = "nodes.privatedns1.example.com"
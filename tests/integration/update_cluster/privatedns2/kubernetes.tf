@@ -542,6 +542,7 @@ resource "aws_launch_template" "bastion-privatedns2-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatedns2.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privatedns2-example-com.id
   }
@@ -553,7 +554,6 @@ resource "aws_launch_template" "bastion-privatedns2-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -589,6 +589,17 @@ resource "aws_launch_template" "bastion-privatedns2-example-com" {
       "kubernetes.io/cluster/privatedns2.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                             = "privatedns2.example.com"
+      "Name"                                          = "bastion.privatedns2.example.com"
+      "aws-node-termination-handler/managed"          = ""
+      "k8s.io/role/bastion"                           = "1"
+      "kops.k8s.io/instancegroup"                     = "bastion"
+      "kubernetes.io/cluster/privatedns2.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                             = "privatedns2.example.com"
     "Name"                                          = "bastion.privatedns2.example.com"
@@ -615,6 +626,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatedns2-example-co
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster privatedns2.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privatedns2-example-com.id
   }
@@ -626,7 +638,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatedns2-example-co
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -670,6 +681,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatedns2-example-co
       "kubernetes.io/cluster/privatedns2.example.com"                                                         = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privatedns2.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privatedns2.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privatedns2.example.com"                                                         = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privatedns2.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privatedns2.example.com"
@@ -697,6 +723,7 @@ resource "aws_launch_template" "nodes-privatedns2-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatedns2.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privatedns2-example-com.id
   }
@@ -708,7 +735,6 @@ resource "aws_launch_template" "nodes-privatedns2-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -746,6 +772,18 @@ resource "aws_launch_template" "nodes-privatedns2-example-com" {
       "kubernetes.io/cluster/privatedns2.example.com"                              = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privatedns2.example.com"
+      "Name"                                                                       = "nodes.privatedns2.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privatedns2.example.com"                              = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privatedns2.example.com"
     "Name"                                                                       = "nodes.privatedns2.example.com"
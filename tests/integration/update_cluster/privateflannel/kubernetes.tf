@@ -551,6 +551,7 @@ resource "aws_launch_template" "bastion-privateflannel-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privateflannel.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privateflannel-example-com.id
   }
@@ -562,7 +563,6 @@ resource "aws_launch_template" "bastion-privateflannel-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -598,6 +598,17 @@ resource "aws_launch_template" "bastion-privateflannel-example-com" {
       "kubernetes.io/cluster/privateflannel.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                = "privateflannel.example.com"
+      "Name"                                             = "bastion.privateflannel.example.com"
+      "aws-node-termination-handler/managed"             = ""
+      "k8s.io/role/bastion"                              = "1"
+      "kops.k8s.io/instancegroup"                        = "bastion"
+      "kubernetes.io/cluster/privateflannel.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                = "privateflannel.example.com"
     "Name"                                             = "bastion.privateflannel.example.com"
@@ -624,6 +635,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privateflannel-example
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster privateflannel.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privateflannel-example-com.id
   }
@@ -635,7 +647,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privateflannel-example
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -679,6 +690,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-privateflannel-example
       "kubernetes.io/cluster/privateflannel.example.com"                                                      = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privateflannel.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privateflannel.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privateflannel.example.com"                                                      = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privateflannel.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privateflannel.example.com"
@@ -706,6 +732,7 @@ resource "aws_launch_template" "nodes-privateflannel-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privateflannel.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privateflannel-example-com.id
   }
@@ -717,7 +744,6 @@ resource "aws_launch_template" "nodes-privateflannel-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -755,6 +781,18 @@ resource "aws_launch_template" "nodes-privateflannel-example-com" {
       "kubernetes.io/cluster/privateflannel.example.com"                           = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privateflannel.example.com"
+      "Name"                                                                       = "nodes.privateflannel.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privateflannel.example.com"                           = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privateflannel.example.com"
     "Name"                                                                       = "nodes.privateflannel.example.com"
@@ -557,6 +557,7 @@ resource "aws_launch_template" "bastion-privatekopeio-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatekopeio.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.bastions-privatekopeio-example-com.id
   }
@@ -568,7 +569,6 @@ resource "aws_launch_template" "bastion-privatekopeio-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -604,6 +604,17 @@ resource "aws_launch_template" "bastion-privatekopeio-example-com" {
       "kubernetes.io/cluster/privatekopeio.example.com" = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                               = "privatekopeio.example.com"
+      "Name"                                            = "bastion.privatekopeio.example.com"
+      "aws-node-termination-handler/managed"            = ""
+      "k8s.io/role/bastion"                             = "1"
+      "kops.k8s.io/instancegroup"                       = "bastion"
+      "kubernetes.io/cluster/privatekopeio.example.com" = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                               = "privatekopeio.example.com"
     "Name"                                            = "bastion.privatekopeio.example.com"
@@ -630,6 +641,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatekopeio-example-
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster privatekopeio.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-privatekopeio-example-com.id
   }
@@ -641,7 +653,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatekopeio-example-
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -685,6 +696,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-privatekopeio-example-
       "kubernetes.io/cluster/privatekopeio.example.com"                                                       = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "privatekopeio.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.privatekopeio.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/privatekopeio.example.com"                                                       = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "privatekopeio.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.privatekopeio.example.com"
@@ -712,6 +738,7 @@ resource "aws_launch_template" "nodes-privatekopeio-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster privatekopeio.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-privatekopeio-example-com.id
   }
@@ -723,7 +750,6 @@ resource "aws_launch_template" "nodes-privatekopeio-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -761,6 +787,18 @@ resource "aws_launch_template" "nodes-privatekopeio-example-com" {
       "kubernetes.io/cluster/privatekopeio.example.com"                            = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "privatekopeio.example.com"
+      "Name"                                                                       = "nodes.privatekopeio.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/privatekopeio.example.com"                            = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "privatekopeio.example.com"
     "Name"                                                                       = "nodes.privatekopeio.example.com"
@@ -398,6 +398,7 @@ resource "aws_launch_template" "master-us-test-1a-masters-sharedsubnet-example-c
     device_name  = "/dev/sdc"
     virtual_name = "ephemeral0"
   }
+  description = "Managed by kOps for cluster sharedsubnet.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.masters-sharedsubnet-example-com.id
   }
@@ -409,7 +410,6 @@ resource "aws_launch_template" "master-us-test-1a-masters-sharedsubnet-example-c
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -453,6 +453,21 @@ resource "aws_launch_template" "master-us-test-1a-masters-sharedsubnet-example-c
       "kubernetes.io/cluster/sharedsubnet.example.com"                                                        = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                                                     = "sharedsubnet.example.com"
+      "Name"                                                                                                  = "master-us-test-1a.masters.sharedsubnet.example.com"
+      "aws-node-termination-handler/managed"                                                                  = ""
+      "k8s.io/cluster-autoscaler/node-template/label/kops.k8s.io/kops-controller-pki"                         = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/control-plane"                   = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node.kubernetes.io/exclude-from-external-load-balancers" = ""
+      "k8s.io/role/control-plane"                                                                             = "1"
+      "k8s.io/role/master"                                                                                    = "1"
+      "kops.k8s.io/instancegroup"                                                                             = "master-us-test-1a"
+      "kubernetes.io/cluster/sharedsubnet.example.com"                                                        = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                                                     = "sharedsubnet.example.com"
     "Name"                                                                                                  = "master-us-test-1a.masters.sharedsubnet.example.com"
@@ -480,6 +495,7 @@ resource "aws_launch_template" "nodes-sharedsubnet-example-com" {
       volume_type           = "gp3"
     }
   }
+  description = "Managed by kOps for cluster sharedsubnet.example.com"
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes-sharedsubnet-example-com.id
   }
@@ -491,7 +507,6 @@ resource "aws_launch_template" "nodes-sharedsubnet-example-com" {
   }
   metadata_options {
     http_endpoint               = "enabled"
-    http_protocol_ipv6          = "disabled"
     http_put_response_hop_limit = 1
     http_tokens                 = "optional"
   }
@@ -529,6 +544,18 @@ resource "aws_launch_template" "nodes-sharedsubnet-example-com" {
       "kubernetes.io/cluster/sharedsubnet.example.com"                             = "owned"
     }
   }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "KubernetesCluster"                                                          = "sharedsubnet.example.com"
+      "Name"                                                                       = "nodes.sharedsubnet.example.com"
+      "aws-node-termination-handler/managed"                                       = ""
+      "k8s.io/cluster-autoscaler/node-template/label/node-role.kubernetes.io/node" = ""
+      "k8s.io/role/node"                                                           = "1"
+      "kops.k8s.io/instancegroup"                                                  = "nodes"
+      "kubernetes.io/cluster/sharedsubnet.example.com"                             = "owned"
+    }
+  }
   tags = {
     "KubernetesCluster"                                                          = "sharedsubnet.example.com"
     "Name"                                                                       = "nodes.sharedsubnet.example.com"
@@ -18,6 +18,7 @@ package awstasks
 
 import (
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	autoscalingtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
@@ -34,6 +35,9 @@ type BlockDeviceMapping struct {
 	EbsEncrypted *bool
 	// EbsKmsKey is the encryption key identifier for the volume
 	EbsKmsKey *string
+	// EbsKmsKeyLink references the encryption key for the volume in terraform, e.g. an aws_kms_key managed alongside the cluster.
+	// It takes precedence over EbsKmsKey when rendering terraform, and is not used otherwise.
+	EbsKmsKeyLink *terraformWriter.Literal
 	// EbsVolumeIops is the provisioned iops for the volume
 	EbsVolumeIops *int32
 	// EbsVolumeThroughput is the throughput for the volume
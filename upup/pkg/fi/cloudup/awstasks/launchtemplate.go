@@ -18,12 +18,15 @@ package awstasks
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
 
 // LaunchTemplate defines the specification for a launch template.
@@ -36,18 +39,48 @@ type LaunchTemplate struct {
 	// Lifecycle is the resource lifecycle
 	Lifecycle fi.Lifecycle
 
+	// AdditionalNetworkInterfaces are the network interfaces attached to the instances in addition to the primary one,
+	// for instance types with multiple network cards.
+	AdditionalNetworkInterfaces []*LaunchTemplateNetworkInterface
 	// AssociatePublicIP indicates if a public ip address is assigned to instances
 	AssociatePublicIP *bool
+	// AssociateIPv6Address indicates if an IPv6 address is assigned to the primary network interface.
+	// Neither EC2 nor terraform have a dedicated setting for this, so it is expressed through the IPv6 address count.
+	AssociateIPv6Address *bool
 	// BlockDeviceMappings is a block device mappings
 	BlockDeviceMappings []*BlockDeviceMapping
+	// CapacityBlock launches the instances with the capacity-block market type, for use with Capacity Blocks for ML.
+	// It cannot be combined with SpotPrice.
+	CapacityBlock *bool
 	// CPUCredits is the credit option for CPU Usage on some instance types
 	CPUCredits *string
+	// EphemeralDeviceNames overrides the device names the instance store volumes of the instance type are mapped to,
+	// keyed by their virtual name (e.g. ephemeral0). An empty device name leaves the volume unmapped.
+	EphemeralDeviceNames map[string]string
+	// ConnectionTrackingTCPEstablishedTimeout is the idle timeout in seconds of established TCP connections tracked on the primary network interface
+	ConnectionTrackingTCPEstablishedTimeout *int32
+	// ConnectionTrackingUDPStreamTimeout is the idle timeout in seconds of UDP flows classified as streams tracked on the primary network interface
+	ConnectionTrackingUDPStreamTimeout *int32
+	// ConnectionTrackingUDPTimeout is the idle timeout in seconds of other UDP flows tracked on the primary network interface
+	ConnectionTrackingUDPTimeout *int32
+	// DefaultVolumeKmsKey is the encryption key identifier for the encrypted EBS volumes that don't set their own key
+	DefaultVolumeKmsKey *string
+	// DefaultVolumeKmsKeyLink references the default encryption key in terraform, taking precedence over DefaultVolumeKmsKey there
+	DefaultVolumeKmsKeyLink *terraformWriter.Literal
+	// DisableAPIStop protects the instances from being stopped through the EC2 API
+	DisableAPIStop *bool
+	// EnaSrdEnabled enables ENA Express on the primary network interface
+	EnaSrdEnabled *bool
+	// EnaSrdUDPEnabled enables ENA Express for UDP traffic on the primary network interface
+	EnaSrdUDPEnabled *bool
 	// HTTPPutResponseHopLimit is the desired HTTP PUT response hop limit for instance metadata requests.
 	HTTPPutResponseHopLimit *int32
 	// HTTPTokens is the state of token usage for your instance metadata requests.
 	HTTPTokens *ec2types.LaunchTemplateHttpTokensState
 	// HTTPProtocolIPv6 enables the IPv6 instance metadata endpoint
 	HTTPProtocolIPv6 *ec2types.LaunchTemplateInstanceMetadataProtocolIpv6
+	// InstanceMetadataTags exposes the instance tags through the instance metadata endpoint
+	InstanceMetadataTags *ec2types.LaunchTemplateInstanceMetadataTagsState
 	// IAMInstanceProfile is the IAM profile to assign to the nodes
 	IAMInstanceProfile *IAMInstanceProfile
 	// ImageID is the AMI to use for the instances
@@ -59,8 +92,24 @@ type LaunchTemplate struct {
 	InstanceMonitoring *bool
 	// InstanceType is the type of instance we are using
 	InstanceType *ec2types.InstanceType
+	// KernelID is the ID of the kernel to boot the AMI with.
+	// This is rarely needed; most AMIs, and all HVM AMIs, provide their own kernel.
+	KernelID *string
 	// Ipv6AddressCount is the number of IPv6 addresses to assign with the primary network interface.
 	IPv6AddressCount *int32
+	// RamdiskID is the ID of the RAM disk to boot the AMI with.
+	// Like KernelID, this is only needed for AMIs that require a specific RAM disk.
+	RamdiskID *string
+	// PlacementGroupName is the name of the placement group for the instances
+	PlacementGroupName *string
+	// PlacementPartitionNumber is the partition of a partition placement group the instances are launched in.
+	// Whether the placement group uses the partition strategy and has this many partitions is only
+	// checked by EC2 when launching the instances.
+	PlacementPartitionNumber *int32
+	// PlacementHostResourceGroupARN is the ARN of the host resource group the instances are launched in
+	PlacementHostResourceGroupARN *string
+	// PlacementHostID is the ID of the Dedicated Host the instances are launched on
+	PlacementHostID *string
 	// RootVolumeIops is the provisioned IOPS when the volume type is io1, io2 or gp3
 	RootVolumeIops *int32
 	// RootVolumeOptimization enables EBS optimization for an instance
@@ -79,16 +128,38 @@ type LaunchTemplate struct {
 	SSHKey *SSHKey
 	// SecurityGroups is a list of security group associated
 	SecurityGroups []*SecurityGroup
+	// SecurityGroupsOnTemplate attaches the SecurityGroups to the launch template itself (vpc_security_group_ids in terraform),
+	// instead of to the primary network interface.
+	// EC2 does not allow security groups on both the launch template and a network interface, so no network interface is
+	// configured in this mode, and it is mutually exclusive with AssociatePublicIP, IPv6 addresses and ENA Express.
+	SecurityGroupsOnTemplate *bool
 	// SpotPrice is set to the spot-price bid if this is a spot pricing request
 	SpotPrice *string
 	// SpotDurationInMinutes is set for requesting spot blocks
 	SpotDurationInMinutes *int32
-	// Tags are the keypairs to apply to the instance and volume on launch as well as the launch template itself.
+	// Tags are the keypairs to apply to the instance, volume and network interface on launch as well as the launch template itself.
 	Tags map[string]string
+	// TagOverrides are additional keypairs applied on launch to the volume or network interface, on top of Tags.
+	TagOverrides map[ec2types.ResourceType]map[string]string
 	// Tenancy. Can be either default or dedicated.
 	Tenancy *ec2types.Tenancy
 	// UserData is the user data configuration
 	UserData fi.Resource
+	// VolumeThroughputPerGiB scales the throughput of the gp3 volumes that don't set their own with their size, in MiBps per GiB,
+	// within the limits of gp3 volumes. It is only applied when rendering to terraform.
+	VolumeThroughputPerGiB *float64
+	// VersionDescription is the description of the launch template versions, for auditing their changes.
+	// If not set, it defaults to a description naming the cluster.
+	VersionDescription *string
+}
+
+// LaunchTemplateNetworkInterface is an additional network interface of the instances,
+// with the same security groups as the primary network interface.
+type LaunchTemplateNetworkInterface struct {
+	// DeviceIndex is the position of the interface on its network card, defaulting to 0
+	DeviceIndex *int32
+	// NetworkCardIndex is the network card the interface is attached to, defaulting to 0
+	NetworkCardIndex *int32
 }
 
 var (
@@ -103,6 +174,29 @@ func (t *LaunchTemplate) CompareWithID() *string {
 	return t.ID
 }
 
+// launchTemplateTagResourceTypes are the resource types tagged on launch, in the order they are rendered
+var launchTemplateTagResourceTypes = []ec2types.ResourceType{
+	ec2types.ResourceTypeInstance,
+	ec2types.ResourceTypeVolume,
+	ec2types.ResourceTypeNetworkInterface,
+}
+
+// tagsForResourceType returns the tags applied on launch to a resource of the given type
+func (t *LaunchTemplate) tagsForResourceType(resourceType ec2types.ResourceType) map[string]string {
+	overrides := t.TagOverrides[resourceType]
+	if len(overrides) == 0 {
+		return t.Tags
+	}
+	tags := make(map[string]string, len(t.Tags)+len(overrides))
+	for k, v := range t.Tags {
+		tags[k] = v
+	}
+	for k, v := range overrides {
+		tags[k] = v
+	}
+	return tags
+}
+
 // buildRootDevice is responsible for retrieving a boot device mapping from the image name
 func (t *LaunchTemplate) buildRootDevice(cloud awsup.AWSCloud) (map[string]*BlockDeviceMapping, error) {
 	image := fi.ValueOf(t.ImageID)
@@ -126,8 +220,11 @@ func (t *LaunchTemplate) buildRootDevice(cloud awsup.AWSCloud) (map[string]*Bloc
 		EbsVolumeThroughput:    t.RootVolumeThroughput,
 		EbsEncrypted:           t.RootVolumeEncryption,
 	}
-	if aws.ToBool(t.RootVolumeEncryption) && aws.ToString(t.RootVolumeKmsKey) != "" {
-		b.EbsKmsKey = t.RootVolumeKmsKey
+	if aws.ToBool(t.RootVolumeEncryption) {
+		b.EbsKmsKey = t.volumeKmsKey(t.RootVolumeKmsKey)
+		if aws.ToString(t.RootVolumeKmsKey) == "" {
+			b.EbsKmsKeyLink = t.DefaultVolumeKmsKeyLink
+		}
 	}
 
 	bm := map[string]*BlockDeviceMapping{
@@ -137,8 +234,73 @@ func (t *LaunchTemplate) buildRootDevice(cloud awsup.AWSCloud) (map[string]*Bloc
 	return bm, nil
 }
 
+// buildAdditionalDevices is responsible for mapping the additional volumes to devices,
+// encrypting the volumes that don't set their own key with the default key
+func (t *LaunchTemplate) buildAdditionalDevices() (map[string]*BlockDeviceMapping, error) {
+	devices, err := buildAdditionalDevices(t.BlockDeviceMappings)
+	if err != nil {
+		return nil, err
+	}
+	if aws.ToString(t.DefaultVolumeKmsKey) == "" && t.DefaultVolumeKmsKeyLink == nil {
+		return devices, nil
+	}
+
+	for name, device := range devices {
+		if aws.ToBool(device.EbsEncrypted) && aws.ToString(device.EbsKmsKey) == "" && device.EbsKmsKeyLink == nil {
+			d := *device
+			d.EbsKmsKey = t.volumeKmsKey(nil)
+			d.EbsKmsKeyLink = t.DefaultVolumeKmsKeyLink
+			devices[name] = &d
+		}
+	}
+	return devices, nil
+}
+
+// volumeKmsKey returns the encryption key of an encrypted volume, falling back to the default key
+func (t *LaunchTemplate) volumeKmsKey(key *string) *string {
+	if aws.ToString(key) != "" {
+		return key
+	}
+	if aws.ToString(t.DefaultVolumeKmsKey) != "" {
+		return t.DefaultVolumeKmsKey
+	}
+	return nil
+}
+
+// buildEphemeralDevices is responsible for mapping the instance store volumes of the instance type to devices,
+// applying the overrides of the device names
+func (t *LaunchTemplate) buildEphemeralDevices(cloud awsup.AWSCloud) (map[string]*BlockDeviceMapping, error) {
+	devices, err := buildEphemeralDevices(cloud, fi.ValueOf(t.InstanceType))
+	if err != nil {
+		return nil, err
+	}
+	if len(t.EphemeralDeviceNames) == 0 {
+		return devices, nil
+	}
+
+	bm := make(map[string]*BlockDeviceMapping)
+	for name, device := range devices {
+		override, found := t.EphemeralDeviceNames[fi.ValueOf(device.VirtualName)]
+		if !found {
+			bm[name] = device
+		} else if override != "" {
+			bm[override] = device
+		}
+	}
+	return bm, nil
+}
+
 func (t *LaunchTemplate) Normalize(c *fi.CloudupContext) error {
 	sort.Stable(OrderSecurityGroupsById(t.SecurityGroups))
+	// Find always reports the indices of the additional network interfaces
+	for _, ni := range t.AdditionalNetworkInterfaces {
+		if ni.DeviceIndex == nil {
+			ni.DeviceIndex = fi.PtrTo(int32(0))
+		}
+		if ni.NetworkCardIndex == nil {
+			ni.NetworkCardIndex = fi.PtrTo(int32(0))
+		}
+	}
 	return nil
 }
 
@@ -158,9 +320,176 @@ func (t *LaunchTemplate) CheckChanges(a, e, changes *LaunchTemplate) error {
 			return fi.RequiredField("Name")
 		}
 	}
+	if e.AssociateIPv6Address != nil && e.IPv6AddressCount != nil {
+		if fi.ValueOf(e.AssociateIPv6Address) && fi.ValueOf(e.IPv6AddressCount) == 0 {
+			return fmt.Errorf("AssociateIPv6Address cannot be true when IPv6AddressCount is 0")
+		}
+		if !fi.ValueOf(e.AssociateIPv6Address) && fi.ValueOf(e.IPv6AddressCount) != 0 {
+			return fmt.Errorf("AssociateIPv6Address cannot be false when IPv6AddressCount is %d", fi.ValueOf(e.IPv6AddressCount))
+		}
+	}
+	if err := validateVolumeIops("RootVolumeIops", e.RootVolumeType, e.RootVolumeIops, e.RootVolumeSize); err != nil {
+		return err
+	}
+	for _, x := range e.BlockDeviceMappings {
+		if err := validateVolumeIops(fmt.Sprintf("EbsVolumeIops of %s", fi.ValueOf(x.DeviceName)), x.EbsVolumeType, x.EbsVolumeIops, x.EbsVolumeSize); err != nil {
+			return err
+		}
+	}
+	if e.VolumeThroughputPerGiB != nil && fi.ValueOf(e.VolumeThroughputPerGiB) <= 0 {
+		return fmt.Errorf("VolumeThroughputPerGiB must be greater than 0, got %v", fi.ValueOf(e.VolumeThroughputPerGiB))
+	}
+	if len(fi.ValueOf(e.VersionDescription)) > 255 {
+		return fmt.Errorf("VersionDescription must be at most 255 characters")
+	}
+	if e.InstanceMetadataTags != nil && !slices.Contains(e.InstanceMetadataTags.Values(), *e.InstanceMetadataTags) {
+		return fmt.Errorf("InstanceMetadataTags must be one of %v, got %q", e.InstanceMetadataTags.Values(), *e.InstanceMetadataTags)
+	}
+	if fi.ValueOf(e.SecurityGroupsOnTemplate) {
+		if fi.ValueOf(e.AssociatePublicIP) || fi.ValueOf(e.AssociateIPv6Address) || fi.ValueOf(e.IPv6AddressCount) != 0 || e.EnaSrdEnabled != nil || e.EnaSrdUDPEnabled != nil {
+			return fmt.Errorf("SecurityGroupsOnTemplate cannot be combined with AssociatePublicIP, IPv6 addresses or ENA Express, as these require a network interface")
+		}
+	}
+	if e.hasConnectionTracking() && fi.ValueOf(e.SecurityGroupsOnTemplate) {
+		return fmt.Errorf("connection tracking timeouts cannot be combined with SecurityGroupsOnTemplate, as they require a network interface")
+	}
+	for _, timeout := range []struct {
+		name     string
+		value    *int32
+		min, max int32
+	}{
+		{"ConnectionTrackingTCPEstablishedTimeout", e.ConnectionTrackingTCPEstablishedTimeout, 60, 432000},
+		{"ConnectionTrackingUDPStreamTimeout", e.ConnectionTrackingUDPStreamTimeout, 60, 180},
+		{"ConnectionTrackingUDPTimeout", e.ConnectionTrackingUDPTimeout, 30, 60},
+	} {
+		if timeout.value != nil && (*timeout.value < timeout.min || *timeout.value > timeout.max) {
+			return fmt.Errorf("%s must be between %d and %d seconds, got %d", timeout.name, timeout.min, timeout.max, *timeout.value)
+		}
+	}
+	if fi.ValueOf(e.CapacityBlock) && fi.ValueOf(e.SpotPrice) != "" {
+		return fmt.Errorf("CapacityBlock cannot be combined with SpotPrice")
+	}
+	if fi.ValueOf(e.EnaSrdUDPEnabled) && !fi.ValueOf(e.EnaSrdEnabled) {
+		return fmt.Errorf("EnaSrdUDPEnabled requires EnaSrdEnabled")
+	}
+	if e.PlacementPartitionNumber != nil {
+		if e.PlacementGroupName == nil {
+			return fmt.Errorf("PlacementPartitionNumber can only be set with a partition placement group")
+		}
+		if n := fi.ValueOf(e.PlacementPartitionNumber); n < 1 || n > maxPlacementPartitions {
+			return fmt.Errorf("PlacementPartitionNumber must be between 1 and %d, got %d", maxPlacementPartitions, n)
+		}
+	}
+	if len(e.AdditionalNetworkInterfaces) > 0 {
+		if fi.ValueOf(e.SecurityGroupsOnTemplate) {
+			return fmt.Errorf("AdditionalNetworkInterfaces cannot be combined with SecurityGroupsOnTemplate")
+		}
+		if fi.ValueOf(e.AssociatePublicIP) {
+			return fmt.Errorf("AdditionalNetworkInterfaces cannot be combined with AssociatePublicIP, as EC2 does not assign public IPs to instances with multiple network interfaces")
+		}
+		// The primary network interface is always device 0 of network card 0
+		seen := map[[2]int32]bool{{0, 0}: true}
+		for _, ni := range e.AdditionalNetworkInterfaces {
+			cardIndex := fi.ValueOf(ni.NetworkCardIndex)
+			deviceIndex := fi.ValueOf(ni.DeviceIndex)
+			if cardIndex < 0 || deviceIndex < 0 {
+				return fmt.Errorf("NetworkCardIndex and DeviceIndex of network interfaces must not be negative, got %d and %d", cardIndex, deviceIndex)
+			}
+			key := [2]int32{cardIndex, deviceIndex}
+			if seen[key] {
+				return fmt.Errorf("network card %d has more than one network interface with device index %d", cardIndex, deviceIndex)
+			}
+			seen[key] = true
+		}
+	}
+	if e.PlacementHostID != nil && e.PlacementHostResourceGroupARN != nil {
+		return fmt.Errorf("PlacementHostID and PlacementHostResourceGroupARN cannot both be set")
+	}
+	if fi.ValueOf(e.Tenancy) == ec2types.TenancyHost {
+		if e.PlacementHostID == nil && e.PlacementHostResourceGroupARN == nil {
+			return fmt.Errorf("tenancy %q requires either PlacementHostID or PlacementHostResourceGroupARN", ec2types.TenancyHost)
+		}
+	} else if e.Tenancy != nil && (e.PlacementHostID != nil || e.PlacementHostResourceGroupARN != nil) {
+		return fmt.Errorf("PlacementHostID and PlacementHostResourceGroupARN can only be set with tenancy %q", ec2types.TenancyHost)
+	}
+	for resourceType := range e.TagOverrides {
+		if resourceType != ec2types.ResourceTypeVolume && resourceType != ec2types.ResourceTypeNetworkInterface {
+			return fmt.Errorf("tag overrides are not supported for resource type %q", resourceType)
+		}
+	}
+	return nil
+}
+
+// maxPlacementPartitions is the maximum number of partitions of a partition placement group in an availability zone
+const maxPlacementPartitions = 7
+
+// validateVolumeIops checks that the provisioned IOPS are valid for the type and size, in GiB, of the volume.
+// The ratio of IOPS to size is not checked if the size is not known.
+func validateVolumeIops(field string, volumeType ec2types.VolumeType, iops, size *int32) error {
+	if fi.ValueOf(iops) == 0 || volumeType == "" {
+		return nil
+	}
+	limits, found := awsup.VolumeIopsLimits[volumeType]
+	if !found {
+		return fmt.Errorf("%s cannot be set for %s volumes, only for gp3, io1 and io2 volumes", field, volumeType)
+	}
+	if *iops < limits.Min || *iops > limits.Max {
+		return fmt.Errorf("%s of %s volumes must be between %d and %d, got %d", field, volumeType, limits.Min, limits.Max, *iops)
+	}
+	if fi.ValueOf(size) > 0 && int64(*iops) > int64(limits.MaxPerGiB)*int64(*size) {
+		return fmt.Errorf("%s of %s volumes must be at most %d per GiB, got %d for %d GiB", field, volumeType, limits.MaxPerGiB, *iops, *size)
+	}
+	return nil
+}
+
+// hasConnectionTracking returns true if any connection tracking timeout is set
+func (t *LaunchTemplate) hasConnectionTracking() bool {
+	return t.ConnectionTrackingTCPEstablishedTimeout != nil || t.ConnectionTrackingUDPStreamTimeout != nil || t.ConnectionTrackingUDPTimeout != nil
+}
+
+// ipv6AddressCount returns the number of IPv6 addresses to assign with the primary network interface,
+// defaulting to a single address if AssociateIPv6Address is set.
+func (t *LaunchTemplate) ipv6AddressCount() *int32 {
+	if t.IPv6AddressCount != nil {
+		return t.IPv6AddressCount
+	}
+	if fi.ValueOf(t.AssociateIPv6Address) {
+		return fi.PtrTo(int32(1))
+	}
 	return nil
 }
 
+// versionDescription returns the description of the launch template versions,
+// defaulting to a description naming the cluster the launch template is tagged with.
+func (t *LaunchTemplate) versionDescription() *string {
+	if t.VersionDescription != nil {
+		return t.VersionDescription
+	}
+	if clusterName := t.Tags[awsup.TagClusterName]; clusterName != "" {
+		return fi.PtrTo("Managed by kOps for cluster " + clusterName)
+	}
+	return nil
+}
+
+// renderUserData returns the user data of the launch template, failing early if EC2 would reject it for its size.
+func (t *LaunchTemplate) renderUserData() ([]byte, error) {
+	if t.UserData == nil {
+		return nil, nil
+	}
+	d, err := fi.ResourceAsBytes(t.UserData)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering LaunchTemplate UserData: %v", err)
+	}
+	if len(d) > MaxUserDataSize {
+		owner := fmt.Sprintf("launch template %q", fi.ValueOf(t.Name))
+		if ig := t.Tags[nodeidentityaws.CloudTagInstanceGroupName]; ig != "" {
+			owner = fmt.Sprintf("instance group %q", ig)
+		}
+		return nil, fmt.Errorf("user data for %s is %d bytes, which exceeds the EC2 limit of %d bytes", owner, len(d), MaxUserDataSize)
+	}
+	return d, nil
+}
+
 // FindDeletions is responsible for finding launch templates which can be deleted
 func (t *LaunchTemplate) FindDeletions(c *fi.CloudupContext) ([]fi.CloudupDeletion, error) {
 	var removals []fi.CloudupDeletion
@@ -43,23 +43,45 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 
 	// @step: lets build the launch template data
 	data := &ec2types.RequestLaunchTemplateData{
+		DisableApiStop:        t.DisableAPIStop,
 		DisableApiTermination: fi.PtrTo(false),
 		EbsOptimized:          t.RootVolumeOptimization,
 		ImageId:               image.ImageId,
 		InstanceType:          fi.ValueOf(t.InstanceType),
+		KernelId:              t.KernelID,
 		MetadataOptions: &ec2types.LaunchTemplateInstanceMetadataOptionsRequest{
 			HttpPutResponseHopLimit: t.HTTPPutResponseHopLimit,
 			HttpTokens:              fi.ValueOf(t.HTTPTokens),
 			HttpProtocolIpv6:        fi.ValueOf(t.HTTPProtocolIPv6),
+			InstanceMetadataTags:    fi.ValueOf(t.InstanceMetadataTags),
 		},
 		NetworkInterfaces: []ec2types.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
 			{
 				AssociatePublicIpAddress: t.AssociatePublicIP,
 				DeleteOnTermination:      aws.Bool(true),
 				DeviceIndex:              fi.PtrTo(int32(0)),
-				Ipv6AddressCount:         t.IPv6AddressCount,
+				Ipv6AddressCount:         t.ipv6AddressCount(),
 			},
 		},
+		RamDiskId: t.RamdiskID,
+	}
+	if t.EnaSrdEnabled != nil || t.EnaSrdUDPEnabled != nil {
+		enaSrd := &ec2types.EnaSrdSpecificationRequest{
+			EnaSrdEnabled: t.EnaSrdEnabled,
+		}
+		if t.EnaSrdUDPEnabled != nil {
+			enaSrd.EnaSrdUdpSpecification = &ec2types.EnaSrdUdpSpecificationRequest{
+				EnaSrdUdpEnabled: t.EnaSrdUDPEnabled,
+			}
+		}
+		data.NetworkInterfaces[0].EnaSrdSpecification = enaSrd
+	}
+	if t.hasConnectionTracking() {
+		data.NetworkInterfaces[0].ConnectionTrackingSpecification = &ec2types.ConnectionTrackingSpecificationRequest{
+			TcpEstablishedTimeout: t.ConnectionTrackingTCPEstablishedTimeout,
+			UdpStreamTimeout:      t.ConnectionTrackingUDPStreamTimeout,
+			UdpTimeout:            t.ConnectionTrackingUDPTimeout,
+		}
 	}
 
 	// @step: add the actual block device mappings
@@ -67,11 +89,11 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 	if err != nil {
 		return fmt.Errorf("failed to build root device: %w", err)
 	}
-	ephemeralDevices, err := buildEphemeralDevices(c.Cloud, fi.ValueOf(t.InstanceType))
+	ephemeralDevices, err := t.buildEphemeralDevices(c.Cloud)
 	if err != nil {
 		return fmt.Errorf("failed to build ephemeral devices: %w", err)
 	}
-	additionalDevices, err := buildAdditionalDevices(t.BlockDeviceMappings)
+	additionalDevices, err := t.buildAdditionalDevices()
 	if err != nil {
 		return err
 	}
@@ -86,12 +108,33 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 		data.KeyName = t.SSHKey.Name
 	}
 	// @step: add the security groups
-	for _, sg := range t.SecurityGroups {
-		data.NetworkInterfaces[0].Groups = append(data.NetworkInterfaces[0].Groups, fi.ValueOf(sg.ID))
+	if fi.ValueOf(t.SecurityGroupsOnTemplate) {
+		data.NetworkInterfaces = nil
+		for _, sg := range t.SecurityGroups {
+			data.SecurityGroupIds = append(data.SecurityGroupIds, fi.ValueOf(sg.ID))
+		}
+	} else {
+		for _, sg := range t.SecurityGroups {
+			data.NetworkInterfaces[0].Groups = append(data.NetworkInterfaces[0].Groups, fi.ValueOf(sg.ID))
+		}
+		for _, ni := range t.AdditionalNetworkInterfaces {
+			data.NetworkInterfaces = append(data.NetworkInterfaces, ec2types.LaunchTemplateInstanceNetworkInterfaceSpecificationRequest{
+				DeleteOnTermination: aws.Bool(true),
+				DeviceIndex:         fi.PtrTo(fi.ValueOf(ni.DeviceIndex)),
+				Groups:              data.NetworkInterfaces[0].Groups,
+				NetworkCardIndex:    fi.PtrTo(fi.ValueOf(ni.NetworkCardIndex)),
+			})
+		}
 	}
-	// @step: add any tenancy details
-	if t.Tenancy != nil {
-		data.Placement = &ec2types.LaunchTemplatePlacementRequest{Tenancy: fi.ValueOf(t.Tenancy)}
+	// @step: add any tenancy and placement details
+	if t.Tenancy != nil || t.PlacementGroupName != nil || t.PlacementPartitionNumber != nil || t.PlacementHostResourceGroupARN != nil || t.PlacementHostID != nil {
+		data.Placement = &ec2types.LaunchTemplatePlacementRequest{
+			GroupName:            t.PlacementGroupName,
+			HostId:               t.PlacementHostID,
+			HostResourceGroupArn: t.PlacementHostResourceGroupARN,
+			PartitionNumber:      t.PlacementPartitionNumber,
+			Tenancy:              fi.ValueOf(t.Tenancy),
+		}
 	}
 	// @step: set the instance monitoring
 	data.Monitoring = &ec2types.LaunchTemplatesMonitoringRequest{Enabled: fi.PtrTo(false)}
@@ -105,28 +148,22 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 		}
 	}
 	// @step: add the tags
-	var tags []ec2types.Tag
-	if len(t.Tags) > 0 {
-		for k, v := range t.Tags {
-			tags = append(tags, ec2types.Tag{
-				Key:   aws.String(k),
-				Value: aws.String(v),
-			})
+	tags := mapToEC2Tags(t.Tags)
+	for _, resourceType := range launchTemplateTagResourceTypes {
+		resourceTags := t.tagsForResourceType(resourceType)
+		if len(resourceTags) == 0 {
+			continue
 		}
 		data.TagSpecifications = append(data.TagSpecifications, ec2types.LaunchTemplateTagSpecificationRequest{
-			ResourceType: ec2types.ResourceTypeInstance,
-			Tags:         tags,
-		})
-		data.TagSpecifications = append(data.TagSpecifications, ec2types.LaunchTemplateTagSpecificationRequest{
-			ResourceType: ec2types.ResourceTypeVolume,
-			Tags:         tags,
+			ResourceType: resourceType,
+			Tags:         mapToEC2Tags(resourceTags),
 		})
 	}
 	// @step: add the userdata
 	if t.UserData != nil {
-		d, err := fi.ResourceAsBytes(t.UserData)
+		d, err := t.renderUserData()
 		if err != nil {
-			return fmt.Errorf("error rendering LaunchTemplate UserData: %v", err)
+			return err
 		}
 		data.UserData = aws.String(base64.StdEncoding.EncodeToString(d))
 	}
@@ -143,6 +180,10 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 			MarketType:  ec2types.MarketTypeSpot,
 			SpotOptions: s,
 		}
+	} else if fi.ValueOf(t.CapacityBlock) {
+		data.InstanceMarketOptions = &ec2types.LaunchTemplateInstanceMarketOptionsRequest{
+			MarketType: ec2types.MarketTypeCapacityBlock,
+		}
 	}
 	if fi.ValueOf(t.CPUCredits) != "" {
 		data.CreditSpecification = &ec2types.CreditSpecificationRequest{
@@ -154,6 +195,7 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 		input := &ec2.CreateLaunchTemplateInput{
 			LaunchTemplateName: t.Name,
 			LaunchTemplateData: data,
+			VersionDescription: t.versionDescription(),
 			TagSpecifications: []ec2types.TagSpecification{
 				{
 					ResourceType: ec2types.ResourceTypeLaunchTemplate,
@@ -170,6 +212,7 @@ func (t *LaunchTemplate) RenderAWS(c *awsup.AWSAPITarget, a, e, changes *LaunchT
 		input := &ec2.CreateLaunchTemplateVersionInput{
 			LaunchTemplateName: t.Name,
 			LaunchTemplateData: data,
+			VersionDescription: t.versionDescription(),
 		}
 		if version, err := c.Cloud.EC2().CreateLaunchTemplateVersion(ctx, input); err != nil {
 			return fmt.Errorf("error creating LaunchTemplateVersion: %v", err)
@@ -216,16 +259,33 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 		ID:                     lt.LaunchTemplateId,
 		ImageID:                lt.LaunchTemplateData.ImageId,
 		InstanceMonitoring:     fi.PtrTo(false),
+		KernelID:               lt.LaunchTemplateData.KernelId,
 		Lifecycle:              t.Lifecycle,
 		Name:                   t.Name,
+		RamdiskID:              lt.LaunchTemplateData.RamDiskId,
 		RootVolumeOptimization: lt.LaunchTemplateData.EbsOptimized,
 	}
 	if len(lt.LaunchTemplateData.InstanceType) > 0 {
 		actual.InstanceType = fi.PtrTo(lt.LaunchTemplateData.InstanceType)
 	}
+	// The default description is not compared, so that existing launch templates are not updated only to set it
+	if t.VersionDescription != nil {
+		actual.VersionDescription = fi.PtrTo(aws.ToString(lt.VersionDescription))
+	}
+	// An unset DisableApiStop is equivalent to false
+	if t.DisableAPIStop != nil {
+		actual.DisableAPIStop = fi.PtrTo(aws.ToBool(lt.LaunchTemplateData.DisableApiStop))
+	}
 
 	// @step: check if any of the interfaces are public facing
 	for _, x := range lt.LaunchTemplateData.NetworkInterfaces {
+		if aws.ToInt32(x.DeviceIndex) != 0 || aws.ToInt32(x.NetworkCardIndex) != 0 {
+			actual.AdditionalNetworkInterfaces = append(actual.AdditionalNetworkInterfaces, &LaunchTemplateNetworkInterface{
+				DeviceIndex:      fi.PtrTo(aws.ToInt32(x.DeviceIndex)),
+				NetworkCardIndex: fi.PtrTo(aws.ToInt32(x.NetworkCardIndex)),
+			})
+			continue
+		}
 		if aws.ToBool(x.AssociatePublicIpAddress) {
 			actual.AssociatePublicIP = fi.PtrTo(true)
 		}
@@ -233,10 +293,34 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 			actual.SecurityGroups = append(actual.SecurityGroups, &SecurityGroup{ID: fi.PtrTo(id)})
 		}
 		actual.IPv6AddressCount = x.Ipv6AddressCount
+		actual.AssociateIPv6Address = fi.PtrTo(aws.ToInt32(x.Ipv6AddressCount) != 0)
+		if x.EnaSrdSpecification != nil {
+			actual.EnaSrdEnabled = x.EnaSrdSpecification.EnaSrdEnabled
+			if x.EnaSrdSpecification.EnaSrdUdpSpecification != nil {
+				actual.EnaSrdUDPEnabled = x.EnaSrdSpecification.EnaSrdUdpSpecification.EnaSrdUdpEnabled
+			}
+		}
+		if x.ConnectionTrackingSpecification != nil {
+			actual.ConnectionTrackingTCPEstablishedTimeout = x.ConnectionTrackingSpecification.TcpEstablishedTimeout
+			actual.ConnectionTrackingUDPStreamTimeout = x.ConnectionTrackingSpecification.UdpStreamTimeout
+			actual.ConnectionTrackingUDPTimeout = x.ConnectionTrackingSpecification.UdpTimeout
+		}
 	}
-	// In older Kops versions, security groups were added to LaunchTemplateData.SecurityGroupIds
-	for _, id := range lt.LaunchTemplateData.SecurityGroupIds {
-		actual.SecurityGroups = append(actual.SecurityGroups, &SecurityGroup{ID: fi.PtrTo("legacy-" + id)})
+	if len(lt.LaunchTemplateData.NetworkInterfaces) == 0 {
+		// Without a network interface no IPv6 addresses are assigned
+		actual.IPv6AddressCount = fi.PtrTo(int32(0))
+		actual.AssociateIPv6Address = fi.PtrTo(false)
+	}
+	if fi.ValueOf(t.SecurityGroupsOnTemplate) {
+		actual.SecurityGroupsOnTemplate = fi.PtrTo(len(lt.LaunchTemplateData.NetworkInterfaces) == 0)
+		for _, id := range lt.LaunchTemplateData.SecurityGroupIds {
+			actual.SecurityGroups = append(actual.SecurityGroups, &SecurityGroup{ID: fi.PtrTo(id)})
+		}
+	} else {
+		// In older Kops versions, security groups were added to LaunchTemplateData.SecurityGroupIds
+		for _, id := range lt.LaunchTemplateData.SecurityGroupIds {
+			actual.SecurityGroups = append(actual.SecurityGroups, &SecurityGroup{ID: fi.PtrTo("legacy-" + id)})
+		}
 	}
 	sort.Sort(OrderSecurityGroupsById(actual.SecurityGroups))
 
@@ -249,9 +333,15 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 	if lt.LaunchTemplateData.Monitoring != nil {
 		actual.InstanceMonitoring = lt.LaunchTemplateData.Monitoring.Enabled
 	}
-	// @step: add the tenancy
-	if lt.LaunchTemplateData.Placement != nil && len(lt.LaunchTemplateData.Placement.Tenancy) > 0 {
-		actual.Tenancy = fi.PtrTo(lt.LaunchTemplateData.Placement.Tenancy)
+	// @step: add the tenancy and placement
+	if placement := lt.LaunchTemplateData.Placement; placement != nil {
+		if len(placement.Tenancy) > 0 {
+			actual.Tenancy = fi.PtrTo(placement.Tenancy)
+		}
+		actual.PlacementGroupName = placement.GroupName
+		actual.PlacementPartitionNumber = placement.PartitionNumber
+		actual.PlacementHostResourceGroupARN = placement.HostResourceGroupArn
+		actual.PlacementHostID = placement.HostId
 	}
 	// @step: add the ssh if there is one
 	if lt.LaunchTemplateData.KeyName != nil {
@@ -272,6 +362,7 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 	} else {
 		actual.SpotPrice = aws.String("")
 	}
+	actual.CapacityBlock = fi.PtrTo(imo != nil && imo.MarketType == ec2types.MarketTypeCapacityBlock)
 
 	// @step: get the image is order to find out the root device name as using the index
 	// is not variable, under conditions they move
@@ -281,7 +372,11 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 	}
 
 	// @step: find the root volume
+	ephemeralDevices := make(map[string]string)
 	for _, b := range lt.LaunchTemplateData.BlockDeviceMappings {
+		if b.VirtualName != nil {
+			ephemeralDevices[aws.ToString(b.DeviceName)] = aws.ToString(b.VirtualName)
+		}
 		if b.Ebs == nil {
 			continue
 		}
@@ -302,6 +397,66 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 		}
 	}
 
+	// @step: the default encryption key is only known through the volumes inheriting it
+	if defaultKey := fi.ValueOf(t.DefaultVolumeKmsKey); defaultKey != "" {
+		inherited := true
+		if fi.ValueOf(t.RootVolumeEncryption) && fi.ValueOf(t.RootVolumeKmsKey) == "" && actual.RootVolumeKmsKey != nil {
+			if fi.ValueOf(actual.RootVolumeKmsKey) == defaultKey {
+				actual.RootVolumeKmsKey = t.RootVolumeKmsKey
+			} else {
+				inherited = false
+			}
+		}
+		for _, expected := range t.BlockDeviceMappings {
+			if !fi.ValueOf(expected.EbsEncrypted) || fi.ValueOf(expected.EbsKmsKey) != "" {
+				continue
+			}
+			for _, d := range actual.BlockDeviceMappings {
+				if fi.ValueOf(d.DeviceName) != fi.ValueOf(expected.DeviceName) {
+					continue
+				}
+				if fi.ValueOf(d.EbsKmsKey) == defaultKey {
+					d.EbsKmsKey = expected.EbsKmsKey
+				} else {
+					inherited = false
+				}
+			}
+		}
+		if inherited {
+			actual.DefaultVolumeKmsKey = t.DefaultVolumeKmsKey
+		}
+	}
+
+	// @step: the references to the encryption keys only exist in terraform
+	actual.DefaultVolumeKmsKeyLink = t.DefaultVolumeKmsKeyLink
+	for _, expected := range t.BlockDeviceMappings {
+		for _, d := range actual.BlockDeviceMappings {
+			if fi.ValueOf(d.DeviceName) == fi.ValueOf(expected.DeviceName) {
+				d.EbsKmsKeyLink = expected.EbsKmsKeyLink
+			}
+		}
+	}
+
+	// @step: the throughput scaling is only applied to the terraform output
+	actual.VolumeThroughputPerGiB = t.VolumeThroughputPerGiB
+
+	// @step: the overrides of the ephemeral devices are only known through the mappings they result in
+	if t.EphemeralDeviceNames != nil {
+		expected, err := t.buildEphemeralDevices(cloud)
+		if err != nil {
+			return nil, err
+		}
+		matches := len(expected) == len(ephemeralDevices)
+		for name, device := range expected {
+			if ephemeralDevices[name] != fi.ValueOf(device.VirtualName) {
+				matches = false
+			}
+		}
+		if matches {
+			actual.EphemeralDeviceNames = t.EphemeralDeviceNames
+		}
+	}
+
 	if lt.LaunchTemplateData.UserData != nil {
 		ud, err := base64.StdEncoding.DecodeString(aws.ToString(lt.LaunchTemplateData.UserData))
 		if err != nil {
@@ -311,11 +466,27 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 	}
 
 	// @step: add tags
-	if len(lt.LaunchTemplateData.TagSpecifications) > 0 {
-		ts := lt.LaunchTemplateData.TagSpecifications[0]
-		if ts.Tags != nil {
-			tags := mapEC2TagsToMap(ts.Tags)
-			actual.Tags = tags
+	for _, ts := range lt.LaunchTemplateData.TagSpecifications {
+		if ts.ResourceType == ec2types.ResourceTypeInstance && ts.Tags != nil {
+			actual.Tags = mapEC2TagsToMap(ts.Tags)
+		}
+	}
+	// @step: add any tags that differ from the instance tags as overrides
+	for _, ts := range lt.LaunchTemplateData.TagSpecifications {
+		if ts.ResourceType == ec2types.ResourceTypeInstance {
+			continue
+		}
+		overrides := make(map[string]string)
+		for k, v := range mapEC2TagsToMap(ts.Tags) {
+			if existing, found := actual.Tags[k]; !found || existing != v {
+				overrides[k] = v
+			}
+		}
+		if len(overrides) > 0 {
+			if actual.TagOverrides == nil {
+				actual.TagOverrides = make(map[ec2types.ResourceType]map[string]string)
+			}
+			actual.TagOverrides[ts.ResourceType] = overrides
 		}
 	}
 
@@ -328,6 +499,9 @@ func (t *LaunchTemplate) Find(c *fi.CloudupContext) (*LaunchTemplate, error) {
 		if len(options.HttpProtocolIpv6) > 0 {
 			actual.HTTPProtocolIPv6 = fi.PtrTo(options.HttpProtocolIpv6)
 		}
+		if len(options.InstanceMetadataTags) > 0 {
+			actual.InstanceMetadataTags = fi.PtrTo(options.InstanceMetadataTags)
+		}
 	}
 
 	// @step: to avoid spurious changes on ImageId
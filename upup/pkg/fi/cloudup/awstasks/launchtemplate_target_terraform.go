@@ -18,6 +18,8 @@ package awstasks
 
 import (
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
 	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
@@ -27,12 +29,41 @@ import (
 type terraformLaunchTemplateNetworkInterface struct {
 	// AssociatePublicIPAddress associates a public ip address with the network interface. Boolean value.
 	AssociatePublicIPAddress *bool `cty:"associate_public_ip_address"`
+	// ConnectionTrackingSpecification configures the idle timeouts of the connections tracked on the network interface.
+	ConnectionTrackingSpecification *terraformLaunchTemplateConnectionTrackingSpecification `cty:"connection_tracking_specification"`
 	// DeleteOnTermination indicates whether the network interface should be destroyed on instance termination.
 	DeleteOnTermination *bool `cty:"delete_on_termination"`
+	// DeviceIndex is the position of the network interface on its network card.
+	DeviceIndex *int32 `cty:"device_index"`
+	// NetworkCardIndex is the network card the network interface is attached to.
+	NetworkCardIndex *int32 `cty:"network_card_index"`
 	// Ipv6AddressCount is the number of IPv6 addresses to assign with the primary network interface.
 	Ipv6AddressCount *int32 `cty:"ipv6_address_count"`
 	// SecurityGroups is a list of security group ids.
 	SecurityGroups []*terraformWriter.Literal `cty:"security_groups"`
+	// EnaSrdSpecification configures ENA Express for the network interface.
+	EnaSrdSpecification *terraformLaunchTemplateEnaSrdSpecification `cty:"ena_srd_specification"`
+}
+
+type terraformLaunchTemplateConnectionTrackingSpecification struct {
+	// TCPEstablishedTimeout is the idle timeout in seconds of established TCP connections.
+	TCPEstablishedTimeout *int32 `cty:"tcp_established_timeout"`
+	// UDPStreamTimeout is the idle timeout in seconds of UDP flows classified as streams.
+	UDPStreamTimeout *int32 `cty:"udp_stream_timeout"`
+	// UDPTimeout is the idle timeout in seconds of other UDP flows.
+	UDPTimeout *int32 `cty:"udp_timeout"`
+}
+
+type terraformLaunchTemplateEnaSrdSpecification struct {
+	// EnaSrdEnabled indicates whether ENA Express is enabled for the network interface.
+	EnaSrdEnabled *bool `cty:"ena_srd_enabled"`
+	// EnaSrdUDPSpecification configures ENA Express for UDP traffic.
+	EnaSrdUDPSpecification *terraformLaunchTemplateEnaSrdUDPSpecification `cty:"ena_srd_udp_specification"`
+}
+
+type terraformLaunchTemplateEnaSrdUDPSpecification struct {
+	// EnaSrdUDPEnabled indicates whether ENA Express is enabled for UDP traffic.
+	EnaSrdUDPEnabled *bool `cty:"ena_srd_udp_enabled"`
 }
 
 type terraformLaunchTemplateMonitoring struct {
@@ -49,6 +80,10 @@ type terraformLaunchTemplatePlacement struct {
 	GroupName *string `cty:"group_name"`
 	// HostID is the ID of the Dedicated Host for the instance.
 	HostID *string `cty:"host_id"`
+	// HostResourceGroupARN is the ARN of the host resource group in which to launch the instance.
+	HostResourceGroupARN *string `cty:"host_resource_group_arn"`
+	// PartitionNumber is the number of the partition the instance should launch in.
+	PartitionNumber *int32 `cty:"partition_number"`
 	// SpreadDomain are reserved for future use.
 	SpreadDomain *string `cty:"spread_domain"`
 	// Tenancy ist he tenancy of the instance. Can be default, dedicated, or host.
@@ -94,7 +129,7 @@ type terraformLaunchTemplateBlockDeviceEBS struct {
 	// Encrypted indicates the device should be encrypted
 	Encrypted *bool `cty:"encrypted"`
 	// KmsKeyID is the encryption key identifier for the volume
-	KmsKeyID *string `cty:"kms_key_id"`
+	KmsKeyID *terraformWriter.Literal `cty:"kms_key_id"`
 }
 
 type terraformLaunchTemplateBlockDevice struct {
@@ -126,6 +161,8 @@ type terraformLaunchTemplateInstanceMetadata struct {
 	HTTPTokens *ec2types.LaunchTemplateHttpTokensState `cty:"http_tokens"`
 	// HTTPProtocolIPv6 enables the IPv6 instance metadata endpoint
 	HTTPProtocolIPv6 *ec2types.LaunchTemplateInstanceMetadataProtocolIpv6 `cty:"http_protocol_ipv6"`
+	// InstanceMetadataTags exposes the instance tags through the instance metadata endpoint
+	InstanceMetadataTags *ec2types.LaunchTemplateInstanceMetadataTagsState `cty:"instance_metadata_tags"`
 }
 
 type terraformLaunchTemplate struct {
@@ -138,6 +175,10 @@ type terraformLaunchTemplate struct {
 	BlockDeviceMappings []*terraformLaunchTemplateBlockDevice `cty:"block_device_mappings"`
 	// CreditSpecification is the credit option for CPU Usage on some instance types
 	CreditSpecification *terraformLaunchTemplateCreditSpecification `cty:"credit_specification"`
+	// Description is the description of the launch template versions
+	Description *string `cty:"description"`
+	// DisableAPIStop protects the instances from being stopped through the EC2 API
+	DisableAPIStop *bool `cty:"disable_api_stop"`
 	// EBSOptimized indicates if the root device is ebs optimized
 	EBSOptimized *bool `cty:"ebs_optimized"`
 	// IAMInstanceProfile is the IAM profile to assign to the nodes
@@ -146,9 +187,11 @@ type terraformLaunchTemplate struct {
 	ImageID *string `cty:"image_id"`
 	// InstanceType is the type of instance
 	InstanceType *ec2types.InstanceType `cty:"instance_type"`
+	// KernelID is the kernel to boot the AMI with
+	KernelID *string `cty:"kernel_id"`
 	// KeyName is the ssh key to use
 	KeyName *terraformWriter.Literal `cty:"key_name"`
-	// MarketOptions are the spot pricing options
+	// MarketOptions are the spot pricing or capacity block options
 	MarketOptions []*terraformLaunchTemplateMarketOptions `cty:"instance_market_options"`
 	// MetadataOptions are the instance metadata options.
 	MetadataOptions *terraformLaunchTemplateInstanceMetadata `cty:"metadata_options"`
@@ -158,12 +201,84 @@ type terraformLaunchTemplate struct {
 	NetworkInterfaces []*terraformLaunchTemplateNetworkInterface `cty:"network_interfaces"`
 	// Placement are the tenancy options
 	Placement []*terraformLaunchTemplatePlacement `cty:"placement"`
+	// RamDiskID is the RAM disk to boot the AMI with
+	RamDiskID *string `cty:"ram_disk_id"`
 	// Tags is a map of tags applied to the launch template itself
 	Tags map[string]string `cty:"tags"`
 	// TagSpecifications are the tags to apply to a resource when it is created.
 	TagSpecifications []*terraformLaunchTemplateTagSpecification `cty:"tag_specifications"`
 	// UserData is the user data for the instances
 	UserData *terraformWriter.Literal `cty:"user_data"`
+	// VPCSecurityGroupIDs is a list of security group ids, when they are not attached to the network interface
+	VPCSecurityGroupIDs []*terraformWriter.Literal `cty:"vpc_security_group_ids"`
+}
+
+// terraformKmsKey returns the encryption key of the volume, preferring a reference to a key managed in terraform
+func (b *BlockDeviceMapping) terraformKmsKey() *terraformWriter.Literal {
+	if b.EbsKmsKeyLink != nil {
+		return b.EbsKmsKeyLink
+	}
+	if b.EbsKmsKey != nil {
+		return terraformWriter.LiteralFromStringValue(fi.ValueOf(b.EbsKmsKey))
+	}
+	return nil
+}
+
+// terraformBlockDeviceEBS returns the terraform EBS settings of a block device of the launch template.
+// gp2 volumes are rendered with a warning recommending gp3, and are converted to gp3 volumes with
+// the same baseline performance when the TerraformGp2ToGp3 feature flag is enabled.
+// The throughput of gp3 volumes without one of their own is scaled with their size if VolumeThroughputPerGiB is set.
+func (t *LaunchTemplate) terraformBlockDeviceEBS(deviceName string, x *BlockDeviceMapping) *terraformLaunchTemplateBlockDeviceEBS {
+	ebs := &terraformLaunchTemplateBlockDeviceEBS{
+		DeleteOnTermination: fi.PtrTo(true),
+		Encrypted:           x.EbsEncrypted,
+		KmsKeyID:            x.terraformKmsKey(),
+		IOPS:                x.EbsVolumeIops,
+		Throughput:          x.EbsVolumeThroughput,
+		VolumeSize:          x.EbsVolumeSize,
+		VolumeType:          fi.PtrTo(string(x.EbsVolumeType)),
+	}
+	if x.EbsVolumeType == ec2types.VolumeTypeGp2 {
+		if !featureflag.TerraformGp2ToGp3.Enabled() {
+			klog.Warningf("launch template %q uses a gp2 volume for %s; gp3 volumes are cheaper and perform at least as well, consider switching to gp3", fi.ValueOf(t.Name), deviceName)
+			return ebs
+		}
+		klog.Infof("converting the gp2 volume for %s of launch template %q to gp3", deviceName, fi.ValueOf(t.Name))
+		ebs.VolumeType = fi.PtrTo(string(ec2types.VolumeTypeGp3))
+		ebs.IOPS = fi.PtrTo(gp2BaselineIops(x.EbsVolumeSize))
+		ebs.Throughput = fi.PtrTo(gp3BaselineThroughput)
+	}
+	if fi.ValueOf(ebs.VolumeType) == string(ec2types.VolumeTypeGp3) && x.EbsVolumeThroughput == nil && t.VolumeThroughputPerGiB != nil {
+		ebs.Throughput = fi.PtrTo(gp3ScaledThroughput(fi.ValueOf(x.EbsVolumeSize), fi.ValueOf(t.VolumeThroughputPerGiB), fi.ValueOf(ebs.IOPS)))
+	}
+	return ebs
+}
+
+const (
+	// gp3BaselineThroughput is the throughput in MiBps included in the price of gp3 volumes
+	gp3BaselineThroughput int32 = 125
+	// gp3MaxThroughput is the maximum throughput in MiBps of gp3 volumes
+	gp3MaxThroughput int32 = 2000
+	// gp3MaxThroughputPerIops is the maximum throughput in MiBps of gp3 volumes for each provisioned IOPS
+	gp3MaxThroughputPerIops = 0.25
+	// gp2MaxIops is the maximum baseline IOPS of gp2 volumes
+	gp2MaxIops int32 = 16000
+)
+
+// gp3ScaledThroughput returns the throughput in MiBps of a gp3 volume of the given size in GiB, at perGiB MiBps per GiB.
+// It is kept between the baseline and the maximum throughput of gp3 volumes, which also depends on the provisioned IOPS,
+// defaulting to the 3000 IOPS included in the price of gp3 volumes.
+func gp3ScaledThroughput(size int32, perGiB float64, iops int32) int32 {
+	iops = max(iops, awsup.VolumeIopsLimits[ec2types.VolumeTypeGp3].Min)
+	limit := min(gp3MaxThroughput, int32(float64(iops)*gp3MaxThroughputPerIops))
+	throughput := int32(float64(size) * perGiB)
+	return max(min(throughput, limit), gp3BaselineThroughput)
+}
+
+// gp2BaselineIops returns the IOPS for a gp3 volume matching the baseline of a gp2 volume of the given size in GiB,
+// which is 3 IOPS per GiB up to 16000 IOPS, but no less than the 3000 IOPS included in the price of gp3 volumes.
+func gp2BaselineIops(size *int32) int32 {
+	return max(min(3*fi.ValueOf(size), gp2MaxIops), awsup.VolumeIopsLimits[ec2types.VolumeTypeGp3].Min)
 }
 
 // TerraformLink returns the terraform reference
@@ -193,26 +308,34 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 
 	tf := terraformLaunchTemplate{
 		Name:         e.Name,
+		Description:  e.versionDescription(),
 		EBSOptimized: e.RootVolumeOptimization,
 		ImageID:      image,
 		InstanceType: e.InstanceType,
+		KernelID:     e.KernelID,
 		Lifecycle:    &terraform.Lifecycle{CreateBeforeDestroy: fi.PtrTo(true)},
 		MetadataOptions: &terraformLaunchTemplateInstanceMetadata{
 			// See issue https://github.com/hashicorp/terraform-provider-aws/issues/12564.
 			HTTPEndpoint:            fi.PtrTo("enabled"),
 			HTTPTokens:              e.HTTPTokens,
 			HTTPPutResponseHopLimit: e.HTTPPutResponseHopLimit,
-			HTTPProtocolIPv6:        e.HTTPProtocolIPv6,
+			InstanceMetadataTags:    e.InstanceMetadataTags,
 		},
 		NetworkInterfaces: []*terraformLaunchTemplateNetworkInterface{
 			{
 				AssociatePublicIPAddress: e.AssociatePublicIP,
 				DeleteOnTermination:      fi.PtrTo(true),
-				Ipv6AddressCount:         e.IPv6AddressCount,
+				Ipv6AddressCount:         e.ipv6AddressCount(),
 			},
 		},
+		RamDiskID: e.RamdiskID,
 	}
 
+	// The IPv6 metadata endpoint is only configured on instances with IPv6 addresses,
+	// as setting it on IPv4-only instances causes needless diffs with some provider versions
+	if fi.ValueOf(e.ipv6AddressCount()) > 0 {
+		tf.MetadataOptions.HTTPProtocolIPv6 = e.HTTPProtocolIPv6
+	}
 	if fi.ValueOf(e.SpotPrice) != "" {
 		marketSpotOptions := terraformLaunchTemplateMarketOptionsSpotOptions{
 			BlockDurationMinutes:         e.SpotDurationInMinutes,
@@ -225,20 +348,72 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 				SpotOptions: []*terraformLaunchTemplateMarketOptionsSpotOptions{&marketSpotOptions},
 			},
 		}
+	} else if fi.ValueOf(e.CapacityBlock) {
+		tf.MarketOptions = []*terraformLaunchTemplateMarketOptions{
+			{
+				MarketType: fi.PtrTo(string(ec2types.MarketTypeCapacityBlock)),
+			},
+		}
+	}
+	if fi.ValueOf(e.DisableAPIStop) {
+		tf.DisableAPIStop = e.DisableAPIStop
 	}
 	if fi.ValueOf(e.CPUCredits) != "" {
 		tf.CreditSpecification = &terraformLaunchTemplateCreditSpecification{
 			CPUCredits: e.CPUCredits,
 		}
 	}
-	for _, x := range e.SecurityGroups {
-		tf.NetworkInterfaces[0].SecurityGroups = append(tf.NetworkInterfaces[0].SecurityGroups, x.TerraformLink())
+	if e.EnaSrdEnabled != nil || e.EnaSrdUDPEnabled != nil {
+		enaSrd := &terraformLaunchTemplateEnaSrdSpecification{
+			EnaSrdEnabled: e.EnaSrdEnabled,
+		}
+		if e.EnaSrdUDPEnabled != nil {
+			enaSrd.EnaSrdUDPSpecification = &terraformLaunchTemplateEnaSrdUDPSpecification{
+				EnaSrdUDPEnabled: e.EnaSrdUDPEnabled,
+			}
+		}
+		tf.NetworkInterfaces[0].EnaSrdSpecification = enaSrd
+	}
+	if e.hasConnectionTracking() {
+		tf.NetworkInterfaces[0].ConnectionTrackingSpecification = &terraformLaunchTemplateConnectionTrackingSpecification{
+			TCPEstablishedTimeout: e.ConnectionTrackingTCPEstablishedTimeout,
+			UDPStreamTimeout:      e.ConnectionTrackingUDPStreamTimeout,
+			UDPTimeout:            e.ConnectionTrackingUDPTimeout,
+		}
+	}
+	if fi.ValueOf(e.SecurityGroupsOnTemplate) {
+		tf.NetworkInterfaces = nil
+		for _, x := range e.SecurityGroups {
+			tf.VPCSecurityGroupIDs = append(tf.VPCSecurityGroupIDs, x.TerraformLink())
+		}
+		terraformWriter.SortLiterals(tf.VPCSecurityGroupIDs)
+	} else {
+		for _, x := range e.SecurityGroups {
+			tf.NetworkInterfaces[0].SecurityGroups = append(tf.NetworkInterfaces[0].SecurityGroups, x.TerraformLink())
+		}
+		terraformWriter.SortLiterals(tf.NetworkInterfaces[0].SecurityGroups)
+		for _, ni := range e.AdditionalNetworkInterfaces {
+			tf.NetworkInterfaces = append(tf.NetworkInterfaces, &terraformLaunchTemplateNetworkInterface{
+				DeleteOnTermination: fi.PtrTo(true),
+				DeviceIndex:         fi.PtrTo(fi.ValueOf(ni.DeviceIndex)),
+				NetworkCardIndex:    fi.PtrTo(fi.ValueOf(ni.NetworkCardIndex)),
+				SecurityGroups:      tf.NetworkInterfaces[0].SecurityGroups,
+			})
+		}
 	}
 	if e.SSHKey != nil {
 		tf.KeyName = e.SSHKey.TerraformLink()
 	}
-	if e.Tenancy != nil {
-		tf.Placement = []*terraformLaunchTemplatePlacement{{Tenancy: e.Tenancy}}
+	if e.Tenancy != nil || e.PlacementGroupName != nil || e.PlacementPartitionNumber != nil || e.PlacementHostResourceGroupARN != nil || e.PlacementHostID != nil {
+		tf.Placement = []*terraformLaunchTemplatePlacement{
+			{
+				GroupName:            e.PlacementGroupName,
+				HostID:               e.PlacementHostID,
+				HostResourceGroupARN: e.PlacementHostResourceGroupARN,
+				PartitionNumber:      e.PlacementPartitionNumber,
+				Tenancy:              e.Tenancy,
+			},
+		}
 	}
 	if e.InstanceMonitoring != nil {
 		tf.Monitoring = []*terraformLaunchTemplateMonitoring{
@@ -251,7 +426,7 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		}
 	}
 	if e.UserData != nil {
-		d, err := fi.ResourceAsBytes(e.UserData)
+		d, err := e.renderUserData()
 		if err != nil {
 			return err
 		}
@@ -269,41 +444,21 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 	for n, x := range devices {
 		tf.BlockDeviceMappings = append(tf.BlockDeviceMappings, &terraformLaunchTemplateBlockDevice{
 			DeviceName: fi.PtrTo(n),
-			EBS: []*terraformLaunchTemplateBlockDeviceEBS{
-				{
-					DeleteOnTermination: fi.PtrTo(true),
-					Encrypted:           x.EbsEncrypted,
-					KmsKeyID:            x.EbsKmsKey,
-					IOPS:                x.EbsVolumeIops,
-					Throughput:          x.EbsVolumeThroughput,
-					VolumeSize:          x.EbsVolumeSize,
-					VolumeType:          fi.PtrTo(string(x.EbsVolumeType)),
-				},
-			},
+			EBS:        []*terraformLaunchTemplateBlockDeviceEBS{e.terraformBlockDeviceEBS(n, x)},
 		})
 	}
-	additionals, err := buildAdditionalDevices(e.BlockDeviceMappings)
+	additionals, err := e.buildAdditionalDevices()
 	if err != nil {
 		return err
 	}
 	for n, x := range additionals {
 		tf.BlockDeviceMappings = append(tf.BlockDeviceMappings, &terraformLaunchTemplateBlockDevice{
 			DeviceName: fi.PtrTo(n),
-			EBS: []*terraformLaunchTemplateBlockDeviceEBS{
-				{
-					DeleteOnTermination: fi.PtrTo(true),
-					Encrypted:           x.EbsEncrypted,
-					IOPS:                x.EbsVolumeIops,
-					Throughput:          x.EbsVolumeThroughput,
-					KmsKeyID:            x.EbsKmsKey,
-					VolumeSize:          x.EbsVolumeSize,
-					VolumeType:          fi.PtrTo(string(x.EbsVolumeType)),
-				},
-			},
+			EBS:        []*terraformLaunchTemplateBlockDeviceEBS{e.terraformBlockDeviceEBS(n, x)},
 		})
 	}
 
-	devices, err = buildEphemeralDevices(cloud, fi.ValueOf(e.InstanceType))
+	devices, err = e.buildEphemeralDevices(cloud)
 	if err != nil {
 		return err
 	}
@@ -314,15 +469,17 @@ func (t *LaunchTemplate) RenderTerraform(target *terraform.TerraformTarget, a, e
 		})
 	}
 
-	if e.Tags != nil {
-		tf.TagSpecifications = append(tf.TagSpecifications, &terraformLaunchTemplateTagSpecification{
-			ResourceType: fi.PtrTo("instance"),
-			Tags:         e.Tags,
-		})
+	for _, resourceType := range launchTemplateTagResourceTypes {
+		tags := e.tagsForResourceType(resourceType)
+		if tags == nil {
+			continue
+		}
 		tf.TagSpecifications = append(tf.TagSpecifications, &terraformLaunchTemplateTagSpecification{
-			ResourceType: fi.PtrTo("volume"),
-			Tags:         e.Tags,
+			ResourceType: fi.PtrTo(string(resourceType)),
+			Tags:         tags,
 		})
+	}
+	if e.Tags != nil {
 		tf.Tags = e.Tags
 	}
 
@@ -17,11 +17,19 @@ limitations under the License.
 package awstasks
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
+	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awsup"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
 )
 
 func TestLaunchTemplateTerraformRender(t *testing.T) {
@@ -95,6 +103,55 @@ resource "aws_launch_template" "test" {
   }
 }
 
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name: fi.PtrTo("test"),
+				IAMInstanceProfile: &IAMInstanceProfile{
+					Name: fi.PtrTo("nodes"),
+				},
+				ID:            fi.PtrTo("test-11"),
+				InstanceType:  fi.PtrTo(ec2types.InstanceTypeP548xlarge),
+				CapacityBlock: fi.PtrTo(true),
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.PtrTo("nodes-1"), ID: fi.PtrTo("1111")},
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  iam_instance_profile {
+    name = aws_iam_instance_profile.nodes.id
+  }
+  instance_market_options {
+    market_type = "capacity-block"
+  }
+  instance_type = "p5.48xlarge"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  network_interfaces {
+    delete_on_termination = true
+    security_groups       = [aws_security_group.nodes-1.id]
+  }
+}
+
 terraform {
   required_version = ">= 0.15.0"
   required_providers {
@@ -135,9 +192,15 @@ terraform {
 					{Name: fi.PtrTo("nodes-1"), ID: fi.PtrTo("1111")},
 					{Name: fi.PtrTo("nodes-2"), ID: fi.PtrTo("2222")},
 				},
-				Tenancy:                 fi.PtrTo(ec2types.TenancyDedicated),
-				HTTPTokens:              fi.PtrTo(ec2types.LaunchTemplateHttpTokensStateRequired),
-				HTTPPutResponseHopLimit: fi.PtrTo(int32(5)),
+				Tenancy:                  fi.PtrTo(ec2types.TenancyDedicated),
+				PlacementGroupName:       fi.PtrTo("partitions"),
+				PlacementPartitionNumber: fi.PtrTo(int32(2)),
+				HTTPTokens:               fi.PtrTo(ec2types.LaunchTemplateHttpTokensStateRequired),
+				HTTPPutResponseHopLimit:  fi.PtrTo(int32(5)),
+				InstanceMetadataTags:     fi.PtrTo(ec2types.LaunchTemplateInstanceMetadataTagsStateEnabled),
+				KernelID:                 fi.PtrTo("aki-12345678"),
+				RamdiskID:                fi.PtrTo("ari-12345678"),
+				DisableAPIStop:           fi.PtrTo(true),
 			},
 			Expected: `provider "aws" {
   region = "eu-west-2"
@@ -153,11 +216,13 @@ resource "aws_launch_template" "test" {
       volume_type           = "gp2"
     }
   }
-  ebs_optimized = true
+  disable_api_stop = true
+  ebs_optimized    = true
   iam_instance_profile {
     name = aws_iam_instance_profile.nodes.id
   }
   instance_type = "t2.medium"
+  kernel_id     = "aki-12345678"
   key_name      = "mykey"
   lifecycle {
     create_before_destroy = true
@@ -166,6 +231,7 @@ resource "aws_launch_template" "test" {
     http_endpoint               = "enabled"
     http_put_response_hop_limit = 5
     http_tokens                 = "required"
+    instance_metadata_tags      = "enabled"
   }
   monitoring {
     enabled = true
@@ -177,7 +243,259 @@ resource "aws_launch_template" "test" {
     security_groups             = [aws_security_group.nodes-1.id, aws_security_group.nodes-2.id]
   }
   placement {
-    tenancy = "dedicated"
+    group_name       = "partitions"
+    partition_number = 2
+    tenancy          = "dedicated"
+  }
+  ram_disk_id = "ari-12345678"
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:         fi.PtrTo("test"),
+				ID:           fi.PtrTo("test-11"),
+				InstanceType: fi.PtrTo(ec2types.InstanceTypeT2Medium),
+				Tags: map[string]string{
+					"Name":        "test",
+					"cost-center": "kubernetes",
+				},
+				TagOverrides: map[ec2types.ResourceType]map[string]string{
+					ec2types.ResourceTypeNetworkInterface: {
+						"cost-center": "networking",
+					},
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  instance_type = "t2.medium"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  network_interfaces {
+    delete_on_termination = true
+  }
+  tag_specifications {
+    resource_type = "instance"
+    tags = {
+      "Name"        = "test"
+      "cost-center" = "kubernetes"
+    }
+  }
+  tag_specifications {
+    resource_type = "volume"
+    tags = {
+      "Name"        = "test"
+      "cost-center" = "kubernetes"
+    }
+  }
+  tag_specifications {
+    resource_type = "network-interface"
+    tags = {
+      "Name"        = "test"
+      "cost-center" = "networking"
+    }
+  }
+  tags = {
+    "Name"        = "test"
+    "cost-center" = "kubernetes"
+  }
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:                 fi.PtrTo("test"),
+				ID:                   fi.PtrTo("test-11"),
+				InstanceType:         fi.PtrTo(ec2types.InstanceTypeT2Medium),
+				AssociateIPv6Address: fi.PtrTo(true),
+				EnaSrdEnabled:        fi.PtrTo(true),
+				EnaSrdUDPEnabled:     fi.PtrTo(true),
+				DisableAPIStop:       fi.PtrTo(false),
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  instance_type = "t2.medium"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  network_interfaces {
+    delete_on_termination = true
+    ena_srd_specification {
+      ena_srd_enabled = true
+      ena_srd_udp_specification {
+        ena_srd_udp_enabled = true
+      }
+    }
+    ipv6_address_count = 1
+  }
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:         fi.PtrTo("test"),
+				ID:           fi.PtrTo("test-11"),
+				InstanceType: fi.PtrTo(ec2types.InstanceTypeT2Medium),
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.PtrTo("nodes-1"), ID: fi.PtrTo("1111")},
+					{Name: fi.PtrTo("nodes-2"), ID: fi.PtrTo("2222")},
+				},
+				SecurityGroupsOnTemplate: fi.PtrTo(true),
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  instance_type = "t2.medium"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  vpc_security_group_ids = [aws_security_group.nodes-1.id, aws_security_group.nodes-2.id]
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:         fi.PtrTo("test"),
+				ID:           fi.PtrTo("test-11"),
+				InstanceType: fi.PtrTo(ec2types.InstanceTypeP548xlarge),
+				SecurityGroups: []*SecurityGroup{
+					{Name: fi.PtrTo("nodes-1"), ID: fi.PtrTo("1111")},
+				},
+				AdditionalNetworkInterfaces: []*LaunchTemplateNetworkInterface{
+					{NetworkCardIndex: fi.PtrTo(int32(1)), DeviceIndex: fi.PtrTo(int32(1))},
+					{NetworkCardIndex: fi.PtrTo(int32(2)), DeviceIndex: fi.PtrTo(int32(1))},
+				},
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  instance_type = "p5.48xlarge"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  network_interfaces {
+    delete_on_termination = true
+    security_groups       = [aws_security_group.nodes-1.id]
+  }
+  network_interfaces {
+    delete_on_termination = true
+    device_index          = 1
+    network_card_index    = 1
+    security_groups       = [aws_security_group.nodes-1.id]
+  }
+  network_interfaces {
+    delete_on_termination = true
+    device_index          = 1
+    network_card_index    = 2
+    security_groups       = [aws_security_group.nodes-1.id]
+  }
+}
+
+terraform {
+  required_version = ">= 0.15.0"
+  required_providers {
+    aws = {
+      "source"  = "hashicorp/aws"
+      "version" = ">= 5.0.0"
+    }
+  }
+}
+`,
+		},
+		{
+			Resource: &LaunchTemplate{
+				Name:                                    fi.PtrTo("test"),
+				ID:                                      fi.PtrTo("test-11"),
+				InstanceType:                            fi.PtrTo(ec2types.InstanceTypeT2Medium),
+				ConnectionTrackingTCPEstablishedTimeout: fi.PtrTo(int32(3600)),
+				ConnectionTrackingUDPTimeout:            fi.PtrTo(int32(60)),
+			},
+			Expected: `provider "aws" {
+  region = "eu-west-2"
+}
+
+resource "aws_launch_template" "test" {
+  instance_type = "t2.medium"
+  lifecycle {
+    create_before_destroy = true
+  }
+  metadata_options {
+    http_endpoint = "enabled"
+  }
+  name = "test"
+  network_interfaces {
+    connection_tracking_specification {
+      tcp_established_timeout = 3600
+      udp_timeout             = 60
+    }
+    delete_on_termination = true
   }
 }
 
@@ -195,3 +513,585 @@ terraform {
 	}
 	doRenderTests(t, "RenderTerraform", cases)
 }
+
+func TestLaunchTemplateCheckChangesSecurityGroupsOnTemplate(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:                     fi.PtrTo("test"),
+		ImageID:                  fi.PtrTo("ami-12345678"),
+		SecurityGroupsOnTemplate: fi.PtrTo(true),
+		IPv6AddressCount:         fi.PtrTo(int32(0)),
+	}
+	if err := lt.CheckChanges(nil, lt, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	lt.AssociatePublicIP = fi.PtrTo(true)
+	err := lt.CheckChanges(nil, lt, nil)
+	if err == nil || !strings.Contains(err.Error(), "SecurityGroupsOnTemplate") {
+		t.Errorf("expected error mentioning SecurityGroupsOnTemplate, got %v", err)
+	}
+}
+
+func TestLaunchTemplateCheckChangesHostTenancy(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:    fi.PtrTo("test"),
+		ImageID: fi.PtrTo("ami-12345678"),
+		Tenancy: fi.PtrTo(ec2types.TenancyHost),
+	}
+	err := lt.CheckChanges(nil, lt, nil)
+	if err == nil || !strings.Contains(err.Error(), "requires either PlacementHostID or PlacementHostResourceGroupARN") {
+		t.Errorf("expected error requiring a host or host resource group, got %v", err)
+	}
+
+	lt.PlacementHostID = fi.PtrTo("h-0123456789abcdef0")
+	if err := lt.CheckChanges(nil, lt, nil); err != nil {
+		t.Errorf("unexpected error with host ID: %v", err)
+	}
+
+	lt.PlacementHostID = nil
+	lt.PlacementHostResourceGroupARN = fi.PtrTo("arn:aws:resource-groups:us-east-1:123456789012:group/hosts")
+	if err := lt.CheckChanges(nil, lt, nil); err != nil {
+		t.Errorf("unexpected error with host resource group: %v", err)
+	}
+
+	lt.PlacementHostID = fi.PtrTo("h-0123456789abcdef0")
+	if err := lt.CheckChanges(nil, lt, nil); err == nil {
+		t.Errorf("expected error when both host ID and host resource group are set")
+	}
+
+	lt.PlacementHostID = nil
+	lt.Tenancy = fi.PtrTo(ec2types.TenancyDedicated)
+	if err := lt.CheckChanges(nil, lt, nil); err == nil {
+		t.Errorf("expected error when host resource group is set without host tenancy")
+	}
+}
+
+func TestLaunchTemplateCheckChangesPlacementPartitionNumber(t *testing.T) {
+	grid := []struct {
+		groupName     *string
+		partition     int32
+		expectedError string
+	}{
+		{groupName: fi.PtrTo("partitions"), partition: 1},
+		{groupName: fi.PtrTo("partitions"), partition: 7},
+		{groupName: fi.PtrTo("partitions"), partition: 0, expectedError: "must be between 1 and 7, got 0"},
+		{groupName: fi.PtrTo("partitions"), partition: 8, expectedError: "must be between 1 and 7, got 8"},
+		{partition: 1, expectedError: "can only be set with a partition placement group"},
+	}
+	for _, g := range grid {
+		lt := &LaunchTemplate{
+			Name:                     fi.PtrTo("test"),
+			ImageID:                  fi.PtrTo("ami-12345678"),
+			PlacementGroupName:       g.groupName,
+			PlacementPartitionNumber: fi.PtrTo(g.partition),
+		}
+		err := lt.CheckChanges(nil, lt, nil)
+		if g.expectedError == "" {
+			if err != nil {
+				t.Errorf("partition %d: unexpected error: %v", g.partition, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), g.expectedError) {
+			t.Errorf("partition %d: expected error containing %q, got %v", g.partition, g.expectedError, err)
+		}
+	}
+}
+
+func TestLaunchTemplateCheckChangesAdditionalNetworkInterfaces(t *testing.T) {
+	grid := []struct {
+		interfaces    []*LaunchTemplateNetworkInterface
+		expectedError string
+	}{
+		{
+			interfaces: []*LaunchTemplateNetworkInterface{
+				{NetworkCardIndex: fi.PtrTo(int32(1))},
+				{NetworkCardIndex: fi.PtrTo(int32(1)), DeviceIndex: fi.PtrTo(int32(1))},
+				{DeviceIndex: fi.PtrTo(int32(1))},
+			},
+		},
+		{
+			interfaces: []*LaunchTemplateNetworkInterface{
+				{NetworkCardIndex: fi.PtrTo(int32(-1))},
+			},
+			expectedError: "must not be negative",
+		},
+		{
+			interfaces: []*LaunchTemplateNetworkInterface{
+				{},
+			},
+			expectedError: "network card 0 has more than one network interface with device index 0",
+		},
+		{
+			interfaces: []*LaunchTemplateNetworkInterface{
+				{NetworkCardIndex: fi.PtrTo(int32(1))},
+				{NetworkCardIndex: fi.PtrTo(int32(1)), DeviceIndex: fi.PtrTo(int32(0))},
+			},
+			expectedError: "network card 1 has more than one network interface with device index 0",
+		},
+	}
+	for _, g := range grid {
+		lt := &LaunchTemplate{
+			Name:                        fi.PtrTo("test"),
+			ImageID:                     fi.PtrTo("ami-12345678"),
+			AdditionalNetworkInterfaces: g.interfaces,
+		}
+		err := lt.CheckChanges(nil, lt, nil)
+		if g.expectedError == "" {
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), g.expectedError) {
+			t.Errorf("expected error containing %q, got %v", g.expectedError, err)
+		}
+	}
+}
+
+func TestLaunchTemplateCheckChangesConnectionTracking(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:                               fi.PtrTo("test"),
+		ImageID:                            fi.PtrTo("ami-12345678"),
+		ConnectionTrackingUDPStreamTimeout: fi.PtrTo(int32(120)),
+	}
+	if err := lt.CheckChanges(nil, lt, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	lt.ConnectionTrackingUDPTimeout = fi.PtrTo(int32(90))
+	err := lt.CheckChanges(nil, lt, nil)
+	if err == nil || !strings.Contains(err.Error(), "ConnectionTrackingUDPTimeout must be between 30 and 60 seconds") {
+		t.Errorf("expected error about ConnectionTrackingUDPTimeout, got %v", err)
+	}
+}
+
+func TestLaunchTemplateCheckChangesCapacityBlock(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:          fi.PtrTo("test"),
+		ImageID:       fi.PtrTo("ami-12345678"),
+		CapacityBlock: fi.PtrTo(true),
+	}
+	if err := lt.CheckChanges(nil, lt, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	lt.SpotPrice = fi.PtrTo("0.1")
+	err := lt.CheckChanges(nil, lt, nil)
+	if err == nil || !strings.Contains(err.Error(), "CapacityBlock cannot be combined with SpotPrice") {
+		t.Errorf("expected error about CapacityBlock and SpotPrice, got %v", err)
+	}
+}
+
+func TestLaunchTemplateCheckChangesVolumeIops(t *testing.T) {
+	grid := []struct {
+		volumeType ec2types.VolumeType
+		iops       int32
+		size       int32
+		err        string
+	}{
+		{volumeType: ec2types.VolumeTypeGp3, iops: 3000, size: 8},
+		{volumeType: ec2types.VolumeTypeGp3, iops: 16000, size: 32},
+		{volumeType: ec2types.VolumeTypeGp3, iops: 80000, size: 160},
+		{volumeType: ec2types.VolumeTypeGp3, iops: 2000, size: 100, err: "must be between 3000 and 80000, got 2000"},
+		{volumeType: ec2types.VolumeTypeGp3, iops: 100000, size: 1000, err: "must be between 3000 and 80000, got 100000"},
+		{volumeType: ec2types.VolumeTypeGp3, iops: 16000, size: 31, err: "must be at most 500 per GiB, got 16000 for 31 GiB"},
+		{volumeType: ec2types.VolumeTypeIo1, iops: 100, size: 4},
+		{volumeType: ec2types.VolumeTypeIo1, iops: 64000, size: 1280},
+		{volumeType: ec2types.VolumeTypeIo1, iops: 50, size: 100, err: "must be between 100 and 64000, got 50"},
+		{volumeType: ec2types.VolumeTypeIo1, iops: 100000, size: 4000, err: "must be between 100 and 64000, got 100000"},
+		{volumeType: ec2types.VolumeTypeIo1, iops: 6000, size: 100, err: "must be at most 50 per GiB, got 6000 for 100 GiB"},
+		{volumeType: ec2types.VolumeTypeIo2, iops: 100, size: 4},
+		{volumeType: ec2types.VolumeTypeIo2, iops: 256000, size: 256},
+		{volumeType: ec2types.VolumeTypeIo2, iops: 50, size: 100, err: "must be between 100 and 256000, got 50"},
+		{volumeType: ec2types.VolumeTypeIo2, iops: 300000, size: 1000, err: "must be between 100 and 256000, got 300000"},
+		{volumeType: ec2types.VolumeTypeIo2, iops: 256000, size: 255, err: "must be at most 1000 per GiB, got 256000 for 255 GiB"},
+		{volumeType: ec2types.VolumeTypeGp2, iops: 0, size: 100},
+		{volumeType: ec2types.VolumeTypeGp2, iops: 3000, size: 100, err: "cannot be set for gp2 volumes"},
+		{volumeType: ec2types.VolumeTypeSt1, iops: 500, size: 500, err: "cannot be set for st1 volumes"},
+	}
+	for _, g := range grid {
+		t.Run(fmt.Sprintf("%s-%d-%d", g.volumeType, g.iops, g.size), func(t *testing.T) {
+			lt := &LaunchTemplate{
+				Name:           fi.PtrTo("test"),
+				ImageID:        fi.PtrTo("ami-12345678"),
+				RootVolumeType: g.volumeType,
+				RootVolumeIops: fi.PtrTo(g.iops),
+				RootVolumeSize: fi.PtrTo(g.size),
+			}
+			bdm := &LaunchTemplate{
+				Name:    fi.PtrTo("test"),
+				ImageID: fi.PtrTo("ami-12345678"),
+				BlockDeviceMappings: []*BlockDeviceMapping{
+					{
+						DeviceName:    fi.PtrTo("/dev/xvdd"),
+						EbsVolumeType: g.volumeType,
+						EbsVolumeIops: fi.PtrTo(g.iops),
+						EbsVolumeSize: fi.PtrTo(g.size),
+					},
+				},
+			}
+			for _, e := range []*LaunchTemplate{lt, bdm} {
+				err := e.CheckChanges(nil, e, nil)
+				if g.err == "" {
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				} else if err == nil || !strings.Contains(err.Error(), g.err) {
+					t.Errorf("expected error containing %q, got %v", g.err, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLaunchTemplateTerraformRenderUserDataTooLarge(t *testing.T) {
+	cloud := awsup.BuildMockAWSCloud("eu-west-2", "abc")
+	target := terraform.NewTerraformTarget(cloud, "test", t.TempDir(), nil)
+
+	lt := &LaunchTemplate{
+		Name:     fi.PtrTo("nodes.test"),
+		Tags:     map[string]string{"kops.k8s.io/instancegroup": "nodes"},
+		UserData: fi.NewStringResource(strings.Repeat("#", MaxUserDataSize+1)),
+	}
+	err := lt.RenderTerraform(target, lt, lt, lt)
+	if err == nil {
+		t.Fatalf("expected an error for oversized user data")
+	}
+	if !strings.Contains(err.Error(), `instance group "nodes"`) {
+		t.Errorf("expected error to name the instance group, got: %v", err)
+	}
+
+	lt.UserData = fi.NewStringResource(strings.Repeat("#", MaxUserDataSize))
+	if err := lt.RenderTerraform(target, lt, lt, lt); err != nil {
+		t.Errorf("unexpected error for user data at the limit: %v", err)
+	}
+}
+
+func TestLaunchTemplateTerraformRenderSecurityGroupOrder(t *testing.T) {
+	render := func(securityGroups ...string) string {
+		lt := &LaunchTemplate{
+			Name:         fi.PtrTo("test"),
+			InstanceType: fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		}
+		for _, name := range securityGroups {
+			lt.SecurityGroups = append(lt.SecurityGroups, &SecurityGroup{Name: fi.PtrTo(name)})
+		}
+		return renderLaunchTemplateTerraform(t, lt)
+	}
+
+	expected := render("nodes-a", "nodes-b", "nodes-c")
+	if actual := render("nodes-c", "nodes-a", "nodes-b"); actual != expected {
+		t.Errorf("rendering depends on the order of the security groups, expected:\n%s\ngot:\n%s", expected, actual)
+	}
+	if !strings.Contains(expected, "[aws_security_group.nodes-a.id, aws_security_group.nodes-b.id, aws_security_group.nodes-c.id]") {
+		t.Errorf("expected sorted security groups, got:\n%s", expected)
+	}
+}
+
+func TestLaunchTemplateTerraformRenderHTTPProtocolIPv6(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:             fi.PtrTo("test"),
+		InstanceType:     fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		HTTPProtocolIPv6: fi.PtrTo(ec2types.LaunchTemplateInstanceMetadataProtocolIpv6Disabled),
+	}
+	if actual := renderLaunchTemplateTerraform(t, lt); strings.Contains(actual, "http_protocol_ipv6") {
+		t.Errorf("expected http_protocol_ipv6 to be omitted for IPv4-only instances, got:\n%s", actual)
+	}
+
+	lt = &LaunchTemplate{
+		Name:             fi.PtrTo("test"),
+		InstanceType:     fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		IPv6AddressCount: fi.PtrTo(int32(1)),
+		HTTPProtocolIPv6: fi.PtrTo(ec2types.LaunchTemplateInstanceMetadataProtocolIpv6Enabled),
+	}
+	if actual := renderLaunchTemplateTerraform(t, lt); !strings.Contains(actual, `http_protocol_ipv6 = "enabled"`) {
+		t.Errorf("expected http_protocol_ipv6 to be enabled for IPv6 instances, got:\n%s", actual)
+	}
+}
+
+func TestLaunchTemplateTerraformRenderVersionDescription(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:         fi.PtrTo("test"),
+		InstanceType: fi.PtrTo(ec2types.InstanceTypeT2Medium),
+	}
+	if actual := renderLaunchTemplateTerraform(t, lt); strings.Contains(actual, "description") {
+		t.Errorf("expected description to be omitted, got:\n%s", actual)
+	}
+
+	lt = &LaunchTemplate{
+		Name:         fi.PtrTo("test"),
+		InstanceType: fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		Tags:         map[string]string{"KubernetesCluster": "cluster.example.com"},
+	}
+	if actual := renderLaunchTemplateTerraform(t, lt); !strings.Contains(actual, `"Managed by kOps for cluster cluster.example.com"`) {
+		t.Errorf("expected default description naming the cluster, got:\n%s", actual)
+	}
+
+	lt = &LaunchTemplate{
+		Name:               fi.PtrTo("test"),
+		InstanceType:       fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		Tags:               map[string]string{"KubernetesCluster": "cluster.example.com"},
+		VersionDescription: fi.PtrTo("release 42"),
+	}
+	if actual := renderLaunchTemplateTerraform(t, lt); !strings.Contains(actual, `"release 42"`) {
+		t.Errorf("expected explicit description, got:\n%s", actual)
+	}
+}
+
+func TestLaunchTemplateTerraformRenderEphemeralDevices(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:         fi.PtrTo("test"),
+		InstanceType: fi.PtrTo(ec2types.InstanceTypeM3Medium),
+	}
+	actual := renderLaunchTemplateTerraform(t, lt)
+	if !strings.Contains(actual, `"/dev/sdc"`) || !strings.Contains(actual, `"ephemeral0"`) {
+		t.Errorf("expected ephemeral0 mapped to /dev/sdc, got:\n%s", actual)
+	}
+
+	lt = &LaunchTemplate{
+		Name:                 fi.PtrTo("test"),
+		InstanceType:         fi.PtrTo(ec2types.InstanceTypeM3Medium),
+		EphemeralDeviceNames: map[string]string{"ephemeral0": "/dev/sdx"},
+	}
+	actual = renderLaunchTemplateTerraform(t, lt)
+	if strings.Contains(actual, `"/dev/sdc"`) || !strings.Contains(actual, `"/dev/sdx"`) || !strings.Contains(actual, `"ephemeral0"`) {
+		t.Errorf("expected ephemeral0 mapped to /dev/sdx, got:\n%s", actual)
+	}
+
+	lt = &LaunchTemplate{
+		Name:                 fi.PtrTo("test"),
+		InstanceType:         fi.PtrTo(ec2types.InstanceTypeM3Medium),
+		EphemeralDeviceNames: map[string]string{"ephemeral0": ""},
+	}
+	actual = renderLaunchTemplateTerraform(t, lt)
+	if strings.Contains(actual, "ephemeral0") || strings.Contains(actual, "block_device_mappings") {
+		t.Errorf("expected ephemeral0 to be excluded, got:\n%s", actual)
+	}
+}
+
+func TestLaunchTemplateTerraformRenderDefaultVolumeKmsKey(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:                fi.PtrTo("test"),
+		InstanceType:        fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		DefaultVolumeKmsKey: fi.PtrTo("default-key"),
+		BlockDeviceMappings: []*BlockDeviceMapping{
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdd"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(20)),
+				EbsEncrypted:  fi.PtrTo(true),
+			},
+			{
+				DeviceName:    fi.PtrTo("/dev/xvde"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(30)),
+				EbsEncrypted:  fi.PtrTo(true),
+				EbsKmsKey:     fi.PtrTo("device-key"),
+			},
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdf"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(40)),
+				EbsEncrypted:  fi.PtrTo(false),
+			},
+		},
+	}
+	actual := renderLaunchTemplateTerraform(t, lt)
+	if strings.Count(actual, `"default-key"`) != 1 {
+		t.Errorf("expected the volume without a key to inherit the default key, got:\n%s", actual)
+	}
+	if strings.Count(actual, `"device-key"`) != 1 {
+		t.Errorf("expected the volume key to override the default key, got:\n%s", actual)
+	}
+	if strings.Count(actual, "kms_key_id") != 2 {
+		t.Errorf("expected the unencrypted volume to have no key, got:\n%s", actual)
+	}
+	if lt.BlockDeviceMappings[0].EbsKmsKey != nil {
+		t.Errorf("expected the task not to be modified by rendering")
+	}
+}
+
+func TestLaunchTemplateTerraformRenderKmsKeyLink(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:                    fi.PtrTo("test"),
+		InstanceType:            fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		DefaultVolumeKmsKey:     fi.PtrTo("default-key"),
+		DefaultVolumeKmsKeyLink: terraformWriter.LiteralProperty("aws_kms_key", "default", "arn"),
+		BlockDeviceMappings: []*BlockDeviceMapping{
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdd"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(20)),
+				EbsEncrypted:  fi.PtrTo(true),
+			},
+			{
+				DeviceName:    fi.PtrTo("/dev/xvde"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(30)),
+				EbsEncrypted:  fi.PtrTo(true),
+				EbsKmsKeyLink: terraformWriter.LiteralProperty("aws_kms_key", "volumes", "arn"),
+			},
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdf"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(40)),
+				EbsEncrypted:  fi.PtrTo(true),
+				EbsKmsKey:     fi.PtrTo("device-key"),
+			},
+		},
+	}
+	actual := renderLaunchTemplateTerraform(t, lt)
+	if strings.Count(actual, "aws_kms_key.default.arn") != 1 || strings.Contains(actual, `"default-key"`) {
+		t.Errorf("expected the volume without a key to reference the default key, got:\n%s", actual)
+	}
+	if strings.Count(actual, "aws_kms_key.volumes.arn") != 1 {
+		t.Errorf("expected the volume to reference its own key, got:\n%s", actual)
+	}
+	if strings.Count(actual, `"device-key"`) != 1 {
+		t.Errorf("expected the literal key of the volume, got:\n%s", actual)
+	}
+}
+
+func TestLaunchTemplateTerraformRenderGp2Volumes(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:         fi.PtrTo("test"),
+		InstanceType: fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		BlockDeviceMappings: []*BlockDeviceMapping{
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdd"),
+				EbsVolumeType: ec2types.VolumeTypeGp2,
+				EbsVolumeSize: fi.PtrTo(int32(100)),
+			},
+			{
+				DeviceName:    fi.PtrTo("/dev/xvde"),
+				EbsVolumeType: ec2types.VolumeTypeGp2,
+				EbsVolumeSize: fi.PtrTo(int32(2000)),
+			},
+		},
+	}
+
+	// Without the feature flag, gp2 volumes are only warned about
+	actual := renderLaunchTemplateTerraform(t, lt)
+	if strings.Count(actual, `"gp2"`) != 2 || strings.Contains(actual, `"gp3"`) {
+		t.Errorf("expected the gp2 volumes to be kept, got:\n%s", actual)
+	}
+	if strings.Contains(actual, "iops") || strings.Contains(actual, "throughput") {
+		t.Errorf("expected no provisioned performance for gp2 volumes, got:\n%s", actual)
+	}
+
+	featureflag.ParseFlags("+TerraformGp2ToGp3")
+	defer featureflag.ParseFlags("-TerraformGp2ToGp3")
+
+	actual = renderLaunchTemplateTerraform(t, lt)
+	if strings.Contains(actual, `"gp2"`) || strings.Count(actual, `"gp3"`) != 2 {
+		t.Errorf("expected the gp2 volumes to be converted to gp3, got:\n%s", actual)
+	}
+	for _, expected := range []string{"iops                  = 3000", "iops                  = 6000", "throughput            = 125"} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("expected %q in the converted volumes, got:\n%s", expected, actual)
+		}
+	}
+	if lt.BlockDeviceMappings[0].EbsVolumeType != ec2types.VolumeTypeGp2 {
+		t.Errorf("expected the task to be left unchanged, got volume type %q", lt.BlockDeviceMappings[0].EbsVolumeType)
+	}
+}
+
+func TestGp3ScaledThroughput(t *testing.T) {
+	tests := []struct {
+		size     int32
+		perGiB   float64
+		iops     int32
+		expected int32
+	}{
+		{size: 1000, perGiB: 0.5, expected: 500},
+		{size: 2000, perGiB: 0.25, iops: 3000, expected: 500},
+		{size: 100, perGiB: 0.5, expected: 125},
+		{size: 4000, perGiB: 0.5, expected: 750},
+		{size: 4000, perGiB: 0.5, iops: 6000, expected: 1500},
+		{size: 4000, perGiB: 0.5, iops: 8000, expected: 2000},
+		{size: 16000, perGiB: 1, iops: 16000, expected: 2000},
+	}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d GiB at %v MiBps per GiB with %d IOPS", test.size, test.perGiB, test.iops), func(t *testing.T) {
+			if actual := gp3ScaledThroughput(test.size, test.perGiB, test.iops); actual != test.expected {
+				t.Errorf("expected %d, got %d", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLaunchTemplateTerraformRenderVolumeThroughputPerGiB(t *testing.T) {
+	lt := &LaunchTemplate{
+		Name:                   fi.PtrTo("test"),
+		InstanceType:           fi.PtrTo(ec2types.InstanceTypeT2Medium),
+		VolumeThroughputPerGiB: fi.PtrTo(0.5),
+		BlockDeviceMappings: []*BlockDeviceMapping{
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdd"),
+				EbsVolumeType: ec2types.VolumeTypeGp3,
+				EbsVolumeSize: fi.PtrTo(int32(1000)),
+			},
+			{
+				DeviceName:          fi.PtrTo("/dev/xvde"),
+				EbsVolumeType:       ec2types.VolumeTypeGp3,
+				EbsVolumeSize:       fi.PtrTo(int32(1000)),
+				EbsVolumeThroughput: fi.PtrTo(int32(200)),
+			},
+			{
+				DeviceName:    fi.PtrTo("/dev/xvdf"),
+				EbsVolumeType: ec2types.VolumeTypeIo2,
+				EbsVolumeSize: fi.PtrTo(int32(1000)),
+				EbsVolumeIops: fi.PtrTo(int32(5000)),
+			},
+		},
+	}
+	actual := renderLaunchTemplateTerraform(t, lt)
+	if strings.Count(actual, "throughput") != 2 {
+		t.Errorf("expected only the gp3 volumes to have a throughput, got:\n%s", actual)
+	}
+	if !strings.Contains(actual, "throughput            = 500") {
+		t.Errorf("expected the throughput to be scaled with the size, got:\n%s", actual)
+	}
+	if !strings.Contains(actual, "throughput            = 200") {
+		t.Errorf("expected the throughput set for the volume to be kept, got:\n%s", actual)
+	}
+
+	lt.VolumeThroughputPerGiB = fi.PtrTo(0.0)
+	lt.ImageID = fi.PtrTo("ami-12345678")
+	if err := lt.CheckChanges(nil, lt, lt); err == nil {
+		t.Errorf("expected error for a throughput ratio of 0")
+	}
+}
+
+func TestLaunchTemplateCheckChangesVersionDescription(t *testing.T) {
+	lt := &LaunchTemplate{
+		ImageID:            fi.PtrTo("ami-12345678"),
+		VersionDescription: fi.PtrTo(strings.Repeat("a", 256)),
+	}
+	if err := lt.CheckChanges(nil, lt, lt); err == nil {
+		t.Errorf("expected error for a description longer than 255 characters")
+	}
+
+	lt.VersionDescription = fi.PtrTo(strings.Repeat("a", 255))
+	if err := lt.CheckChanges(nil, lt, lt); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// renderLaunchTemplateTerraform renders the launch template and returns the terraform output
+func renderLaunchTemplateTerraform(t *testing.T, lt *LaunchTemplate) string {
+	cloud := awsup.BuildMockAWSCloud("eu-west-2", "abc")
+	outdir := t.TempDir()
+	target := terraform.NewTerraformTarget(cloud, "test", outdir, nil)
+
+	if err := lt.RenderTerraform(target, lt, lt, lt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := target.Finish(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outdir, "kubernetes.tf"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return string(content)
+}
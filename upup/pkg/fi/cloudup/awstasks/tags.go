@@ -39,6 +39,20 @@ func mapEC2TagsToMap(tags []ec2types.Tag) map[string]string {
 	return m
 }
 
+func mapToEC2Tags(tags map[string]string) []ec2types.Tag {
+	if tags == nil {
+		return nil
+	}
+	m := make([]ec2types.Tag, 0)
+	for k, v := range tags {
+		m = append(m, ec2types.Tag{
+			Key:   aws.String(k),
+			Value: aws.String(v),
+		})
+	}
+	return m
+}
+
 func mapIAMTagsToMap(tags []iamtypes.Tag) map[string]string {
 	if tags == nil {
 		return nil
@@ -273,7 +273,24 @@ func getCloudInstancesFromRegion(region string) AWSCloud {
 	return cloud
 }
 
-func loadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
+// Credentials selects the AWS credentials to use, instead of the ones from the environment.
+type Credentials struct {
+	// Profile is the name of the profile in the shared AWS config and credentials files.
+	Profile string
+	// RoleARN is the ARN of an IAM role to assume, taking precedence over KOPS_AWS_ROLE_ARN.
+	RoleARN string
+}
+
+// cloudInstancesKey returns the key of the clouds cached for the region and credentials.
+// Clouds built from the credentials of the environment are only keyed by region.
+func cloudInstancesKey(region string, credentials Credentials) string {
+	if credentials == (Credentials{}) {
+		return region
+	}
+	return region + "|" + credentials.Profile + "|" + credentials.RoleARN
+}
+
+func loadAWSConfig(ctx context.Context, region string, credentials Credentials) (aws.Config, error) {
 	loadOptions := []func(*awsconfig.LoadOptions) error{
 		awsconfig.WithRegion(region),
 		awsconfig.WithClientLogMode(aws.LogRetries),
@@ -287,8 +304,15 @@ func loadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
 		}),
 	}
 
+	if credentials.Profile != "" {
+		loadOptions = append(loadOptions, awsconfig.WithSharedConfigProfile(credentials.Profile))
+	}
+
 	// assumes the role before executing commands
-	roleARN := os.Getenv("KOPS_AWS_ROLE_ARN")
+	roleARN := credentials.RoleARN
+	if roleARN == "" {
+		roleARN = os.Getenv("KOPS_AWS_ROLE_ARN")
+	}
 	if roleARN != "" {
 		cfg, err := awsconfig.LoadDefaultConfig(ctx, loadOptions...)
 		if err != nil {
@@ -304,8 +328,15 @@ func loadAWSConfig(ctx context.Context, region string) (aws.Config, error) {
 }
 
 func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
+	return NewAWSCloudWithCredentials(region, tags, Credentials{})
+}
+
+// NewAWSCloudWithCredentials builds the cloud for the region with the given credentials,
+// falling back to the credentials of the environment for the fields that are not set.
+func NewAWSCloudWithCredentials(region string, tags map[string]string, credentials Credentials) (AWSCloud, error) {
 	ctx := context.TODO()
-	raw := getCloudInstancesFromRegion(region)
+	key := cloudInstancesKey(region, credentials)
+	raw := getCloudInstancesFromRegion(key)
 
 	if raw == nil {
 		c := &awsCloudImplementation{
@@ -315,7 +346,7 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 			},
 		}
 
-		cfg, err := loadAWSConfig(ctx, region)
+		cfg, err := loadAWSConfig(ctx, region, credentials)
 		if err != nil {
 			return c, fmt.Errorf("failed to load default aws config: %w", err)
 		}
@@ -341,7 +372,7 @@ func NewAWSCloud(region string, tags map[string]string) (AWSCloud, error) {
 		c.eventbridge = eventbridge.NewFromConfig(cfg)
 		c.ssm = ssm.NewFromConfig(cfg)
 
-		updateAwsCloudInstances(region, c)
+		updateAwsCloudInstances(key, c)
 
 		raw = c
 	}
@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAWSConfigCredentials(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	if err := os.WriteFile(configFile, []byte("[profile prod]\nregion = eu-west-1\n"), 0o600); err != nil {
+		t.Fatalf("error writing config: %v", err)
+	}
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentialsFile, []byte("[prod]\naws_access_key_id = AKIDPROD\naws_secret_access_key = secret\n"), 0o600); err != nil {
+		t.Fatalf("error writing credentials: %v", err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("KOPS_AWS_ROLE_ARN", "")
+
+	ctx := context.Background()
+	cfg, err := loadAWSConfig(ctx, "us-test-1", Credentials{Profile: "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Region != "us-test-1" {
+		t.Errorf("expected region us-test-1, got %q", cfg.Region)
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error retrieving credentials: %v", err)
+	}
+	if creds.AccessKeyID != "AKIDPROD" {
+		t.Errorf("expected the credentials of the prod profile, got access key %q", creds.AccessKeyID)
+	}
+
+	if _, err := loadAWSConfig(ctx, "us-test-1", Credentials{Profile: "missing"}); err == nil {
+		t.Errorf("expected error for a missing profile")
+	}
+}
+
+func TestCloudInstancesKey(t *testing.T) {
+	if key := cloudInstancesKey("us-test-1", Credentials{}); key != "us-test-1" {
+		t.Errorf("expected clouds using the environment credentials to be keyed by region, got %q", key)
+	}
+
+	keys := map[string]bool{}
+	for _, credentials := range []Credentials{
+		{},
+		{Profile: "prod"},
+		{Profile: "dev"},
+		{RoleARN: "arn:aws:iam::123456789012:role/kops"},
+		{Profile: "prod", RoleARN: "arn:aws:iam::123456789012:role/kops"},
+	} {
+		key := cloudInstancesKey("us-test-1", credentials)
+		if keys[key] {
+			t.Errorf("duplicate key %q for credentials %+v", key, credentials)
+		}
+		keys[key] = true
+	}
+}
@@ -43,8 +43,24 @@ import (
 var allRegions []ec2types.Region
 var allRegionsMutex sync.Mutex
 
+// VolumeIopsLimits are the limits of the provisioned IOPS of each EBS volume type supporting them.
+// All io2 volumes are io2 Block Express volumes, which support far more IOPS than io1 volumes.
+var VolumeIopsLimits = map[ec2types.VolumeType]struct {
+	Min, Max, MaxPerGiB int32
+}{
+	ec2types.VolumeTypeGp3: {Min: 3000, Max: 80000, MaxPerGiB: 500},
+	ec2types.VolumeTypeIo1: {Min: 100, Max: 64000, MaxPerGiB: 50},
+	ec2types.VolumeTypeIo2: {Min: 100, Max: 256000, MaxPerGiB: 1000},
+}
+
 // ValidateRegion checks that an AWS region name is valid
 func ValidateRegion(ctx context.Context, region string) error {
+	return ValidateRegionWithCredentials(ctx, region, Credentials{})
+}
+
+// ValidateRegionWithCredentials checks that the region is a valid EC2 region,
+// listing the regions with the given credentials.
+func ValidateRegionWithCredentials(ctx context.Context, region string, credentials Credentials) error {
 	allRegionsMutex.Lock()
 	defer allRegionsMutex.Unlock()
 
@@ -56,7 +72,7 @@ func ValidateRegion(ctx context.Context, region string) error {
 		if awsRegion == "" {
 			awsRegion = "us-east-1"
 		}
-		cfg, err := loadAWSConfig(ctx, awsRegion)
+		cfg, err := loadAWSConfig(ctx, awsRegion, credentials)
 		if err != nil {
 			return fmt.Errorf("error loading AWS config: %v", err)
 		}
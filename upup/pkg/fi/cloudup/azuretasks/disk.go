@@ -18,6 +18,9 @@ package azuretasks
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	compute "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
@@ -36,6 +39,47 @@ type Disk struct {
 	SizeGB        *int32
 	Tags          map[string]*string
 	Zones         []*string
+	// VolumeType is the storage SKU of the Disk. Defaults to StandardSSD_LRS.
+	// Zone-redundant (ZRS) SKUs replicate the Disk across zones, and cannot be combined with Zones.
+	VolumeType *compute.DiskStorageAccountTypes
+	// MaxShares is the number of VMs the Disk can be attached to at the same time.
+	// Values greater than 1 create a shared disk, which requires an SSD volume type.
+	MaxShares *int32
+	// CreateOption is how the Disk is created: Empty, Copy from the snapshot or disk SourceResourceID,
+	// or Import from the blob SourceURI. Defaults to the option matching the source, or Empty without one.
+	// The source of a Disk cannot be changed once it is created.
+	CreateOption *compute.DiskCreateOption
+	// SourceResourceID is the ID of the snapshot or disk the Disk is copied from.
+	SourceResourceID *string
+	// SourceURI is the URI of the blob the Disk is imported from.
+	SourceURI *string
+	// BurstingEnabled enables on-demand bursting, letting the Disk exceed its provisioned performance for spiky workloads.
+	// It is only supported on Premium SSDs larger than 512 GiB, and can be toggled on an existing Disk.
+	BurstingEnabled *bool
+
+	// attached is set by Find if the Disk is attached to a VM.
+	attached bool
+}
+
+// DefaultDiskVolumeType is the storage SKU of a Disk without a VolumeType.
+const DefaultDiskVolumeType = compute.DiskStorageAccountTypesStandardSSDLRS
+
+// burstingVolumeTypes are the storage SKUs that support on-demand bursting.
+var burstingVolumeTypes = []compute.DiskStorageAccountTypes{
+	compute.DiskStorageAccountTypesPremiumLRS,
+	compute.DiskStorageAccountTypesPremiumZRS,
+}
+
+// maxNonBurstingDiskSizeGB is the size of the largest Premium SSD that does not support on-demand bursting.
+const maxNonBurstingDiskSizeGB = 512
+
+// sharedDiskVolumeTypes are the storage SKUs that support attaching a Disk to multiple VMs.
+var sharedDiskVolumeTypes = []compute.DiskStorageAccountTypes{
+	compute.DiskStorageAccountTypesPremiumLRS,
+	compute.DiskStorageAccountTypesPremiumZRS,
+	compute.DiskStorageAccountTypesStandardSSDLRS,
+	compute.DiskStorageAccountTypesStandardSSDZRS,
+	compute.DiskStorageAccountTypesUltraSSDLRS,
 }
 
 var (
@@ -79,6 +123,22 @@ func (d *Disk) Find(c *fi.CloudupContext) (*Disk, error) {
 	}
 	if found.Properties != nil {
 		disk.SizeGB = found.Properties.DiskSizeGB
+		disk.MaxShares = found.Properties.MaxShares
+		disk.BurstingEnabled = to.Ptr(fi.ValueOf(found.Properties.BurstingEnabled))
+		disk.attached = found.Properties.DiskState != nil && *found.Properties.DiskState != compute.DiskStateUnattached
+	}
+	if found.SKU != nil {
+		disk.VolumeType = found.SKU.Name
+	}
+	if found.Properties != nil && found.Properties.CreationData != nil {
+		creationData := found.Properties.CreationData
+		disk.CreateOption = creationData.CreateOption
+		disk.SourceResourceID = creationData.SourceResourceID
+		disk.SourceURI = creationData.SourceURI
+		// Azure does not preserve the case of resource IDs
+		if disk.SourceResourceID != nil && strings.EqualFold(*disk.SourceResourceID, fi.ValueOf(d.SourceResourceID)) {
+			disk.SourceResourceID = d.SourceResourceID
+		}
 	}
 
 	return disk, nil
@@ -101,16 +161,146 @@ func (*Disk) CheckChanges(a, e, changes *Disk) error {
 		if e.Name == nil {
 			return fi.RequiredField("Name")
 		}
-		return nil
+		if err := e.validateZones(); err != nil {
+			return err
+		}
+		if err := e.validateSource(); err != nil {
+			return err
+		}
+		if err := e.validateBursting(e.SizeGB); err != nil {
+			return err
+		}
+		return e.validateMaxShares()
 	}
 
 	// Check if unchangeable fields won't be changed.
 	if changes.Name != nil {
 		return fi.CannotChangeField("Name")
 	}
+	if changes.CreateOption != nil {
+		return fi.CannotChangeField("CreateOption")
+	}
+	if changes.SourceResourceID != nil {
+		return fi.CannotChangeField("SourceResourceID")
+	}
+	if changes.SourceURI != nil {
+		return fi.CannotChangeField("SourceURI")
+	}
+	if changes.BurstingEnabled != nil || changes.SizeGB != nil || changes.VolumeType != nil {
+		sizeGB := e.SizeGB
+		if sizeGB == nil {
+			sizeGB = a.SizeGB
+		}
+		if err := e.validateBursting(sizeGB); err != nil {
+			return err
+		}
+	}
+	if changes.MaxShares != nil {
+		// Azure only allows changing the number of shares of a detached disk.
+		if a.attached {
+			return fmt.Errorf("cannot change MaxShares of Disk %q from %d to %d while it is attached; detach it from all VMs first",
+				fi.ValueOf(a.Name), fi.ValueOf(a.MaxShares), fi.ValueOf(changes.MaxShares))
+		}
+		return e.validateMaxShares()
+	}
 	return nil
 }
 
+// validateZones checks that zone-redundant Disks are not pinned to zones,
+// and warns about locally-redundant Disks that are not pinned to a zone.
+func (d *Disk) validateZones() error {
+	if d.zoneRedundant() {
+		if len(d.Zones) > 0 {
+			return fmt.Errorf("disk %q of zone-redundant volume type %q cannot have Zones", fi.ValueOf(d.Name), d.volumeType())
+		}
+		return nil
+	}
+	if len(d.Zones) == 0 {
+		klog.Warningf("Disk %q of volume type %q has no zone, so it cannot be attached to VMs in an availability zone", fi.ValueOf(d.Name), d.volumeType())
+	}
+	return nil
+}
+
+// validateSource checks that the Disk has the source its create option requires, and no other.
+func (d *Disk) validateSource() error {
+	if d.SourceResourceID != nil && d.SourceURI != nil {
+		return fmt.Errorf("disk %q cannot have both SourceResourceID and SourceURI", fi.ValueOf(d.Name))
+	}
+	switch createOption := d.createOption(); createOption {
+	case compute.DiskCreateOptionEmpty:
+		if d.SourceResourceID != nil || d.SourceURI != nil {
+			return fmt.Errorf("disk %q with create option %q cannot have a source", fi.ValueOf(d.Name), createOption)
+		}
+	case compute.DiskCreateOptionCopy:
+		if d.SourceResourceID == nil {
+			return fmt.Errorf("disk %q with create option %q requires SourceResourceID", fi.ValueOf(d.Name), createOption)
+		}
+	case compute.DiskCreateOptionImport:
+		if d.SourceURI == nil {
+			return fmt.Errorf("disk %q with create option %q requires SourceURI", fi.ValueOf(d.Name), createOption)
+		}
+	default:
+		return fmt.Errorf("disk %q has unsupported create option %q", fi.ValueOf(d.Name), createOption)
+	}
+	return nil
+}
+
+// createOption returns how the Disk is created, applying the default for its source.
+func (d *Disk) createOption() compute.DiskCreateOption {
+	switch {
+	case d.CreateOption != nil:
+		return *d.CreateOption
+	case d.SourceResourceID != nil:
+		return compute.DiskCreateOptionCopy
+	case d.SourceURI != nil:
+		return compute.DiskCreateOptionImport
+	default:
+		return compute.DiskCreateOptionEmpty
+	}
+}
+
+// zoneRedundant returns true if the storage SKU of the Disk replicates it across zones.
+func (d *Disk) zoneRedundant() bool {
+	return strings.HasSuffix(string(d.volumeType()), "_ZRS")
+}
+
+// validateMaxShares checks that MaxShares is only set for volume types that support shared disks.
+func (d *Disk) validateMaxShares() error {
+	if d.MaxShares == nil {
+		return nil
+	}
+	if *d.MaxShares < 1 {
+		return fmt.Errorf("MaxShares of Disk %q must be at least 1, got %d", fi.ValueOf(d.Name), *d.MaxShares)
+	}
+	if *d.MaxShares > 1 && !slices.Contains(sharedDiskVolumeTypes, d.volumeType()) {
+		return fmt.Errorf("disk %q of volume type %q cannot be shared; MaxShares requires one of %v", fi.ValueOf(d.Name), d.volumeType(), sharedDiskVolumeTypes)
+	}
+	return nil
+}
+
+// validateBursting checks that on-demand bursting is only enabled for the volume types and sizes that support it.
+// The size of a Disk copied or imported from a source may only be known once it is created, so it is not checked then.
+func (d *Disk) validateBursting(sizeGB *int32) error {
+	if !fi.ValueOf(d.BurstingEnabled) {
+		return nil
+	}
+	if !slices.Contains(burstingVolumeTypes, d.volumeType()) {
+		return fmt.Errorf("disk %q of volume type %q does not support on-demand bursting; BurstingEnabled requires one of %v", fi.ValueOf(d.Name), d.volumeType(), burstingVolumeTypes)
+	}
+	if sizeGB != nil && *sizeGB <= maxNonBurstingDiskSizeGB {
+		return fmt.Errorf("disk %q of %d GiB does not support on-demand bursting; BurstingEnabled requires a size larger than %d GiB", fi.ValueOf(d.Name), *sizeGB, maxNonBurstingDiskSizeGB)
+	}
+	return nil
+}
+
+// volumeType returns the storage SKU of the Disk, applying the default.
+func (d *Disk) volumeType() compute.DiskStorageAccountTypes {
+	if d.VolumeType != nil {
+		return *d.VolumeType
+	}
+	return DefaultDiskVolumeType
+}
+
 // RenderAzure creates or updates a Disk.
 func (*Disk) RenderAzure(t *azure.AzureAPITarget, a, e, changes *Disk) error {
 	if a == nil {
@@ -124,12 +314,16 @@ func (*Disk) RenderAzure(t *azure.AzureAPITarget, a, e, changes *Disk) error {
 		Location: to.Ptr(t.Cloud.Region()),
 		Properties: &compute.DiskProperties{
 			CreationData: &compute.CreationData{
-				CreateOption: to.Ptr(compute.DiskCreateOptionEmpty),
+				CreateOption:     to.Ptr(e.createOption()),
+				SourceResourceID: e.SourceResourceID,
+				SourceURI:        e.SourceURI,
 			},
-			DiskSizeGB: e.SizeGB,
+			BurstingEnabled: e.BurstingEnabled,
+			DiskSizeGB:      e.SizeGB,
+			MaxShares:       e.MaxShares,
 		},
 		SKU: &compute.DiskSKU{
-			Name: to.Ptr(compute.DiskStorageAccountTypesStandardSSDLRS),
+			Name: to.Ptr(e.volumeType()),
 		},
 		Tags:  e.Tags,
 		Zones: e.Zones,
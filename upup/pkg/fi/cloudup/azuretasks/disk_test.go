@@ -40,7 +40,8 @@ func newTestDisk() *Disk {
 		ResourceGroup: &ResourceGroup{
 			Name: to.Ptr("rg"),
 		},
-		SizeGB: to.Ptr[int32](32),
+		SizeGB:    to.Ptr[int32](32),
+		MaxShares: to.Ptr[int32](2),
 		Tags: map[string]*string{
 			testTagKey: to.Ptr(testTagValue),
 		},
@@ -66,6 +67,12 @@ func TestDiskRenderAzure(t *testing.T) {
 	if a, e := *actual.Properties.DiskSizeGB, *expected.SizeGB; a != e {
 		t.Fatalf("unexpected disk size: expected %d, but got %d", e, a)
 	}
+	if a, e := *actual.Properties.MaxShares, *expected.MaxShares; a != e {
+		t.Errorf("unexpected max shares: expected %d, but got %d", e, a)
+	}
+	if a, e := *actual.SKU.Name, DefaultDiskVolumeType; a != e {
+		t.Errorf("unexpected volume type: expected %s, but got %s", e, a)
+	}
 	if a, e := actual.Tags, expected.Tags; !reflect.DeepEqual(a, e) {
 		t.Errorf("unexpected tags: expected %v, but got %v", e, a)
 	}
@@ -128,11 +135,47 @@ func TestDiskFind(t *testing.T) {
 	if a, e := *actual.SizeGB, diskSizeGB; a != e {
 		t.Errorf("unexpected disk size: expected %d, but got %d", e, a)
 	}
+	if a := *actual.BurstingEnabled; a {
+		t.Errorf("unexpected bursting: expected disabled, but got enabled")
+	}
 	if a, e := actual.Tags, tags; !reflect.DeepEqual(a, e) {
 		t.Errorf("unexpected tags: expected %v, but got %v", e, a)
 	}
 }
 
+func TestDiskFindSource(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.CloudupContext{
+		T: fi.CloudupSubContext{
+			Cloud: cloud,
+		},
+	}
+	apiTarget := azure.NewAzureAPITarget(cloud)
+
+	expected := newTestDisk()
+	expected.SourceResourceID = to.Ptr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/snapshots/snap")
+	if err := expected.RenderAzure(apiTarget, nil, expected, nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	created := cloud.DisksClient.Disks[*expected.Name]
+	if a, e := *created.Properties.CreationData.CreateOption, compute.DiskCreateOptionCopy; a != e {
+		t.Errorf("unexpected create option: expected %s, but got %s", e, a)
+	}
+	// Azure does not preserve the case of resource IDs.
+	created.Properties.CreationData.SourceResourceID = to.Ptr("/subscriptions/sub/resourceGroups/RG/providers/Microsoft.Compute/snapshots/snap")
+
+	actual, err := expected.Find(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, e := *actual.SourceResourceID, *expected.SourceResourceID; a != e {
+		t.Errorf("unexpected source resource ID: expected %s, but got %s", e, a)
+	}
+	if a, e := *actual.CreateOption, compute.DiskCreateOptionCopy; a != e {
+		t.Errorf("unexpected create option: expected %s, but got %s", e, a)
+	}
+}
+
 func TestDiskRun(t *testing.T) {
 	cloud := NewMockAzureCloud("eastus")
 	ctx := &fi.CloudupContext{
@@ -160,6 +203,33 @@ func TestDiskRun(t *testing.T) {
 	}
 }
 
+func TestDiskRunBursting(t *testing.T) {
+	cloud := NewMockAzureCloud("eastus")
+	ctx := &fi.CloudupContext{
+		T: fi.CloudupSubContext{
+			Cloud: cloud,
+		},
+		Target: azure.NewAzureAPITarget(cloud),
+	}
+
+	disk := newTestDisk()
+	disk.MaxShares = nil
+	disk.SizeGB = to.Ptr[int32](1024)
+	disk.VolumeType = to.Ptr(compute.DiskStorageAccountTypesPremiumLRS)
+	disk.BurstingEnabled = to.Ptr(true)
+	if err := disk.Normalize(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := disk.Run(ctx); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	actual := cloud.DisksClient.Disks[*disk.Name]
+	if a := fi.ValueOf(actual.Properties.BurstingEnabled); !a {
+		t.Errorf("unexpected bursting: expected enabled, but got disabled")
+	}
+}
+
 func TestDiskCheckChanges(t *testing.T) {
 	testCases := []struct {
 		a, e, changes *Disk
@@ -187,6 +257,138 @@ func TestDiskCheckChanges(t *testing.T) {
 			changes: &Disk{Name: to.Ptr("newName")},
 			success: false,
 		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](2)},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](2), VolumeType: to.Ptr(compute.DiskStorageAccountTypesStandardLRS)},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](0)},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumZRS)},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumZRS), Zones: []*string{to.Ptr("1")}},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumLRS), Zones: []*string{to.Ptr("1")}},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SourceResourceID: to.Ptr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/snapshots/snap")},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SourceURI: to.Ptr("https://account.blob.core.windows.net/vhds/disk.vhd")},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SourceResourceID: to.Ptr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/snapshots/snap"), SourceURI: to.Ptr("https://account.blob.core.windows.net/vhds/disk.vhd")},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), CreateOption: to.Ptr(compute.DiskCreateOptionCopy)},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), CreateOption: to.Ptr(compute.DiskCreateOptionImport), SourceResourceID: to.Ptr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/snapshots/snap")},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), CreateOption: to.Ptr(compute.DiskCreateOptionFromImage)},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       &Disk{Name: to.Ptr("name"), CreateOption: to.Ptr(compute.DiskCreateOptionEmpty)},
+			e:       &Disk{Name: to.Ptr("name"), SourceResourceID: to.Ptr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/snapshots/snap")},
+			changes: &Disk{SourceResourceID: to.Ptr("/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Compute/snapshots/snap")},
+			success: false,
+		},
+		{
+			a:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](1)},
+			e:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](3)},
+			changes: &Disk{MaxShares: to.Ptr[int32](3)},
+			success: true,
+		},
+		{
+			a:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](1), attached: true},
+			e:       &Disk{Name: to.Ptr("name"), MaxShares: to.Ptr[int32](3)},
+			changes: &Disk{MaxShares: to.Ptr[int32](3)},
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](1024), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumLRS), BurstingEnabled: to.Ptr(true)},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](512), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumLRS), BurstingEnabled: to.Ptr(true)},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](1024), BurstingEnabled: to.Ptr(true)},
+			changes: nil,
+			success: false,
+		},
+		{
+			a:       nil,
+			e:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](32), BurstingEnabled: to.Ptr(false)},
+			changes: nil,
+			success: true,
+		},
+		{
+			a:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](1024), BurstingEnabled: to.Ptr(false), attached: true},
+			e:       &Disk{Name: to.Ptr("name"), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumZRS), BurstingEnabled: to.Ptr(true)},
+			changes: &Disk{BurstingEnabled: to.Ptr(true)},
+			success: true,
+		},
+		{
+			a:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](256), BurstingEnabled: to.Ptr(false)},
+			e:       &Disk{Name: to.Ptr("name"), VolumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumLRS), BurstingEnabled: to.Ptr(true)},
+			changes: &Disk{BurstingEnabled: to.Ptr(true)},
+			success: false,
+		},
+		{
+			a:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](1024), BurstingEnabled: to.Ptr(true)},
+			e:       &Disk{Name: to.Ptr("name"), SizeGB: to.Ptr[int32](1024), BurstingEnabled: to.Ptr(false)},
+			changes: &Disk{BurstingEnabled: to.Ptr(false)},
+			success: true,
+		},
 	}
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
@@ -198,3 +400,23 @@ func TestDiskCheckChanges(t *testing.T) {
 		})
 	}
 }
+
+func TestDiskZoneRedundant(t *testing.T) {
+	testCases := []struct {
+		volumeType *compute.DiskStorageAccountTypes
+		expected   bool
+	}{
+		{volumeType: nil, expected: false},
+		{volumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumLRS), expected: false},
+		{volumeType: to.Ptr(compute.DiskStorageAccountTypesPremiumZRS), expected: true},
+		{volumeType: to.Ptr(compute.DiskStorageAccountTypesStandardSSDZRS), expected: true},
+	}
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			d := &Disk{Name: to.Ptr("name"), VolumeType: tc.volumeType}
+			if actual := d.zoneRedundant(); actual != tc.expected {
+				t.Errorf("expected %t, but got %t", tc.expected, actual)
+			}
+		})
+	}
+}
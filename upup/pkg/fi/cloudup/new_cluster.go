@@ -1245,11 +1245,6 @@ func setupNetworking(opt *NewClusterOptions, cluster *api.Cluster) error {
 		cluster.Spec.Networking.Canal = &api.CanalNetworkingSpec{}
 	case "kube-router":
 		cluster.Spec.Networking.KubeRouter = &api.KuberouterNetworkingSpec{}
-		if cluster.Spec.KubeProxy == nil {
-			cluster.Spec.KubeProxy = &api.KubeProxyConfig{}
-		}
-		enabled := false
-		cluster.Spec.KubeProxy.Enabled = &enabled
 	case "amazonvpc", "amazon-vpc-routed-eni":
 		cluster.Spec.Networking.AmazonVPC = &api.AmazonVPCNetworkingSpec{}
 	case "cilium", "":
@@ -1266,6 +1261,13 @@ func setupNetworking(opt *NewClusterOptions, cluster *api.Cluster) error {
 		return fmt.Errorf("unknown networking mode %q", opt.Networking)
 	}
 
+	if model.UsesKubeProxyReplacement(cluster) {
+		if cluster.Spec.KubeProxy == nil {
+			cluster.Spec.KubeProxy = &api.KubeProxyConfig{}
+		}
+		cluster.Spec.KubeProxy.Enabled = fi.PtrTo(false)
+	}
+
 	klog.V(4).Infof("networking mode=%s => %s", opt.Networking, fi.DebugAsJsonString(cluster.Spec.Networking))
 
 	return nil
@@ -1621,11 +1623,6 @@ func addCiliumNetwork(cluster *api.Cluster) {
 	cilium := &api.CiliumNetworkingSpec{}
 	cluster.Spec.Networking.Cilium = cilium
 	cilium.EnableNodePort = true
-	if cluster.Spec.KubeProxy == nil {
-		cluster.Spec.KubeProxy = &api.KubeProxyConfig{}
-	}
-	enabled := false
-	cluster.Spec.KubeProxy.Enabled = &enabled
 }
 
 // defaultImage returns the default Image, based on the cloudprovider
@@ -165,6 +165,12 @@ type OpenstackCloud interface {
 	// GetSubnet returns subnet using subnet id
 	GetSubnet(subnetID string) (*subnets.Subnet, error)
 
+	// GetSubnetSegmentID returns the ID of the network segment of a subnet on a routed provider network
+	GetSubnetSegmentID(subnetID string) (string, error)
+
+	// GetSubnetDNSDomain returns the DNS domain of a subnet
+	GetSubnetDNSDomain(subnetID string) (string, error)
+
 	// ListNetworks will return the Neutron networks which match the options
 	ListNetworks(opt networks.ListOptsBuilder) ([]networks.Network, error)
 
@@ -285,7 +291,7 @@ type OpenstackCloud interface {
 	// DeletePool will delete loadbalancer pool
 	DeletePool(poolID string) error
 	ListListeners(opts listeners.ListOpts) ([]listeners.Listener, error)
-	CreateListener(opts listeners.CreateOpts) (*listeners.Listener, error)
+	CreateListener(opts listeners.CreateOptsBuilder) (*listeners.Listener, error)
 
 	// DeleteListener will delete loadbalancer listener
 	DeleteListener(listenerID string) error
@@ -17,6 +17,7 @@ limitations under the License.
 package openstack
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -539,11 +540,77 @@ func listListeners(c OpenstackCloud, opts listeners.ListOpts) (listenerList []li
 	return listenerList, nil
 }
 
-func (c *openstackCloud) CreateListener(opts listeners.CreateOpts) (listener *listeners.Listener, err error) {
+// ListenerCreateOptsWithTLSCiphers adds the TLS cipher suites, which gophercloud does not model, to the options for creating a listener
+type ListenerCreateOptsWithTLSCiphers struct {
+	listeners.CreateOptsBuilder
+	TLSCiphers string
+}
+
+// ToListenerCreateMap implements listeners.CreateOptsBuilder
+func (opts ListenerCreateOptsWithTLSCiphers) ToListenerCreateMap() (map[string]interface{}, error) {
+	base, err := opts.CreateOptsBuilder.ToListenerCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	if opts.TLSCiphers != "" {
+		listener, ok := base["listener"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected listener create options: %v", base)
+		}
+		listener["tls_ciphers"] = opts.TLSCiphers
+	}
+	return base, nil
+}
+
+// ListenerUpdateOptsWithTLSCiphers adds the TLS cipher suites, which gophercloud does not model, to the options for updating a listener
+type ListenerUpdateOptsWithTLSCiphers struct {
+	listeners.UpdateOptsBuilder
+	TLSCiphers *string
+}
+
+// ToListenerUpdateMap implements listeners.UpdateOptsBuilder
+func (opts ListenerUpdateOptsWithTLSCiphers) ToListenerUpdateMap() (map[string]interface{}, error) {
+	base, err := opts.UpdateOptsBuilder.ToListenerUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	if opts.TLSCiphers != nil {
+		listener, ok := base["listener"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected listener update options: %v", base)
+		}
+		listener["tls_ciphers"] = *opts.TLSCiphers
+	}
+	return base, nil
+}
+
+// UpdateListener updates the listener with the given ID.
+// Unlike listeners.Update, it accepts any listeners.UpdateOptsBuilder, such as ListenerUpdateOptsWithTLSCiphers.
+func UpdateListener(client *gophercloud.ServiceClient, id string, opts listeners.UpdateOptsBuilder) (*listeners.Listener, error) {
+	b, err := opts.ToListenerUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	var r listeners.UpdateResult
+	resp, err := client.Put(client.ServiceURL("lbaas", "listeners", id), b, &r.Body, &gophercloud.RequestOpts{
+		OkCodes: []int{200, 202},
+	})
+	_, r.Header, r.Err = gophercloud.ParseResponse(resp, err)
+	return r.Extract()
+}
+
+// IsConflict returns true if the request conflicts with the current state of the resource,
+// such as creating a listener on a port already used on the load balancer
+func IsConflict(err error) bool {
+	var conflict gophercloud.ErrDefault409
+	return errors.As(err, &conflict)
+}
+
+func (c *openstackCloud) CreateListener(opts listeners.CreateOptsBuilder) (listener *listeners.Listener, err error) {
 	return createListener(c, opts)
 }
 
-func createListener(c OpenstackCloud, opts listeners.CreateOpts) (listener *listeners.Listener, err error) {
+func createListener(c OpenstackCloud, opts listeners.CreateOptsBuilder) (listener *listeners.Listener, err error) {
 	if c.LoadBalancerClient() == nil {
 		return nil, fmt.Errorf("loadbalancer support not available in this deployment")
 	}
@@ -551,7 +618,7 @@ func createListener(c OpenstackCloud, opts listeners.CreateOpts) (listener *list
 	done, err := vfs.RetryWithBackoff(readBackoff, func() (bool, error) {
 		listener, err = listeners.Create(c.LoadBalancerClient(), opts).Extract()
 		if err != nil {
-			return false, fmt.Errorf("unabled to create listener: %v", err)
+			return false, fmt.Errorf("unabled to create listener: %w", err)
 		}
 		return true, nil
 	})
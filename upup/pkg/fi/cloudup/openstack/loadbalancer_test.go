@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+)
+
+func TestIsConflict(t *testing.T) {
+	tests := []struct {
+		desc     string
+		err      error
+		expected bool
+	}{
+		{
+			desc:     "conflict",
+			err:      gophercloud.ErrDefault409{},
+			expected: true,
+		},
+		{
+			desc:     "wrapped conflict",
+			err:      fmt.Errorf("unabled to create listener: %w", gophercloud.ErrDefault409{}),
+			expected: true,
+		},
+		{
+			desc: "not found",
+			err:  gophercloud.ErrDefault404{},
+		},
+		{
+			desc: "other error",
+			err:  fmt.Errorf("connection refused"),
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			if actual := IsConflict(testCase.err); actual != testCase.expected {
+				t.Errorf("expected %t, got %t", testCase.expected, actual)
+			}
+		})
+	}
+}
@@ -189,7 +189,7 @@ func (c *MockCloud) CreateLB(opt loadbalancers.CreateOptsBuilder) (*loadbalancer
 	return createLB(c, opt)
 }
 
-func (c *MockCloud) CreateListener(opts listeners.CreateOpts) (listener *listeners.Listener, err error) {
+func (c *MockCloud) CreateListener(opts listeners.CreateOptsBuilder) (listener *listeners.Listener, err error) {
 	return createListener(c, opts)
 }
 
@@ -393,6 +393,14 @@ func (c *MockCloud) GetSubnet(subnetID string) (*subnets.Subnet, error) {
 	return getSubnet(c, subnetID)
 }
 
+func (c *MockCloud) GetSubnetSegmentID(subnetID string) (string, error) {
+	return getSubnetSegmentID(c, subnetID)
+}
+
+func (c *MockCloud) GetSubnetDNSDomain(subnetID string) (string, error) {
+	return getSubnetDNSDomain(c, subnetID)
+}
+
 func (c *MockCloud) ListAvailabilityZones(serviceClient *gophercloud.ServiceClient) (azList []az.AvailabilityZone, err error) {
 	return listAvailabilityZones(c, serviceClient)
 }
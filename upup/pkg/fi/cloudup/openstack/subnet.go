@@ -77,6 +77,122 @@ func getSubnet(c OpenstackCloud, subnetID string) (*subnets.Subnet, error) {
 	}
 }
 
+func (c *openstackCloud) GetSubnetSegmentID(subnetID string) (string, error) {
+	return getSubnetSegmentID(c, subnetID)
+}
+
+func getSubnetSegmentID(c OpenstackCloud, subnetID string) (string, error) {
+	// gophercloud does not model the segment of a subnet
+	var s struct {
+		SegmentID string `json:"segment_id"`
+	}
+	done, err := vfs.RetryWithBackoff(readBackoff, func() (bool, error) {
+		err := subnets.Get(c.NetworkingClient(), subnetID).ExtractIntoStructPtr(&s, "subnet")
+		if err != nil {
+			return false, fmt.Errorf("error retrieving subnet: %v", err)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return "", err
+	} else if done {
+		return s.SegmentID, nil
+	} else {
+		return "", wait.ErrWaitTimeout
+	}
+}
+
+// SubnetCreateOptsWithSegment adds the network segment, which gophercloud does not model, to the options for creating a subnet
+type SubnetCreateOptsWithSegment struct {
+	subnets.CreateOptsBuilder
+	SegmentID string
+}
+
+// ToSubnetCreateMap implements subnets.CreateOptsBuilder
+func (opts SubnetCreateOptsWithSegment) ToSubnetCreateMap() (map[string]interface{}, error) {
+	base, err := opts.CreateOptsBuilder.ToSubnetCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	if opts.SegmentID != "" {
+		subnet, ok := base["subnet"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected subnet create options: %v", base)
+		}
+		subnet["segment_id"] = opts.SegmentID
+	}
+	return base, nil
+}
+
+func (c *openstackCloud) GetSubnetDNSDomain(subnetID string) (string, error) {
+	return getSubnetDNSDomain(c, subnetID)
+}
+
+func getSubnetDNSDomain(c OpenstackCloud, subnetID string) (string, error) {
+	// gophercloud does not model the DNS domain of a subnet
+	var s struct {
+		DNSDomain string `json:"dns_domain"`
+	}
+	done, err := vfs.RetryWithBackoff(readBackoff, func() (bool, error) {
+		err := subnets.Get(c.NetworkingClient(), subnetID).ExtractIntoStructPtr(&s, "subnet")
+		if err != nil {
+			return false, fmt.Errorf("error retrieving subnet: %v", err)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return "", err
+	} else if done {
+		return s.DNSDomain, nil
+	} else {
+		return "", wait.ErrWaitTimeout
+	}
+}
+
+// SubnetCreateOptsWithDNSDomain adds the DNS domain, which gophercloud does not model, to the options for creating a subnet
+type SubnetCreateOptsWithDNSDomain struct {
+	subnets.CreateOptsBuilder
+	DNSDomain string
+}
+
+// ToSubnetCreateMap implements subnets.CreateOptsBuilder
+func (opts SubnetCreateOptsWithDNSDomain) ToSubnetCreateMap() (map[string]interface{}, error) {
+	base, err := opts.CreateOptsBuilder.ToSubnetCreateMap()
+	if err != nil {
+		return nil, err
+	}
+	if opts.DNSDomain != "" {
+		subnet, ok := base["subnet"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected subnet create options: %v", base)
+		}
+		subnet["dns_domain"] = opts.DNSDomain
+	}
+	return base, nil
+}
+
+// SubnetUpdateOptsWithDNSDomain adds the DNS domain, which gophercloud does not model, to the options for updating a subnet
+type SubnetUpdateOptsWithDNSDomain struct {
+	subnets.UpdateOptsBuilder
+	DNSDomain *string
+}
+
+// ToSubnetUpdateMap implements subnets.UpdateOptsBuilder
+func (opts SubnetUpdateOptsWithDNSDomain) ToSubnetUpdateMap() (map[string]interface{}, error) {
+	base, err := opts.UpdateOptsBuilder.ToSubnetUpdateMap()
+	if err != nil {
+		return nil, err
+	}
+	if opts.DNSDomain != nil {
+		subnet, ok := base["subnet"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected subnet update options: %v", base)
+		}
+		subnet["dns_domain"] = *opts.DNSDomain
+	}
+	return base, nil
+}
+
 func (c *openstackCloud) CreateSubnet(opt subnets.CreateOptsBuilder) (*subnets.Subnet, error) {
 	return createSubnet(c, opt)
 }
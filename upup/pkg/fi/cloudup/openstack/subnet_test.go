@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstack
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+)
+
+func TestSubnetCreateOptsWithSegment(t *testing.T) {
+	opts := SubnetCreateOptsWithSegment{
+		CreateOptsBuilder: subnets.CreateOpts{
+			Name:      "subnet",
+			NetworkID: "network-id",
+			IPVersion: gophercloud.IPv4,
+			CIDR:      "192.168.0.0/24",
+		},
+		SegmentID: "segment-id",
+	}
+
+	body, err := opts.ToSubnetCreateMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"subnet": map[string]interface{}{
+			"name":       "subnet",
+			"network_id": "network-id",
+			"ip_version": float64(4),
+			"cidr":       "192.168.0.0/24",
+			"segment_id": "segment-id",
+		},
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected %v, got %v", expected, body)
+	}
+}
+
+func TestSubnetCreateOptsWithDNSDomain(t *testing.T) {
+	opts := SubnetCreateOptsWithDNSDomain{
+		CreateOptsBuilder: SubnetCreateOptsWithSegment{
+			CreateOptsBuilder: subnets.CreateOpts{
+				Name:      "subnet",
+				NetworkID: "network-id",
+				IPVersion: gophercloud.IPv4,
+				CIDR:      "192.168.0.0/24",
+			},
+			SegmentID: "segment-id",
+		},
+		DNSDomain: "example.com.",
+	}
+
+	body, err := opts.ToSubnetCreateMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"subnet": map[string]interface{}{
+			"name":       "subnet",
+			"network_id": "network-id",
+			"ip_version": float64(4),
+			"cidr":       "192.168.0.0/24",
+			"segment_id": "segment-id",
+			"dns_domain": "example.com.",
+		},
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected %v, got %v", expected, body)
+	}
+}
+
+func TestSubnetUpdateOptsWithDNSDomain(t *testing.T) {
+	description := "subnet"
+	dnsDomain := "example.com."
+	opts := SubnetUpdateOptsWithDNSDomain{
+		UpdateOptsBuilder: subnets.UpdateOpts{
+			Description: &description,
+		},
+		DNSDomain: &dnsDomain,
+	}
+
+	body, err := opts.ToSubnetUpdateMap()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"subnet": map[string]interface{}{
+			"description": "subnet",
+			"dns_domain":  "example.com.",
+		},
+	}
+	if !reflect.DeepEqual(body, expected) {
+		t.Errorf("expected %v, got %v", expected, body)
+	}
+}
@@ -18,9 +18,12 @@ package openstacktasks
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 
 	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	v2pools "github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
 	"k8s.io/klog/v2"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
@@ -28,12 +31,58 @@ import (
 
 // +kops:fitask
 type LBListener struct {
-	ID           *string
-	Name         *string
-	Port         *int
-	Pool         *LBPool
-	Lifecycle    fi.Lifecycle
-	AllowedCIDRs []string
+	ID *string
+	// Name is the name of the listener, which can be changed in place, as the listener is also found by its port and protocol on the load balancer
+	Name *string
+	// Description is the description of the listener, and can be changed in place
+	Description *string
+	Port        *int
+	Pool        *LBPool
+	// DefaultPoolName is the name of an existing pool, such as one managed outside of kOps, used as the
+	// default pool of the listener instead of Pool; exactly one of Pool and DefaultPoolName must be set
+	DefaultPoolName *string
+	Lifecycle       fi.Lifecycle
+	AllowedCIDRs    []string
+	// Protocol is the listener protocol, defaulting to TCP
+	Protocol *string
+	// DefaultTLSContainerRef is the certificate served by a TERMINATED_HTTPS listener
+	DefaultTLSContainerRef *string
+	// SNIContainerRefs are additional certificates served by a TERMINATED_HTTPS listener, selected through SNI
+	SNIContainerRefs []string
+	// TLSCiphers is the colon-separated list of OpenSSL cipher suites accepted by a TERMINATED_HTTPS listener
+	TLSCiphers *string
+	// TLSVersions are the TLS protocol versions accepted by a TERMINATED_HTTPS listener
+	TLSVersions []string
+	// InsertHeaders are the headers, such as X-Forwarded-For, inserted by HTTP and TERMINATED_HTTPS listeners
+	// into the requests to the members, with a value of "true" or "false"
+	InsertHeaders map[string]string
+
+	// defaultPool is the pool that DefaultPoolName resolves to
+	defaultPool *LBPool
+}
+
+// validTLSVersions are the TLS protocol versions supported by Octavia
+var validTLSVersions = []listeners.TLSVersion{
+	listeners.TLSVersionSSLv3,
+	listeners.TLSVersionTLSv1,
+	listeners.TLSVersionTLSv1_1,
+	listeners.TLSVersionTLSv1_2,
+	listeners.TLSVersionTLSv1_3,
+}
+
+// validInsertHeaders are the headers Octavia can insert into the requests to the members
+var validInsertHeaders = []string{
+	"X-Forwarded-For",
+	"X-Forwarded-Port",
+	"X-Forwarded-Proto",
+	"X-SSL-Client-Verify",
+	"X-SSL-Client-Has-Cert",
+	"X-SSL-Client-DN",
+	"X-SSL-Client-CN",
+	"X-SSL-Issuer",
+	"X-SSL-Client-SHA1",
+	"X-SSL-Client-Not-Before",
+	"X-SSL-Client-Not-After",
 }
 
 // GetDependencies returns the dependencies of the Instance task
@@ -59,17 +108,37 @@ func (s *LBListener) CompareWithID() *string {
 func NewLBListenerTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle fi.Lifecycle, listener *listeners.Listener, find *LBListener) (*LBListener, error) {
 	// sort for consistent comparison
 	sort.Strings(listener.AllowedCIDRs)
+	sort.Strings(listener.SniContainerRefs)
+	sort.Strings(listener.TLSVersions)
 	listenerTask := &LBListener{
 		ID:           fi.PtrTo(listener.ID),
 		Name:         fi.PtrTo(listener.Name),
+		Description:  fi.PtrTo(listener.Description),
 		Port:         fi.PtrTo(listener.ProtocolPort),
 		AllowedCIDRs: listener.AllowedCIDRs,
 		Lifecycle:    lifecycle,
+		Protocol:     fi.PtrTo(listener.Protocol),
+	}
+	if listener.DefaultTlsContainerRef != "" {
+		listenerTask.DefaultTLSContainerRef = fi.PtrTo(listener.DefaultTlsContainerRef)
+	}
+	if len(listener.SniContainerRefs) > 0 {
+		listenerTask.SNIContainerRefs = listener.SniContainerRefs
+	}
+	if listener.TLSCiphers != "" {
+		listenerTask.TLSCiphers = fi.PtrTo(listener.TLSCiphers)
+	}
+	if len(listener.TLSVersions) > 0 {
+		listenerTask.TLSVersions = listener.TLSVersions
+	}
+	// Octavia reports an empty map for listeners without inserted headers
+	if len(listener.InsertHeaders) > 0 {
+		listenerTask.InsertHeaders = maps.Clone(listener.InsertHeaders)
 	}
 
 	if len(listener.Pools) > 0 {
 		for _, pool := range listener.Pools {
-			poolTask, err := NewLBPoolTaskFromCloud(cloud, lifecycle, &pool, find.Pool)
+			poolTask, err := NewLBPoolTaskFromCloud(cloud, lifecycle, &pool, nil)
 			if err != nil {
 				return nil, fmt.Errorf("NewLBListenerTaskFromCloud: Failed to create new LBListener task for pool %s: %v", pool.Name, err)
 			} else {
@@ -83,17 +152,30 @@ func NewLBListenerTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle fi.Lif
 		if err != nil {
 			return nil, fmt.Errorf("Fail to get pool with ID: %s: %v", listener.DefaultPoolID, err)
 		}
-		poolTask, err := NewLBPoolTaskFromCloud(cloud, lifecycle, pool, find.Pool)
+		poolTask, err := NewLBPoolTaskFromCloud(cloud, lifecycle, pool, nil)
 		if err != nil {
 			return nil, fmt.Errorf("NewLBListenerTaskFromCloud: Failed to create new LBListener task for pool %s: %v", pool.Name, err)
 		}
 		listenerTask.Pool = poolTask
 	}
+	if find != nil && find.DefaultPoolName != nil && listenerTask.Pool != nil {
+		listenerTask.DefaultPoolName = listenerTask.Pool.Name
+	}
 	if find != nil {
-		// Update all search terms
+		// Update all search terms; the name is not a search term once the ID is known, so it can be changed
 		find.ID = listenerTask.ID
-		find.Name = listenerTask.Name
-		find.Pool = listenerTask.Pool
+		// sort for consistent comparison
+		sort.Strings(find.SNIContainerRefs)
+		sort.Strings(find.TLSVersions)
+		// Expect an empty map rather than nil when the spec has no headers but the listener has some,
+		// so that the headers inserted out-of-band are reported as a change and removed
+		if len(find.InsertHeaders) == 0 {
+			if listenerTask.InsertHeaders != nil {
+				find.InsertHeaders = map[string]string{}
+			} else {
+				find.InsertHeaders = nil
+			}
+		}
 	}
 	return listenerTask, nil
 }
@@ -104,38 +186,177 @@ func (s *LBListener) Find(context *fi.CloudupContext) (*LBListener, error) {
 	}
 
 	cloud := context.T.Cloud.(openstack.OpenstackCloud)
-	listenerList, err := cloud.ListListeners(listeners.ListOpts{
-		ID:   fi.ValueOf(s.ID),
+	if err := s.resolveDefaultPool(cloud); err != nil {
+		return nil, err
+	}
+	opts := listeners.ListOpts{
 		Name: fi.ValueOf(s.Name),
-	})
+	}
+	if s.ID != nil {
+		// Listeners found by ID can be renamed
+		opts = listeners.ListOpts{
+			ID: fi.ValueOf(s.ID),
+		}
+	}
+	listenerList, err := cloud.ListListeners(opts)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to list loadbalancer listeners for name %s: %v", fi.ValueOf(s.Name), err)
 	}
-	if len(listenerList) == 0 {
-		return nil, nil
-	}
 	if len(listenerList) > 1 {
 		return nil, fmt.Errorf("Multiple listeners found with name %s", fi.ValueOf(s.Name))
 	}
+	if len(listenerList) == 0 {
+		// The listener may have been created with another name
+		listener, err := findListenerByPort(cloud, s)
+		if err != nil || listener == nil {
+			return nil, err
+		}
+		return NewLBListenerTaskFromCloud(cloud, s.Lifecycle, listener, s)
+	}
 
 	return NewLBListenerTaskFromCloud(cloud, s.Lifecycle, &listenerList[0], s)
 }
 
+// findListenerByPort returns the listener with the port and protocol of the task on its load balancer,
+// or nil if there is none or the load balancer does not exist yet.
+// Octavia allows only one listener per port and protocol on a load balancer.
+func findListenerByPort(cloud openstack.OpenstackCloud, e *LBListener) (*listeners.Listener, error) {
+	pool := e.pool()
+	if e.Port == nil || pool == nil || pool.Loadbalancer == nil || pool.Loadbalancer.ID == nil {
+		return nil, nil
+	}
+	loadbalancerID := fi.ValueOf(pool.Loadbalancer.ID)
+	port := fi.ValueOf(e.Port)
+	protocol := string(e.protocol())
+	listenerList, err := cloud.ListListeners(listeners.ListOpts{
+		LoadbalancerID: loadbalancerID,
+		Protocol:       protocol,
+		ProtocolPort:   port,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list loadbalancer listeners for port %d: %v", port, err)
+	}
+
+	for i := range listenerList {
+		listener := &listenerList[i]
+		if listener.ProtocolPort != port || listener.Protocol != protocol {
+			continue
+		}
+		for _, lb := range listener.Loadbalancers {
+			if lb.ID == loadbalancerID {
+				return listener, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// resolveDefaultPool looks up the pool named by DefaultPoolName, if it has not been resolved yet.
+func (e *LBListener) resolveDefaultPool(cloud openstack.OpenstackCloud) error {
+	if e.DefaultPoolName == nil || e.defaultPool != nil {
+		return nil
+	}
+	name := fi.ValueOf(e.DefaultPoolName)
+	poolList, err := cloud.ListPools(v2pools.ListOpts{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to list pools for name %s: %v", name, err)
+	}
+	if len(poolList) == 0 {
+		return fmt.Errorf("No pool found with name %s", name)
+	}
+	if len(poolList) > 1 {
+		return fmt.Errorf("Multiple pools found with name %s", name)
+	}
+	pool, err := NewLBPoolTaskFromCloud(cloud, e.Lifecycle, &poolList[0], nil)
+	if err != nil {
+		return err
+	}
+	if pool.Loadbalancer == nil {
+		return fmt.Errorf("Pool %s is not attached to a loadbalancer", name)
+	}
+	e.defaultPool = pool
+	return nil
+}
+
+// pool returns the default pool of the listener, from either Pool or DefaultPoolName.
+func (e *LBListener) pool() *LBPool {
+	if e.Pool != nil {
+		return e.Pool
+	}
+	return e.defaultPool
+}
+
+// protocol returns the protocol of the listener, defaulting to TCP.
+func (e *LBListener) protocol() listeners.Protocol {
+	if e.Protocol != nil {
+		return listeners.Protocol(fi.ValueOf(e.Protocol))
+	}
+	return listeners.ProtocolTCP
+}
+
 func (s *LBListener) Run(context *fi.CloudupContext) error {
 	return fi.CloudupDefaultDeltaRunMethod(s, context)
 }
 
 func (_ *LBListener) CheckChanges(a, e, changes *LBListener) error {
+	if e.Pool != nil && e.DefaultPoolName != nil {
+		return fmt.Errorf("Pool and DefaultPoolName cannot both be set")
+	}
+	if fi.ValueOf(e.Protocol) != string(listeners.ProtocolTerminatedHTTPS) {
+		if len(e.SNIContainerRefs) > 0 {
+			return fmt.Errorf("SNIContainerRefs can only be set for %s listeners", listeners.ProtocolTerminatedHTTPS)
+		}
+		if e.DefaultTLSContainerRef != nil {
+			return fmt.Errorf("DefaultTLSContainerRef can only be set for %s listeners", listeners.ProtocolTerminatedHTTPS)
+		}
+		if e.TLSCiphers != nil {
+			return fmt.Errorf("TLSCiphers can only be set for %s listeners", listeners.ProtocolTerminatedHTTPS)
+		}
+		if len(e.TLSVersions) > 0 {
+			return fmt.Errorf("TLSVersions can only be set for %s listeners", listeners.ProtocolTerminatedHTTPS)
+		}
+	} else if e.DefaultTLSContainerRef == nil {
+		return fi.RequiredField("DefaultTLSContainerRef")
+	}
+	if len(e.InsertHeaders) > 0 {
+		protocol := fi.ValueOf(e.Protocol)
+		if protocol != string(listeners.ProtocolHTTP) && protocol != string(listeners.ProtocolTerminatedHTTPS) {
+			return fmt.Errorf("InsertHeaders can only be set for %s and %s listeners", listeners.ProtocolHTTP, listeners.ProtocolTerminatedHTTPS)
+		}
+		var headers []string
+		for header := range e.InsertHeaders {
+			headers = append(headers, header)
+		}
+		sort.Strings(headers)
+		for _, header := range headers {
+			if !slices.Contains(validInsertHeaders, header) {
+				return fmt.Errorf("InsertHeaders must be a subset of %v, got %q", validInsertHeaders, header)
+			}
+			if value := e.InsertHeaders[header]; value != "true" && value != "false" {
+				return fmt.Errorf("InsertHeaders value of %q must be true or false, got %q", header, value)
+			}
+		}
+	}
+	for _, version := range e.TLSVersions {
+		if !slices.Contains(validTLSVersions, listeners.TLSVersion(version)) {
+			return fmt.Errorf("TLSVersions must be a subset of %v, got %q", validTLSVersions, version)
+		}
+	}
 	if a == nil {
 		if e.Name == nil {
 			return fi.RequiredField("Name")
 		}
+		if e.Pool == nil && e.DefaultPoolName == nil {
+			return fmt.Errorf("one of Pool or DefaultPoolName must be set")
+		}
 	} else {
 		if changes.ID != nil {
 			return fi.CannotChangeField("ID")
 		}
-		if changes.Name != nil {
-			return fi.CannotChangeField("Name")
+		if changes.Protocol != nil {
+			return fi.CannotChangeField("Protocol")
 		}
 	}
 	return nil
@@ -147,40 +368,130 @@ func (_ *LBListener) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, chan
 		return err
 	}
 
+	if err := e.resolveDefaultPool(t.Cloud); err != nil {
+		return err
+	}
+
 	if a == nil {
 		klog.V(2).Infof("Creating LB with Name: %q", fi.ValueOf(e.Name))
+		pool := e.pool()
+		protocol := e.protocol()
 		listeneropts := listeners.CreateOpts{
 			Name:           fi.ValueOf(e.Name),
-			DefaultPoolID:  fi.ValueOf(e.Pool.ID),
-			LoadbalancerID: fi.ValueOf(e.Pool.Loadbalancer.ID),
-			Protocol:       listeners.ProtocolTCP,
+			Description:    fi.ValueOf(e.Description),
+			DefaultPoolID:  fi.ValueOf(pool.ID),
+			LoadbalancerID: fi.ValueOf(pool.Loadbalancer.ID),
+			Protocol:       protocol,
 			ProtocolPort:   fi.ValueOf(e.Port),
 		}
 
-		if useVIPACL && (fi.ValueOf(e.Pool.Loadbalancer.Provider) != "ovn") {
+		if len(e.InsertHeaders) > 0 {
+			listeneropts.InsertHeaders = e.InsertHeaders
+		}
+
+		if protocol == listeners.ProtocolTerminatedHTTPS {
+			listeneropts.DefaultTlsContainerRef = fi.ValueOf(e.DefaultTLSContainerRef)
+			listeneropts.SniContainerRefs = e.SNIContainerRefs
+			listeneropts.TLSVersions = tlsVersions(e.TLSVersions)
+		}
+
+		if useVIPACL && (fi.ValueOf(pool.Loadbalancer.Provider) != "ovn") {
 			listeneropts.AllowedCIDRs = e.AllowedCIDRs
 		}
 
-		listener, err := t.Cloud.CreateListener(listeneropts)
+		listener, err := t.Cloud.CreateListener(openstack.ListenerCreateOptsWithTLSCiphers{
+			CreateOptsBuilder: listeneropts,
+			TLSCiphers:        fi.ValueOf(e.TLSCiphers),
+		})
 		if err != nil {
-			return fmt.Errorf("error creating LB listener: %v", err)
+			// A previous partial apply may have created the listener already, taking its port on the load balancer
+			if !openstack.IsConflict(err) {
+				return fmt.Errorf("error creating LB listener: %v", err)
+			}
+			existing, findErr := findListenerByPort(t.Cloud, e)
+			if findErr != nil || existing == nil {
+				return fmt.Errorf("error creating LB listener: %v", err)
+			}
+			klog.Warningf("Adopting existing LB listener %q (%s) after failing to create it: %v", existing.Name, existing.ID, err)
+			e.ID = fi.PtrTo(existing.ID)
+			return nil
 		}
 		e.ID = fi.PtrTo(listener.ID)
 		return nil
-	} else if len(changes.AllowedCIDRs) > 0 {
+	}
+
+	opts, update := updateOptsFromChanges(a, e, changes, useVIPACL)
+	if !update {
+		klog.V(2).Infof("Openstack task LB::RenderOpenstack did nothing")
+		return nil
+	}
+
+	_, err = openstack.UpdateListener(t.Cloud.LoadBalancerClient(), fi.ValueOf(a.ID), opts)
+	if err != nil {
+		return fmt.Errorf("error updating LB listener: %v", err)
+	}
+	return nil
+}
+
+// updateOptsFromChanges builds the options to update an existing listener in place,
+// returning false if none of the changes require an update.
+func updateOptsFromChanges(a, e, changes *LBListener, useVIPACL bool) (listeners.UpdateOptsBuilder, bool) {
+	opts := listeners.UpdateOpts{}
+	update := false
+	if changes.Name != nil {
+		opts.Name = changes.Name
+		update = true
+	}
+	if changes.Description != nil {
+		opts.Description = changes.Description
+		update = true
+	}
+	if changes.Pool != nil || changes.DefaultPoolName != nil {
+		opts.DefaultPoolID = e.pool().ID
+		update = true
+	}
+	if len(changes.AllowedCIDRs) > 0 {
 		if useVIPACL && (fi.ValueOf(a.Pool.Loadbalancer.Provider) != "ovn") {
-			opts := listeners.UpdateOpts{
-				AllowedCIDRs: &changes.AllowedCIDRs,
-			}
-			_, err := listeners.Update(t.Cloud.LoadBalancerClient(), fi.ValueOf(a.ID), opts).Extract()
-			if err != nil {
-				return fmt.Errorf("error updating LB listener: %v", err)
-			}
+			opts.AllowedCIDRs = &changes.AllowedCIDRs
+			update = true
 		} else {
 			klog.V(2).Infof("Openstack Octavia VIPACLs not supported")
 		}
-		return nil
 	}
-	klog.V(2).Infof("Openstack task LB::RenderOpenstack did nothing")
-	return nil
+	// Find expects an empty map when the headers inserted out-of-band must be removed
+	if changes.InsertHeaders != nil {
+		headers := maps.Clone(changes.InsertHeaders)
+		opts.InsertHeaders = &headers
+		update = true
+	}
+	if fi.ValueOf(e.Protocol) == string(listeners.ProtocolTerminatedHTTPS) {
+		if changes.DefaultTLSContainerRef != nil {
+			opts.DefaultTlsContainerRef = changes.DefaultTLSContainerRef
+			update = true
+		}
+		if changes.SNIContainerRefs != nil {
+			opts.SniContainerRefs = &changes.SNIContainerRefs
+			update = true
+		}
+		if changes.TLSVersions != nil {
+			versions := tlsVersions(changes.TLSVersions)
+			opts.TLSVersions = &versions
+			update = true
+		}
+		if changes.TLSCiphers != nil {
+			return openstack.ListenerUpdateOptsWithTLSCiphers{
+				UpdateOptsBuilder: opts,
+				TLSCiphers:        changes.TLSCiphers,
+			}, true
+		}
+	}
+	return opts, update
+}
+
+func tlsVersions(versions []string) []listeners.TLSVersion {
+	var tlsVersions []listeners.TLSVersion
+	for _, version := range versions {
+		tlsVersions = append(tlsVersions, listeners.TLSVersion(version))
+	}
+	return tlsVersions
 }
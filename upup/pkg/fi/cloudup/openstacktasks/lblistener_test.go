@@ -0,0 +1,694 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacktasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/listeners"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/loadbalancers"
+	"github.com/gophercloud/gophercloud/openstack/loadbalancer/v2/pools"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"k8s.io/kops/cloudmock/openstack/mockloadbalancer"
+	"k8s.io/kops/cloudmock/openstack/mocknetworking"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
+)
+
+func Test_LBListener_updateOptsFromChanges(t *testing.T) {
+	tests := []struct {
+		desc           string
+		actual         *LBListener
+		expected       *LBListener
+		expectedOpts   listeners.UpdateOptsBuilder
+		expectedUpdate bool
+	}{
+		{
+			desc: "no changes",
+			actual: &LBListener{
+				ID:   fi.PtrTo("listener-id"),
+				Name: fi.PtrTo("api"),
+				Pool: &LBPool{ID: fi.PtrTo("pool-a")},
+			},
+			expected: &LBListener{
+				ID:   fi.PtrTo("listener-id"),
+				Name: fi.PtrTo("api"),
+				Pool: &LBPool{ID: fi.PtrTo("pool-a")},
+			},
+			expectedOpts:   listeners.UpdateOpts{},
+			expectedUpdate: false,
+		},
+		{
+			desc: "pool changed",
+			actual: &LBListener{
+				ID:   fi.PtrTo("listener-id"),
+				Name: fi.PtrTo("api"),
+				Pool: &LBPool{ID: fi.PtrTo("pool-a")},
+			},
+			expected: &LBListener{
+				ID:   fi.PtrTo("listener-id"),
+				Name: fi.PtrTo("api"),
+				Pool: &LBPool{ID: fi.PtrTo("pool-b")},
+			},
+			expectedOpts: listeners.UpdateOpts{
+				DefaultPoolID: fi.PtrTo("pool-b"),
+			},
+			expectedUpdate: true,
+		},
+		{
+			desc: "name and description changed",
+			actual: &LBListener{
+				ID:          fi.PtrTo("listener-id"),
+				Name:        fi.PtrTo("api"),
+				Description: fi.PtrTo(""),
+				Pool:        &LBPool{ID: fi.PtrTo("pool-a")},
+			},
+			expected: &LBListener{
+				ID:          fi.PtrTo("listener-id"),
+				Name:        fi.PtrTo("api-renamed"),
+				Description: fi.PtrTo("kubernetes api"),
+				Pool:        &LBPool{ID: fi.PtrTo("pool-a")},
+			},
+			expectedOpts: listeners.UpdateOpts{
+				Name:        fi.PtrTo("api-renamed"),
+				Description: fi.PtrTo("kubernetes api"),
+			},
+			expectedUpdate: true,
+		},
+		{
+			desc: "tls versions changed",
+			actual: &LBListener{
+				ID:          fi.PtrTo("listener-id"),
+				Name:        fi.PtrTo("api"),
+				Protocol:    fi.PtrTo("TERMINATED_HTTPS"),
+				TLSVersions: []string{"TLSv1.2", "TLSv1.3"},
+			},
+			expected: &LBListener{
+				ID:          fi.PtrTo("listener-id"),
+				Name:        fi.PtrTo("api"),
+				Protocol:    fi.PtrTo("TERMINATED_HTTPS"),
+				TLSVersions: []string{"TLSv1.3"},
+			},
+			expectedOpts: listeners.UpdateOpts{
+				TLSVersions: &[]listeners.TLSVersion{listeners.TLSVersionTLSv1_3},
+			},
+			expectedUpdate: true,
+		},
+		{
+			desc: "tls ciphers changed",
+			actual: &LBListener{
+				ID:         fi.PtrTo("listener-id"),
+				Name:       fi.PtrTo("api"),
+				Protocol:   fi.PtrTo("TERMINATED_HTTPS"),
+				TLSCiphers: fi.PtrTo("ECDHE-RSA-AES256-GCM-SHA384:ECDHE-RSA-AES128-GCM-SHA256"),
+			},
+			expected: &LBListener{
+				ID:         fi.PtrTo("listener-id"),
+				Name:       fi.PtrTo("api"),
+				Protocol:   fi.PtrTo("TERMINATED_HTTPS"),
+				TLSCiphers: fi.PtrTo("ECDHE-RSA-AES256-GCM-SHA384"),
+			},
+			expectedOpts: openstack.ListenerUpdateOptsWithTLSCiphers{
+				UpdateOptsBuilder: listeners.UpdateOpts{},
+				TLSCiphers:        fi.PtrTo("ECDHE-RSA-AES256-GCM-SHA384"),
+			},
+			expectedUpdate: true,
+		},
+		{
+			desc: "insert headers changed",
+			actual: &LBListener{
+				ID:            fi.PtrTo("listener-id"),
+				Name:          fi.PtrTo("api"),
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{"X-Forwarded-For": "true"},
+			},
+			expected: &LBListener{
+				ID:            fi.PtrTo("listener-id"),
+				Name:          fi.PtrTo("api"),
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{"X-Forwarded-For": "true", "X-Forwarded-Proto": "true"},
+			},
+			expectedOpts: listeners.UpdateOpts{
+				InsertHeaders: &map[string]string{"X-Forwarded-For": "true", "X-Forwarded-Proto": "true"},
+			},
+			expectedUpdate: true,
+		},
+		{
+			desc: "insert headers added out-of-band",
+			actual: &LBListener{
+				ID:            fi.PtrTo("listener-id"),
+				Name:          fi.PtrTo("api"),
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{"X-Forwarded-Port": "true"},
+			},
+			expected: &LBListener{
+				ID:            fi.PtrTo("listener-id"),
+				Name:          fi.PtrTo("api"),
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{},
+			},
+			expectedOpts: listeners.UpdateOpts{
+				InsertHeaders: &map[string]string{},
+			},
+			expectedUpdate: true,
+		},
+		{
+			desc: "default pool name changed",
+			actual: &LBListener{
+				ID:              fi.PtrTo("listener-id"),
+				Name:            fi.PtrTo("api"),
+				DefaultPoolName: fi.PtrTo("external"),
+			},
+			expected: &LBListener{
+				ID:              fi.PtrTo("listener-id"),
+				Name:            fi.PtrTo("api"),
+				DefaultPoolName: fi.PtrTo("external-v2"),
+				defaultPool:     &LBPool{ID: fi.PtrTo("pool-2")},
+			},
+			expectedOpts: listeners.UpdateOpts{
+				DefaultPoolID: fi.PtrTo("pool-2"),
+			},
+			expectedUpdate: true,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			changes := &LBListener{}
+			fi.BuildChanges(testCase.actual, testCase.expected, changes)
+
+			opts, update := updateOptsFromChanges(testCase.actual, testCase.expected, changes, false)
+			if update != testCase.expectedUpdate {
+				t.Errorf("expected update %t, got %t", testCase.expectedUpdate, update)
+			}
+			if !reflect.DeepEqual(opts, testCase.expectedOpts) {
+				t.Errorf("expected opts %+v, got %+v", testCase.expectedOpts, opts)
+			}
+		})
+	}
+}
+
+func Test_LBListener_InsertHeadersDrift(t *testing.T) {
+	tests := []struct {
+		desc          string
+		cloudHeaders  map[string]string
+		insertHeaders map[string]string
+		expectedDrift bool
+	}{
+		{
+			desc:         "no headers",
+			cloudHeaders: map[string]string{},
+		},
+		{
+			desc:          "same headers",
+			cloudHeaders:  map[string]string{"X-Forwarded-Proto": "true", "X-Forwarded-For": "true"},
+			insertHeaders: map[string]string{"X-Forwarded-For": "true", "X-Forwarded-Proto": "true"},
+		},
+		{
+			desc:          "header disabled out-of-band",
+			cloudHeaders:  map[string]string{"X-Forwarded-For": "false"},
+			insertHeaders: map[string]string{"X-Forwarded-For": "true"},
+			expectedDrift: true,
+		},
+		{
+			desc:          "header added out-of-band",
+			cloudHeaders:  map[string]string{"X-Forwarded-For": "true", "X-Forwarded-Port": "true"},
+			insertHeaders: map[string]string{"X-Forwarded-For": "true"},
+			expectedDrift: true,
+		},
+		{
+			desc:          "headers removed out-of-band",
+			cloudHeaders:  map[string]string{},
+			insertHeaders: map[string]string{"X-Forwarded-For": "true"},
+			expectedDrift: true,
+		},
+		{
+			desc:          "headers added out-of-band without headers in the spec",
+			cloudHeaders:  map[string]string{"X-Forwarded-For": "true"},
+			expectedDrift: true,
+		},
+		{
+			desc:          "no headers with empty headers in the spec",
+			cloudHeaders:  map[string]string{},
+			insertHeaders: map[string]string{},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			listener := &listeners.Listener{
+				ID:            "listener-id",
+				Name:          "api",
+				Protocol:      "HTTP",
+				ProtocolPort:  80,
+				Pools:         []pools.Pool{{ID: "pool-id", Name: "api"}},
+				InsertHeaders: testCase.cloudHeaders,
+			}
+			expected := &LBListener{
+				Name:          fi.PtrTo("api"),
+				Protocol:      fi.PtrTo("HTTP"),
+				Port:          fi.PtrTo(80),
+				Pool:          &LBPool{ID: fi.PtrTo("pool-id"), Name: fi.PtrTo("api")},
+				Lifecycle:     fi.LifecycleSync,
+				InsertHeaders: testCase.insertHeaders,
+			}
+			actual, err := NewLBListenerTaskFromCloud(nil, fi.LifecycleSync, listener, expected)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			changes := &LBListener{}
+			if changed := fi.BuildChanges(actual, expected, changes); changed != testCase.expectedDrift {
+				t.Errorf("expected changed %t, got %t", testCase.expectedDrift, changed)
+			}
+			opts, update := updateOptsFromChanges(actual, expected, changes, false)
+			if update != testCase.expectedDrift {
+				t.Errorf("expected drift %t, got %t", testCase.expectedDrift, update)
+			}
+			if testCase.expectedDrift {
+				headers := opts.(listeners.UpdateOpts).InsertHeaders
+				if headers == nil || !reflect.DeepEqual(*headers, expected.InsertHeaders) {
+					t.Errorf("expected headers %v, got %v", expected.InsertHeaders, headers)
+				}
+			}
+		})
+	}
+}
+
+func Test_LBListener_findListenerByPort(t *testing.T) {
+	cloud := openstack.BuildMockOpenstackCloud("us-test1")
+	cloud.MockLBClient = mockloadbalancer.CreateClient()
+
+	for _, opts := range []listeners.CreateOpts{
+		{Name: "api", LoadbalancerID: "lb-1", Protocol: listeners.ProtocolTCP, ProtocolPort: 443},
+		{Name: "api", LoadbalancerID: "lb-2", Protocol: listeners.ProtocolTCP, ProtocolPort: 443},
+		{Name: "dns", LoadbalancerID: "lb-1", Protocol: listeners.ProtocolUDP, ProtocolPort: 53},
+	} {
+		if _, err := cloud.CreateListener(opts); err != nil {
+			t.Fatalf("unexpected error creating listener: %v", err)
+		}
+	}
+
+	tests := []struct {
+		desc           string
+		port           int
+		protocol       *string
+		loadbalancerID string
+		expectedName   string
+	}{
+		{
+			desc:           "listener on the port of the load balancer",
+			port:           443,
+			loadbalancerID: "lb-1",
+			expectedName:   "api",
+		},
+		{
+			desc:           "listener with the protocol on the port of the load balancer",
+			port:           53,
+			protocol:       fi.PtrTo("UDP"),
+			loadbalancerID: "lb-1",
+			expectedName:   "dns",
+		},
+		{
+			desc:           "listener with another protocol",
+			port:           53,
+			loadbalancerID: "lb-1",
+		},
+		{
+			desc:           "listener on another port",
+			port:           8443,
+			loadbalancerID: "lb-1",
+		},
+		{
+			desc:           "listener on another load balancer",
+			port:           443,
+			loadbalancerID: "lb-3",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			e := &LBListener{
+				Name:     fi.PtrTo("kops"),
+				Port:     fi.PtrTo(testCase.port),
+				Protocol: testCase.protocol,
+				Pool:     &LBPool{Loadbalancer: &LB{ID: fi.PtrTo(testCase.loadbalancerID)}},
+			}
+			existing, err := findListenerByPort(cloud, e)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if testCase.expectedName == "" {
+				if existing != nil {
+					t.Errorf("expected no listener, got %s", existing.ID)
+				}
+				return
+			}
+			if existing == nil {
+				t.Fatalf("expected listener to be found")
+			}
+			if existing.Name != testCase.expectedName || existing.Loadbalancers[0].ID != testCase.loadbalancerID {
+				t.Errorf("expected listener %s on %s, got %s on %v", testCase.expectedName, testCase.loadbalancerID, existing.Name, existing.Loadbalancers)
+			}
+		})
+	}
+}
+
+func Test_LBListener_CheckChanges_TLSPolicy(t *testing.T) {
+	tests := []struct {
+		desc          string
+		expected      *LBListener
+		expectedError string
+	}{
+		{
+			desc: "tls policy on https listener",
+			expected: &LBListener{
+				Name:                   fi.PtrTo("api"),
+				Pool:                   &LBPool{Name: fi.PtrTo("api")},
+				Protocol:               fi.PtrTo("TERMINATED_HTTPS"),
+				DefaultTLSContainerRef: fi.PtrTo("https://barbican/v1/containers/api"),
+				TLSCiphers:             fi.PtrTo("ECDHE-RSA-AES256-GCM-SHA384"),
+				TLSVersions:            []string{"TLSv1.2", "TLSv1.3"},
+			},
+		},
+		{
+			desc: "tls ciphers on tcp listener",
+			expected: &LBListener{
+				Name:       fi.PtrTo("api"),
+				Pool:       &LBPool{Name: fi.PtrTo("api")},
+				Protocol:   fi.PtrTo("TCP"),
+				TLSCiphers: fi.PtrTo("ECDHE-RSA-AES256-GCM-SHA384"),
+			},
+			expectedError: "TLSCiphers can only be set for TERMINATED_HTTPS listeners",
+		},
+		{
+			desc: "tls versions on listener without protocol",
+			expected: &LBListener{
+				Name:        fi.PtrTo("api"),
+				Pool:        &LBPool{Name: fi.PtrTo("api")},
+				TLSVersions: []string{"TLSv1.3"},
+			},
+			expectedError: "TLSVersions can only be set for TERMINATED_HTTPS listeners",
+		},
+		{
+			desc: "unknown tls version",
+			expected: &LBListener{
+				Name:                   fi.PtrTo("api"),
+				Pool:                   &LBPool{Name: fi.PtrTo("api")},
+				Protocol:               fi.PtrTo("TERMINATED_HTTPS"),
+				DefaultTLSContainerRef: fi.PtrTo("https://barbican/v1/containers/api"),
+				TLSVersions:            []string{"TLSv1.4"},
+			},
+			expectedError: `got "TLSv1.4"`,
+		},
+		{
+			desc: "insert headers on http listener",
+			expected: &LBListener{
+				Name:          fi.PtrTo("api"),
+				Pool:          &LBPool{Name: fi.PtrTo("api")},
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{"X-Forwarded-For": "true", "X-Forwarded-Proto": "false"},
+			},
+		},
+		{
+			desc: "insert headers on tcp listener",
+			expected: &LBListener{
+				Name:          fi.PtrTo("api"),
+				Pool:          &LBPool{Name: fi.PtrTo("api")},
+				Protocol:      fi.PtrTo("TCP"),
+				InsertHeaders: map[string]string{"X-Forwarded-For": "true"},
+			},
+			expectedError: "InsertHeaders can only be set for HTTP and TERMINATED_HTTPS listeners",
+		},
+		{
+			desc: "unknown insert header",
+			expected: &LBListener{
+				Name:          fi.PtrTo("api"),
+				Pool:          &LBPool{Name: fi.PtrTo("api")},
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{"X-Real-IP": "true"},
+			},
+			expectedError: `got "X-Real-IP"`,
+		},
+		{
+			desc: "invalid insert header value",
+			expected: &LBListener{
+				Name:          fi.PtrTo("api"),
+				Pool:          &LBPool{Name: fi.PtrTo("api")},
+				Protocol:      fi.PtrTo("HTTP"),
+				InsertHeaders: map[string]string{"X-Forwarded-For": "yes"},
+			},
+			expectedError: `must be true or false, got "yes"`,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := (&LBListener{}).CheckChanges(nil, testCase.expected, &LBListener{})
+			if testCase.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("expected error containing %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func Test_LBListener_CheckChanges_DefaultPool(t *testing.T) {
+	tests := []struct {
+		desc          string
+		actual        *LBListener
+		expected      *LBListener
+		expectedError string
+	}{
+		{
+			desc: "pool task",
+			expected: &LBListener{
+				Name: fi.PtrTo("api"),
+				Pool: &LBPool{Name: fi.PtrTo("api")},
+			},
+		},
+		{
+			desc: "pool name",
+			expected: &LBListener{
+				Name:            fi.PtrTo("api"),
+				DefaultPoolName: fi.PtrTo("external"),
+			},
+		},
+		{
+			desc: "pool task and pool name",
+			expected: &LBListener{
+				Name:            fi.PtrTo("api"),
+				Pool:            &LBPool{Name: fi.PtrTo("api")},
+				DefaultPoolName: fi.PtrTo("external"),
+			},
+			expectedError: "Pool and DefaultPoolName cannot both be set",
+		},
+		{
+			desc: "neither pool task nor pool name",
+			expected: &LBListener{
+				Name: fi.PtrTo("api"),
+			},
+			expectedError: "one of Pool or DefaultPoolName must be set",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := (&LBListener{}).CheckChanges(nil, testCase.expected, nil)
+			if testCase.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("expected error containing %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func Test_LBListener_resolveDefaultPool(t *testing.T) {
+	cloud := openstack.BuildMockOpenstackCloud("us-test1")
+	cloud.MockLBClient = mockloadbalancer.CreateClient()
+	cloud.MockNeutronClient = mocknetworking.CreateClient()
+
+	network, err := cloud.CreateNetwork(networks.CreateOpts{Name: "external"})
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	subnet, err := cloud.CreateSubnet(subnets.CreateOpts{Name: "external", NetworkID: network.ID, CIDR: "10.0.0.0/24", IPVersion: 4, EnableDHCP: fi.PtrTo(true)})
+	if err != nil {
+		t.Fatalf("unexpected error creating subnet: %v", err)
+	}
+	lb, err := cloud.CreateLB(loadbalancers.CreateOpts{Name: "external", VipSubnetID: subnet.ID})
+	if err != nil {
+		t.Fatalf("unexpected error creating loadbalancer: %v", err)
+	}
+	for _, opts := range []pools.CreateOpts{
+		{Name: "external", LoadbalancerID: lb.ID, Protocol: pools.ProtocolTCP, LBMethod: pools.LBMethodRoundRobin},
+		{Name: "duplicate", LoadbalancerID: lb.ID, Protocol: pools.ProtocolTCP, LBMethod: pools.LBMethodRoundRobin},
+		{Name: "duplicate", LoadbalancerID: lb.ID, Protocol: pools.ProtocolTCP, LBMethod: pools.LBMethodRoundRobin},
+	} {
+		if _, err := cloud.CreatePool(opts); err != nil {
+			t.Fatalf("unexpected error creating pool: %v", err)
+		}
+	}
+
+	tests := []struct {
+		desc          string
+		poolName      string
+		expectedError string
+	}{
+		{
+			desc:     "existing pool",
+			poolName: "external",
+		},
+		{
+			desc:          "missing pool",
+			poolName:      "missing",
+			expectedError: "No pool found with name missing",
+		},
+		{
+			desc:          "ambiguous pool",
+			poolName:      "duplicate",
+			expectedError: "Multiple pools found with name duplicate",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			e := &LBListener{
+				Name:            fi.PtrTo("api"),
+				DefaultPoolName: fi.PtrTo(testCase.poolName),
+			}
+			err := e.resolveDefaultPool(cloud)
+			if testCase.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+					t.Errorf("expected error containing %q, got %v", testCase.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pool := e.pool()
+			if pool == nil || fi.ValueOf(pool.Name) != testCase.poolName {
+				t.Fatalf("expected pool %s, got %+v", testCase.poolName, pool)
+			}
+			if fi.ValueOf(pool.Loadbalancer.ID) != lb.ID {
+				t.Errorf("expected pool on loadbalancer %s, got %s", lb.ID, fi.ValueOf(pool.Loadbalancer.ID))
+			}
+		})
+	}
+}
+
+func Test_LBListener_Find(t *testing.T) {
+	cloud := openstack.BuildMockOpenstackCloud("us-test1")
+	cloud.MockLBClient = mockloadbalancer.CreateClient()
+	cloud.MockNeutronClient = mocknetworking.CreateClient()
+
+	network, err := cloud.CreateNetwork(networks.CreateOpts{Name: "api"})
+	if err != nil {
+		t.Fatalf("unexpected error creating network: %v", err)
+	}
+	subnet, err := cloud.CreateSubnet(subnets.CreateOpts{Name: "api", NetworkID: network.ID, CIDR: "10.0.0.0/24", IPVersion: 4, EnableDHCP: fi.PtrTo(true)})
+	if err != nil {
+		t.Fatalf("unexpected error creating subnet: %v", err)
+	}
+	lb, err := cloud.CreateLB(loadbalancers.CreateOpts{Name: "api", VipSubnetID: subnet.ID})
+	if err != nil {
+		t.Fatalf("unexpected error creating loadbalancer: %v", err)
+	}
+	pool, err := cloud.CreatePool(pools.CreateOpts{Name: "api", LoadbalancerID: lb.ID, Protocol: pools.ProtocolTCP, LBMethod: pools.LBMethodRoundRobin})
+	if err != nil {
+		t.Fatalf("unexpected error creating pool: %v", err)
+	}
+	listener, err := cloud.CreateListener(listeners.CreateOpts{Name: "api-old", DefaultPoolID: pool.ID, LoadbalancerID: lb.ID, Protocol: listeners.ProtocolTCP, ProtocolPort: 443})
+	if err != nil {
+		t.Fatalf("unexpected error creating listener: %v", err)
+	}
+
+	tests := []struct {
+		desc         string
+		name         string
+		port         int
+		protocol     *string
+		expectedName string
+	}{
+		{
+			desc:         "listener found by name",
+			name:         "api-old",
+			port:         443,
+			expectedName: "api-old",
+		},
+		{
+			desc:         "renamed listener found by port",
+			name:         "api",
+			port:         443,
+			expectedName: "api-old",
+		},
+		{
+			desc: "no listener on the port",
+			name: "api",
+			port: 8443,
+		},
+		{
+			desc:     "listener on the port with another protocol",
+			name:     "api",
+			port:     443,
+			protocol: fi.PtrTo("UDP"),
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			e := &LBListener{
+				Name:      fi.PtrTo(testCase.name),
+				Port:      fi.PtrTo(testCase.port),
+				Protocol:  testCase.protocol,
+				Lifecycle: fi.LifecycleSync,
+				Pool:      &LBPool{ID: fi.PtrTo(pool.ID), Loadbalancer: &LB{ID: fi.PtrTo(lb.ID)}},
+			}
+			context := &fi.CloudupContext{
+				T: fi.CloudupSubContext{
+					Cloud: cloud,
+				},
+			}
+			actual, err := e.Find(context)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if testCase.expectedName == "" {
+				if actual != nil {
+					t.Errorf("expected no listener, got %s", fi.ValueOf(actual.Name))
+				}
+				return
+			}
+			if actual == nil {
+				t.Fatalf("expected listener to be found")
+			}
+			if fi.ValueOf(actual.Name) != testCase.expectedName {
+				t.Errorf("expected listener %s, got %s", testCase.expectedName, fi.ValueOf(actual.Name))
+			}
+			if fi.ValueOf(e.ID) != listener.ID {
+				t.Errorf("expected the ID of the task to be set to %s, got %s", listener.ID, fi.ValueOf(e.ID))
+			}
+		})
+	}
+}
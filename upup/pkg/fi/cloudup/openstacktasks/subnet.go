@@ -18,6 +18,7 @@ package openstacktasks
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/gophercloud/gophercloud"
 	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
@@ -26,15 +27,40 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/openstack"
 )
 
+// SubnetNoGateway is the GatewayIP value for a subnet without a gateway
+const SubnetNoGateway = "none"
+
 // +kops:fitask
 type Subnet struct {
-	ID         *string
-	Name       *string
-	Network    *Network
+	ID      *string
+	Name    *string
+	Network *Network
+	// CIDR is the address range of the subnet. It can be omitted when the subnet
+	// is allocated from a subnet pool, in which case it is chosen by OpenStack.
 	CIDR       *string
 	DNSServers []*string
-	Tag        *string
-	Lifecycle  fi.Lifecycle
+	// SubnetPoolID is the subnet pool the CIDR of the subnet is allocated from.
+	// It cannot be changed once the subnet is created.
+	SubnetPoolID *string
+	// PrefixLen is the prefix length of the CIDR allocated from the subnet pool.
+	// If nil, the default prefix length of the subnet pool is used.
+	PrefixLen *int
+	// GatewayIP overrides the gateway of the subnet; SubnetNoGateway disables the gateway.
+	// If nil, OpenStack assigns the first address of the CIDR as the gateway.
+	GatewayIP *string
+	// Description is the description of the subnet, and can be changed in place.
+	Description *string
+	// SegmentID is the network segment of the subnet on a routed provider network.
+	// It cannot be changed once the subnet is created.
+	SegmentID *string
+	// DNSPublishFixedIP publishes the fixed IPs of the ports on the subnet to the external DNS service (Designate),
+	// and can be changed in place. If nil, the setting of the subnet is left as is.
+	DNSPublishFixedIP *bool
+	// DNSDomain is the DNS domain the DNS records of the ports on the subnet are created in,
+	// and can be changed in place. If nil, the setting of the subnet is left as is.
+	DNSDomain *string
+	Tag       *string
+	Lifecycle fi.Lifecycle
 }
 
 // GetDependencies returns the dependencies of the Port task
@@ -69,22 +95,58 @@ func NewSubnetTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle fi.Lifecyc
 		nameservers[i] = fi.PtrTo(ns)
 	}
 
+	gatewayIP := subnet.GatewayIP
+	if gatewayIP == "" {
+		gatewayIP = SubnetNoGateway
+	}
+
 	tag := ""
 	if find != nil && fi.ArrayContains(subnet.Tags, fi.ValueOf(find.Tag)) {
 		tag = fi.ValueOf(find.Tag)
 	}
 
 	actual := &Subnet{
-		ID:         fi.PtrTo(subnet.ID),
-		Name:       fi.PtrTo(subnet.Name),
-		Network:    networkTask,
-		CIDR:       fi.PtrTo(subnet.CIDR),
-		Lifecycle:  lifecycle,
-		DNSServers: nameservers,
-		Tag:        fi.PtrTo(tag),
+		ID:          fi.PtrTo(subnet.ID),
+		Name:        fi.PtrTo(subnet.Name),
+		Network:     networkTask,
+		CIDR:        fi.PtrTo(subnet.CIDR),
+		Lifecycle:   lifecycle,
+		DNSServers:  nameservers,
+		GatewayIP:   fi.PtrTo(gatewayIP),
+		Description: fi.PtrTo(subnet.Description),
+		Tag:         fi.PtrTo(tag),
+
+		DNSPublishFixedIP: fi.PtrTo(subnet.DNSPublishFixedIP),
+	}
+	// The segment is only looked up when it is specified, as it needs another API call
+	if find != nil && find.SegmentID != nil {
+		segmentID, err := cloud.GetSubnetSegmentID(subnet.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting segment of subnet %s: %v", subnet.ID, err)
+		}
+		actual.SegmentID = fi.PtrTo(segmentID)
+	}
+	// Likewise, the DNS domain is only looked up when it is specified
+	if find != nil && find.DNSDomain != nil {
+		dnsDomain, err := cloud.GetSubnetDNSDomain(subnet.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error getting DNS domain of subnet %s: %v", subnet.ID, err)
+		}
+		actual.DNSDomain = fi.PtrTo(dnsDomain)
+	}
+	if subnet.SubnetPoolID != "" {
+		actual.SubnetPoolID = fi.PtrTo(subnet.SubnetPoolID)
+		if _, ipNet, err := net.ParseCIDR(subnet.CIDR); err == nil {
+			prefixLen, _ := ipNet.Mask.Size()
+			actual.PrefixLen = fi.PtrTo(prefixLen)
+		}
 	}
 	if find != nil {
 		find.ID = actual.ID
+		// The CIDR allocated from the subnet pool is only known once the subnet exists
+		if find.CIDR == nil && find.SubnetPoolID != nil {
+			find.CIDR = actual.CIDR
+		}
 	}
 	return actual, nil
 }
@@ -92,12 +154,13 @@ func NewSubnetTaskFromCloud(cloud openstack.OpenstackCloud, lifecycle fi.Lifecyc
 func (s *Subnet) Find(context *fi.CloudupContext) (*Subnet, error) {
 	cloud := context.T.Cloud.(openstack.OpenstackCloud)
 	opt := subnets.ListOpts{
-		ID:         fi.ValueOf(s.ID),
-		Name:       fi.ValueOf(s.Name),
-		NetworkID:  fi.ValueOf(s.Network.ID),
-		CIDR:       fi.ValueOf(s.CIDR),
-		EnableDHCP: fi.PtrTo(true),
-		IPVersion:  4,
+		ID:           fi.ValueOf(s.ID),
+		Name:         fi.ValueOf(s.Name),
+		NetworkID:    fi.ValueOf(s.Network.ID),
+		CIDR:         fi.ValueOf(s.CIDR),
+		SubnetPoolID: fi.ValueOf(s.SubnetPoolID),
+		EnableDHCP:   fi.PtrTo(true),
+		IPVersion:    4,
 	}
 	rs, err := cloud.ListSubnets(opt)
 	if err != nil {
@@ -123,9 +186,17 @@ func (*Subnet) CheckChanges(a, e, changes *Subnet) error {
 		if e.Network == nil {
 			return fi.RequiredField("Network")
 		}
-		if e.CIDR == nil {
+		if e.CIDR == nil && e.SubnetPoolID == nil {
 			return fi.RequiredField("CIDR")
 		}
+		if e.PrefixLen != nil {
+			if e.SubnetPoolID == nil {
+				return fmt.Errorf("PrefixLen can only be set with SubnetPoolID")
+			}
+			if fi.ValueOf(e.PrefixLen) < 1 || fi.ValueOf(e.PrefixLen) > 32 {
+				return fmt.Errorf("PrefixLen must be between 1 and 32, got %d", fi.ValueOf(e.PrefixLen))
+			}
+		}
 	} else {
 		if changes.Name != nil {
 			return fi.CannotChangeField("Name")
@@ -136,6 +207,15 @@ func (*Subnet) CheckChanges(a, e, changes *Subnet) error {
 		if changes.CIDR != nil {
 			return fi.CannotChangeField("CIDR")
 		}
+		if changes.SegmentID != nil {
+			return fi.CannotChangeField("SegmentID")
+		}
+		if changes.SubnetPoolID != nil {
+			return fi.CannotChangeField("SubnetPoolID")
+		}
+		if changes.PrefixLen != nil {
+			return fi.CannotChangeField("PrefixLen")
+		}
 	}
 	return nil
 }
@@ -144,12 +224,31 @@ func (*Subnet) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *S
 	if a == nil {
 		klog.V(2).Infof("Creating Subnet with name:%q", fi.ValueOf(e.Name))
 
+		if e.CIDR != nil {
+			existing, err := t.Cloud.ListSubnets(subnets.ListOpts{
+				NetworkID: fi.ValueOf(e.Network.ID),
+			})
+			if err != nil {
+				return fmt.Errorf("error listing subnets of network %s: %v", fi.ValueOf(e.Network.ID), err)
+			}
+			if err := checkSubnetOverlap(fi.ValueOf(e.CIDR), existing); err != nil {
+				return fmt.Errorf("cannot create subnet %q: %w", fi.ValueOf(e.Name), err)
+			}
+		}
+
 		opt := subnets.CreateOpts{
-			Name:       fi.ValueOf(e.Name),
-			NetworkID:  fi.ValueOf(e.Network.ID),
-			IPVersion:  gophercloud.IPv4,
-			CIDR:       fi.ValueOf(e.CIDR),
-			EnableDHCP: fi.PtrTo(true),
+			Name:        fi.ValueOf(e.Name),
+			NetworkID:   fi.ValueOf(e.Network.ID),
+			IPVersion:   gophercloud.IPv4,
+			CIDR:        fi.ValueOf(e.CIDR),
+			EnableDHCP:  fi.PtrTo(true),
+			Description: fi.ValueOf(e.Description),
+
+			DNSPublishFixedIP: e.DNSPublishFixedIP,
+		}
+		if e.SubnetPoolID != nil {
+			opt.SubnetPoolID = fi.ValueOf(e.SubnetPoolID)
+			opt.Prefixlen = fi.ValueOf(e.PrefixLen)
 		}
 
 		if len(e.DNSServers) > 0 {
@@ -159,7 +258,23 @@ func (*Subnet) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *S
 			}
 			opt.DNSNameservers = dnsNameSrv
 		}
-		v, err := t.Cloud.CreateSubnet(opt)
+		if e.GatewayIP != nil {
+			opt.GatewayIP = gatewayIPOpt(e.GatewayIP)
+		}
+		var createOpts subnets.CreateOptsBuilder = opt
+		if e.SegmentID != nil {
+			createOpts = openstack.SubnetCreateOptsWithSegment{
+				CreateOptsBuilder: opt,
+				SegmentID:         fi.ValueOf(e.SegmentID),
+			}
+		}
+		if e.DNSDomain != nil {
+			createOpts = openstack.SubnetCreateOptsWithDNSDomain{
+				CreateOptsBuilder: createOpts,
+				DNSDomain:         fi.ValueOf(e.DNSDomain),
+			}
+		}
+		v, err := t.Cloud.CreateSubnet(createOpts)
 		if err != nil {
 			return fmt.Errorf("Error creating subnet: %v", err)
 		}
@@ -181,17 +296,16 @@ func (*Subnet) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *S
 		}
 		client := t.Cloud.NetworkingClient()
 
-		opt := subnets.UpdateOpts{}
-
-		if changes.DNSServers != nil {
-			dnsNameSrv := make([]string, len(e.DNSServers))
-			for i, ns := range e.DNSServers {
-				dnsNameSrv[i] = fi.ValueOf(ns)
+		opt := subnetUpdateOpts(e, changes)
+		var updateOpts subnets.UpdateOptsBuilder = opt
+		if changes.DNSDomain != nil {
+			updateOpts = openstack.SubnetUpdateOptsWithDNSDomain{
+				UpdateOptsBuilder: opt,
+				DNSDomain:         e.DNSDomain,
 			}
-			opt.DNSNameservers = &dnsNameSrv
 		}
-		result := subnets.Update(client, fi.ValueOf(a.ID), opt)
-		klog.Infof("Updated %v", opt)
+		result := subnets.Update(client, fi.ValueOf(a.ID), updateOpts)
+		klog.Infof("Updated %v", updateOpts)
 		if result.Err != nil {
 			return fmt.Errorf("error updating subnet %v: %v", a.ID, result.Err)
 		}
@@ -200,3 +314,55 @@ func (*Subnet) RenderOpenstack(t *openstack.OpenstackAPITarget, a, e, changes *S
 	klog.V(2).Infof("Using an existing Openstack subnet, id=%s", fi.ValueOf(e.ID))
 	return nil
 }
+
+// subnetUpdateOpts returns the options updating the fields of an existing subnet that can be changed in place
+func subnetUpdateOpts(e, changes *Subnet) subnets.UpdateOpts {
+	opt := subnets.UpdateOpts{}
+
+	if changes.DNSServers != nil {
+		dnsNameSrv := make([]string, len(e.DNSServers))
+		for i, ns := range e.DNSServers {
+			dnsNameSrv[i] = fi.ValueOf(ns)
+		}
+		opt.DNSNameservers = &dnsNameSrv
+	}
+	if changes.GatewayIP != nil {
+		opt.GatewayIP = gatewayIPOpt(e.GatewayIP)
+	}
+	if changes.Description != nil {
+		opt.Description = changes.Description
+	}
+	if changes.DNSPublishFixedIP != nil {
+		opt.DNSPublishFixedIP = e.DNSPublishFixedIP
+	}
+	return opt
+}
+
+// gatewayIPOpt converts the GatewayIP of the task to the value expected by the OpenStack API,
+// where an empty string disables the gateway.
+func gatewayIPOpt(gatewayIP *string) *string {
+	if fi.ValueOf(gatewayIP) == SubnetNoGateway {
+		return fi.PtrTo("")
+	}
+	return gatewayIP
+}
+
+// checkSubnetOverlap returns an error naming the first of the existing subnets whose CIDR overlaps with cidr.
+// OpenStack rejects overlapping subnets on the same network, but with an error that does not say which subnet overlaps.
+func checkSubnetOverlap(cidr string, existing []subnets.Subnet) error {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q: %v", cidr, err)
+	}
+	for _, subnet := range existing {
+		_, existingNet, err := net.ParseCIDR(subnet.CIDR)
+		if err != nil {
+			klog.Warningf("ignoring subnet %s with invalid CIDR %q", subnet.ID, subnet.CIDR)
+			continue
+		}
+		if ipNet.Contains(existingNet.IP) || existingNet.Contains(ipNet.IP) {
+			return fmt.Errorf("CIDR %s overlaps with CIDR %s of existing subnet %q (%s)", cidr, subnet.CIDR, subnet.Name, subnet.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openstacktasks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func Test_Subnet_CheckChanges_SubnetPool(t *testing.T) {
+	tests := []struct {
+		desc          string
+		expected      *Subnet
+		expectedError string
+	}{
+		{
+			desc: "fixed cidr",
+			expected: &Subnet{
+				Name:    fi.PtrTo("subnet"),
+				Network: &Network{},
+				CIDR:    fi.PtrTo("10.0.0.0/24"),
+			},
+		},
+		{
+			desc: "allocated from subnet pool",
+			expected: &Subnet{
+				Name:         fi.PtrTo("subnet"),
+				Network:      &Network{},
+				SubnetPoolID: fi.PtrTo("pool"),
+				PrefixLen:    fi.PtrTo(24),
+			},
+		},
+		{
+			desc: "neither cidr nor subnet pool",
+			expected: &Subnet{
+				Name:    fi.PtrTo("subnet"),
+				Network: &Network{},
+			},
+			expectedError: "CIDR",
+		},
+		{
+			desc: "prefix length without subnet pool",
+			expected: &Subnet{
+				Name:      fi.PtrTo("subnet"),
+				Network:   &Network{},
+				CIDR:      fi.PtrTo("10.0.0.0/24"),
+				PrefixLen: fi.PtrTo(24),
+			},
+			expectedError: "PrefixLen can only be set with SubnetPoolID",
+		},
+		{
+			desc: "invalid prefix length",
+			expected: &Subnet{
+				Name:         fi.PtrTo("subnet"),
+				Network:      &Network{},
+				SubnetPoolID: fi.PtrTo("pool"),
+				PrefixLen:    fi.PtrTo(33),
+			},
+			expectedError: "got 33",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			err := (&Subnet{}).CheckChanges(nil, testCase.expected, &Subnet{})
+			if testCase.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("expected error containing %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
+
+func Test_SubnetUpdateOpts(t *testing.T) {
+	tests := []struct {
+		desc     string
+		expected *Subnet
+		changes  *Subnet
+		opts     subnets.UpdateOpts
+	}{
+		{
+			desc:     "no changes",
+			expected: &Subnet{DNSPublishFixedIP: fi.PtrTo(true)},
+			changes:  &Subnet{},
+			opts:     subnets.UpdateOpts{},
+		},
+		{
+			desc:     "enable dns publish fixed ip",
+			expected: &Subnet{DNSPublishFixedIP: fi.PtrTo(true)},
+			changes:  &Subnet{DNSPublishFixedIP: fi.PtrTo(true)},
+			opts:     subnets.UpdateOpts{DNSPublishFixedIP: fi.PtrTo(true)},
+		},
+		{
+			desc:     "disable dns publish fixed ip",
+			expected: &Subnet{DNSPublishFixedIP: fi.PtrTo(false)},
+			changes:  &Subnet{DNSPublishFixedIP: fi.PtrTo(false)},
+			opts:     subnets.UpdateOpts{DNSPublishFixedIP: fi.PtrTo(false)},
+		},
+		{
+			desc:     "remove gateway",
+			expected: &Subnet{GatewayIP: fi.PtrTo(SubnetNoGateway)},
+			changes:  &Subnet{GatewayIP: fi.PtrTo(SubnetNoGateway)},
+			opts:     subnets.UpdateOpts{GatewayIP: fi.PtrTo("")},
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.desc, func(t *testing.T) {
+			opts := subnetUpdateOpts(testCase.expected, testCase.changes)
+			if !reflect.DeepEqual(opts, testCase.opts) {
+				t.Errorf("expected %+v, got %+v", testCase.opts, opts)
+			}
+		})
+	}
+}
+
+func Test_CheckSubnetOverlap(t *testing.T) {
+	existing := []subnets.Subnet{
+		{ID: "subnet-a", Name: "a", CIDR: "10.0.0.0/24"},
+		{ID: "subnet-b", Name: "b", CIDR: "10.0.8.0/21"},
+		{ID: "subnet-v6", Name: "v6", CIDR: "2001:db8::/64"},
+	}
+	tests := []struct {
+		cidr          string
+		expectedError string
+	}{
+		{
+			cidr: "10.0.1.0/24",
+		},
+		{
+			cidr:          "10.0.0.128/25",
+			expectedError: `CIDR 10.0.0.128/25 overlaps with CIDR 10.0.0.0/24 of existing subnet "a" (subnet-a)`,
+		},
+		{
+			cidr:          "10.0.0.0/16",
+			expectedError: `overlaps with CIDR 10.0.0.0/24 of existing subnet "a"`,
+		},
+		{
+			cidr:          "10.0.12.0/24",
+			expectedError: `overlaps with CIDR 10.0.8.0/21 of existing subnet "b"`,
+		},
+		{
+			cidr:          "10.0.0.0",
+			expectedError: "invalid CIDR",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.cidr, func(t *testing.T) {
+			err := checkSubnetOverlap(testCase.cidr, existing)
+			if testCase.expectedError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), testCase.expectedError) {
+				t.Errorf("expected error containing %q, got %v", testCase.expectedError, err)
+			}
+		})
+	}
+}
@@ -120,6 +120,9 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	dest["GetInstanceGroup"] = tf.GetInstanceGroup
 	dest["GetNodeInstanceGroups"] = tf.GetNodeInstanceGroups
 	dest["GetClusterAutoscalerNodeGroups"] = tf.GetClusterAutoscalerNodeGroups
+	dest["GetClusterAutoscalerNodeGroupAutoDiscovery"] = tf.GetClusterAutoscalerNodeGroupAutoDiscovery
+	dest["ClusterAutoscalerMetricsAddress"] = tf.ClusterAutoscalerMetricsAddress
+	dest["ClusterAutoscalerNamespace"] = tf.ClusterAutoscalerNamespace
 	dest["HasHighlyAvailableControlPlane"] = tf.HasHighlyAvailableControlPlane
 	dest["ControlPlaneControllerReplicas"] = tf.ControlPlaneControllerReplicas
 	dest["APIServerNodeRole"] = tf.APIServerNodeRole
@@ -341,6 +344,7 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 	}
 
 	dest["IsIPv6Only"] = tf.IsIPv6Only
+	dest["UseIPv6Masquerade"] = func() bool { return apiModel.UseIPv6Masquerade(cluster) }
 	dest["UseServiceAccountExternalPermissions"] = tf.UseServiceAccountExternalPermissions
 
 	if cluster.Spec.ClusterAutoscaler != nil {
@@ -962,10 +966,12 @@ func (tf *TemplateFunctions) GetClusterAutoscalerNodeGroups() map[string]Cluster
 	groups := make(map[string]ClusterAutoscalerNodeGroup)
 	for _, ig := range tf.KopsModelContext.InstanceGroups {
 		if ig.Spec.Role == kops.InstanceGroupRoleNode && (ig.Spec.Autoscale == nil || fi.ValueOf(ig.Spec.Autoscale)) {
+			// Use the same sizes as the cloud's instance group, so that the scaling bounds match the spec
+			minSize, maxSize := apiModel.InstanceGroupSize(ig)
 			group := ClusterAutoscalerNodeGroup{
 				AutoScale: ig.Spec.Autoscale,
-				MinSize:   fi.ValueOf(ig.Spec.MinSize),
-				MaxSize:   fi.ValueOf(ig.Spec.MaxSize),
+				MinSize:   minSize,
+				MaxSize:   maxSize,
 			}
 			if cluster.GetCloudProvider() == kops.CloudProviderGCE {
 				cloud := tf.cloud.(gce.GCECloud)
@@ -980,6 +986,41 @@ func (tf *TemplateFunctions) GetClusterAutoscalerNodeGroups() map[string]Cluster
 	return groups
 }
 
+// GetClusterAutoscalerNodeGroupAutoDiscovery returns the value of the --node-group-auto-discovery flag,
+// or an empty string if the node groups are configured explicitly.
+func (tf *TemplateFunctions) GetClusterAutoscalerNodeGroupAutoDiscovery() string {
+	cas := tf.Cluster.Spec.ClusterAutoscaler
+	if cas == nil || len(cas.NodeGroupAutoDiscoveryTags) == 0 {
+		return ""
+	}
+
+	var tags []string
+	for key, value := range cas.NodeGroupAutoDiscoveryTags {
+		if value == "" {
+			tags = append(tags, key)
+		} else {
+			tags = append(tags, key+"="+value)
+		}
+	}
+	sort.Strings(tags)
+	return "asg:tag=" + strings.Join(tags, ",")
+}
+
+// ClusterAutoscalerMetricsAddress returns the address the cluster autoscaler serves metrics and health checks on.
+func (tf *TemplateFunctions) ClusterAutoscalerMetricsAddress() string {
+	cas := tf.Cluster.Spec.ClusterAutoscaler
+	return net.JoinHostPort(fi.ValueOf(cas.MetricsAddress), strconv.Itoa(int(fi.ValueOf(cas.MetricsPort))))
+}
+
+// ClusterAutoscalerNamespace returns the namespace the cluster autoscaler keeps its configuration and state in.
+func (tf *TemplateFunctions) ClusterAutoscalerNamespace() string {
+	cas := tf.Cluster.Spec.ClusterAutoscaler
+	if cas.ConfigNamespace == nil {
+		return "kube-system"
+	}
+	return *cas.ConfigNamespace
+}
+
 func (tf *TemplateFunctions) architectureOfAMI(amiID string) string {
 	image, _ := tf.cloud.(awsup.AWSCloud).ResolveImage(amiID)
 	switch image.Architecture {
@@ -23,6 +23,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/cloudmock/aws/mockec2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/featureflag"
@@ -360,3 +361,50 @@ func TestKopsFeatureEnabled(t *testing.T) {
 		})
 	}
 }
+
+func Test_TemplateFunctions_GetClusterAutoscalerNodeGroups(t *testing.T) {
+	tf := &TemplateFunctions{}
+	tf.Cluster = &kops.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "minimal.example.com"},
+		Spec: kops.ClusterSpec{
+			CloudProvider: kops.CloudProviderSpec{
+				AWS: &kops.AWSSpec{},
+			},
+		},
+	}
+	newInstanceGroup := func(name string, role kops.InstanceGroupRole, minSize, maxSize *int32, autoscale *bool) *kops.InstanceGroup {
+		return &kops.InstanceGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: kops.InstanceGroupSpec{
+				Role:      role,
+				MinSize:   minSize,
+				MaxSize:   maxSize,
+				Autoscale: autoscale,
+			},
+		}
+	}
+	tf.InstanceGroups = []*kops.InstanceGroup{
+		newInstanceGroup("control-plane", kops.InstanceGroupRoleControlPlane, nil, nil, nil),
+		newInstanceGroup("nodes-default", kops.InstanceGroupRoleNode, nil, nil, nil),
+		newInstanceGroup("nodes-sized", kops.InstanceGroupRoleNode, fi.PtrTo(int32(0)), fi.PtrTo(int32(10)), fi.PtrTo(true)),
+		newInstanceGroup("nodes-fixed", kops.InstanceGroupRoleNode, fi.PtrTo(int32(3)), fi.PtrTo(int32(3)), fi.PtrTo(false)),
+	}
+
+	expected := map[string]ClusterAutoscalerNodeGroup{
+		"nodes-default": {
+			MinSize: 2,
+			MaxSize: 2,
+			Other:   "nodes-default.minimal.example.com",
+		},
+		"nodes-sized": {
+			AutoScale: fi.PtrTo(true),
+			MinSize:   0,
+			MaxSize:   10,
+			Other:     "nodes-sized.minimal.example.com",
+		},
+	}
+	actual := tf.GetClusterAutoscalerNodeGroups()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("unexpected node groups: expected %+v, got %+v", expected, actual)
+	}
+}
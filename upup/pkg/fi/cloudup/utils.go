@@ -36,7 +36,19 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
 )
 
+// CloudCredentials selects the credentials used to access the cloud of a cluster, per cloud provider,
+// instead of the ones from the environment. The credentials of other cloud providers are ignored.
+type CloudCredentials struct {
+	// AWS are the credentials used on AWS.
+	AWS awsup.Credentials
+}
+
 func BuildCloud(cluster *kops.Cluster) (fi.Cloud, error) {
+	return BuildCloudWithCredentials(cluster, CloudCredentials{})
+}
+
+// BuildCloudWithCredentials builds the cloud of the cluster with the given credentials.
+func BuildCloudWithCredentials(cluster *kops.Cluster, credentials CloudCredentials) (fi.Cloud, error) {
 	var cloud fi.Cloud
 	ctx := context.TODO()
 
@@ -78,14 +90,14 @@ func BuildCloud(cluster *kops.Cluster) (fi.Cloud, error) {
 				return nil, err
 			}
 
-			err = awsup.ValidateRegion(ctx, region)
+			err = awsup.ValidateRegionWithCredentials(ctx, region, credentials.AWS)
 			if err != nil {
 				return nil, err
 			}
 
 			cloudTags := map[string]string{awsup.TagClusterName: cluster.ObjectMeta.Name}
 
-			awsCloud, err := awsup.NewAWSCloud(region, cloudTags)
+			awsCloud, err := awsup.NewAWSCloudWithCredentials(region, cloudTags, credentials.AWS)
 			if err != nil {
 				return nil, err
 			}
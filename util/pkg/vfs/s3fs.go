@@ -32,6 +32,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"k8s.io/klog/v2"
@@ -334,9 +335,9 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 	_, err = client.PutObject(ctx, request)
 	if err != nil {
 		if len(request.ACL) > 0 {
-			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, request.ACL, err)
+			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, request.ACL, explainRegionMismatch(p.bucket, err))
 		}
-		return fmt.Errorf("error writing %s: %v", p, err)
+		return fmt.Errorf("error writing %s: %v", p, explainRegionMismatch(p.bucket, err))
 	}
 
 	return nil
@@ -402,7 +403,7 @@ func (p *S3Path) WriteToWithContext(ctx context.Context, out io.Writer) (int64,
 		if AWSErrorCode(err) == "NoSuchKey" {
 			return 0, os.ErrNotExist
 		}
-		return 0, fmt.Errorf("error fetching %s: %v", p, err)
+		return 0, fmt.Errorf("error fetching %s: %v", p, explainRegionMismatch(p.bucket, err))
 	}
 	defer response.Body.Close()
 
@@ -435,7 +436,7 @@ func (p *S3Path) ReadDir() ([]Path, error) {
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error listing %s: %v", p, err)
+			return nil, fmt.Errorf("error listing %s: %v", p, explainRegionMismatch(p.bucket, err))
 		}
 		for _, o := range page.Contents {
 			key := aws.ToString(o.Key)
@@ -485,7 +486,7 @@ func (p *S3Path) ReadTree(ctx context.Context) ([]Path, error) {
 	for paginator.HasMorePages() {
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("error listing %s: %v", p, err)
+			return nil, fmt.Errorf("error listing %s: %v", p, explainRegionMismatch(p.bucket, err))
 		}
 		for _, o := range page.Contents {
 			key := aws.ToString(o.Key)
@@ -515,7 +516,7 @@ func (p *S3Path) getBucketDetails(ctx context.Context) (*S3BucketDetails, error)
 func (p *S3Path) client(ctx context.Context) (*s3.Client, error) {
 	bucketDetails, err := p.getBucketDetails(ctx)
 	if err != nil {
-		return nil, err
+		return nil, explainRegionMismatch(p.bucket, err)
 	}
 
 	client, err := p.s3Context.getClient(ctx, bucketDetails.region)
@@ -773,3 +774,36 @@ func AWSErrorCode(err error) string {
 	}
 	return ""
 }
+
+// AWSBucketRegion returns the region of the bucket reported by S3 in err, or "" if there is none.
+// S3 reports the region of the bucket in the x-amz-bucket-region header of redirects and region mismatch errors.
+func AWSBucketRegion(err error) string {
+	var responseErr *smithyhttp.ResponseError
+	if errors.As(err, &responseErr) && responseErr.Response != nil && responseErr.Response.Response != nil {
+		return responseErr.Response.Header.Get("X-Amz-Bucket-Region")
+	}
+	return ""
+}
+
+// IsAWSRegionMismatch returns true if err was returned because a request for a bucket was sent to the wrong region.
+func IsAWSRegionMismatch(err error) bool {
+	switch AWSErrorCode(err) {
+	case "PermanentRedirect", "AuthorizationHeaderMalformed", "IllegalLocationConstraintException":
+		return true
+	}
+	return AWSBucketRegion(err) != ""
+}
+
+// explainRegionMismatch tells how to configure the region of the bucket if err is a region mismatch, which S3 reports cryptically.
+// Other errors are returned unchanged.
+func explainRegionMismatch(bucket string, err error) error {
+	if !IsAWSRegionMismatch(err) {
+		return err
+	}
+	if region := AWSBucketRegion(err); region != "" {
+		return fmt.Errorf("bucket %q is in region %q, which does not match the configured region; "+
+			"set AWS_REGION=%s (or S3_REGION=%s when using S3_ENDPOINT) and try again: %w", bucket, region, region, region, err)
+	}
+	return fmt.Errorf("bucket %q is not in the configured region; "+
+		"set AWS_REGION (or S3_REGION when using S3_ENDPOINT) to the region of the bucket and try again: %w", bucket, err)
+}
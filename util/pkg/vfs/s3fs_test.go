@@ -16,7 +16,17 @@ limitations under the License.
 
 package vfs
 
-import "testing"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
 
 func Test_S3Path_Parse(t *testing.T) {
 	grid := []struct {
@@ -64,3 +74,62 @@ func Test_S3Path_Parse(t *testing.T) {
 		}
 	}
 }
+
+func Test_AWSBucketRegion(t *testing.T) {
+	responseError := func(header http.Header, err error) error {
+		return &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: http.StatusMovedPermanently, Header: header}},
+				Err:      err,
+			},
+		}
+	}
+
+	grid := []struct {
+		Name                string
+		Err                 error
+		ExpectedRegion      string
+		ExpectedMismatch    bool
+		ExpectedExplanation string
+	}{
+		{
+			Name: "generic error",
+			Err:  errors.New("connection refused"),
+		},
+		{
+			Name: "access denied",
+			Err:  responseError(http.Header{}, &smithy.GenericAPIError{Code: "AccessDenied"}),
+		},
+		{
+			Name:                "redirect with region",
+			Err:                 fmt.Errorf("reading object: %w", responseError(http.Header{"X-Amz-Bucket-Region": []string{"eu-west-1"}}, &smithy.GenericAPIError{Code: "PermanentRedirect"})),
+			ExpectedRegion:      "eu-west-1",
+			ExpectedMismatch:    true,
+			ExpectedExplanation: `bucket "state" is in region "eu-west-1", which does not match the configured region; set AWS_REGION=eu-west-1`,
+		},
+		{
+			Name:                "malformed authorization header",
+			Err:                 responseError(http.Header{}, &smithy.GenericAPIError{Code: "AuthorizationHeaderMalformed"}),
+			ExpectedMismatch:    true,
+			ExpectedExplanation: `bucket "state" is not in the configured region; set AWS_REGION`,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.Name, func(t *testing.T) {
+			if region := AWSBucketRegion(g.Err); region != g.ExpectedRegion {
+				t.Errorf("expected region %q, got %q", g.ExpectedRegion, region)
+			}
+			if mismatch := IsAWSRegionMismatch(g.Err); mismatch != g.ExpectedMismatch {
+				t.Errorf("expected region mismatch %v, got %v", g.ExpectedMismatch, mismatch)
+			}
+			err := explainRegionMismatch("state", g.Err)
+			if g.ExpectedExplanation == "" {
+				if err != g.Err {
+					t.Errorf("expected error to be unchanged, got %v", err)
+				}
+			} else if !strings.HasPrefix(err.Error(), g.ExpectedExplanation) || !errors.Is(err, g.Err) {
+				t.Errorf("expected error starting with %q and wrapping the original, got %v", g.ExpectedExplanation, err)
+			}
+		})
+	}
+}